@@ -0,0 +1,20 @@
+package common
+
+import "time"
+
+// CacheStatus describes the informer caching one resource type
+type CacheStatus struct {
+	Resource string `json:"resource"`
+	// Dynamic is set for the informers serving the resource tools, the others serve the analyzers
+	Dynamic         bool      `json:"dynamic,omitempty"`
+	Synced          bool      `json:"synced"`
+	StartedAt       time.Time `json:"startedAt"`
+	ResourceVersion string    `json:"resourceVersion,omitempty"`
+	// LastEvent is when the informer last saw an object change
+	LastEvent        *time.Time `json:"lastEvent,omitempty"`
+	LastWatchError   string     `json:"lastWatchError,omitempty"`
+	LastWatchErrorAt *time.Time `json:"lastWatchErrorAt,omitempty"`
+	// Stale is set when the informer is not synced or its watch failed after the last event, reads may then miss
+	// recent changes
+	Stale bool `json:"stale"`
+}
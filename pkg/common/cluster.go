@@ -0,0 +1,58 @@
+package common
+
+type ClusterScanOptions struct {
+	// Namespaces limits the scan, all namespaces are scanned when it is empty
+	Namespaces []string
+	// Concurrency bounds the number of analyzers running at the same time
+	Concurrency int
+	// MinSeverity drops the findings below it from the report
+	MinSeverity string
+}
+
+type ClusterReport struct {
+	Summary    ClusterSummary    `json:"summary"`
+	Namespaces []NamespaceReport `json:"namespaces"`
+	// Errors lists the analyzers that failed, the report covers the others
+	Errors []string `json:"errors,omitempty"`
+	// FailedAnalyzers lists the analyzers that failed and where, like Errors
+	FailedAnalyzers []FailedAnalyzer `json:"failedAnalyzers,omitempty"`
+}
+
+// FailedAnalyzer is an analyzer that failed during a cluster scan, Namespace is empty for cluster scoped analyzers
+type FailedAnalyzer struct {
+	Analyzer  string `json:"analyzer"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type ClusterSummary struct {
+	Namespaces int `json:"namespaces"`
+	Objects    int `json:"objects"`
+	Critical   int `json:"critical"`
+	Warning    int `json:"warning"`
+	Info       int `json:"info"`
+}
+
+type NamespaceReport struct {
+	// Namespace is empty for cluster scoped objects
+	Namespace string         `json:"namespace"`
+	Objects   []ObjectReport `json:"objects"`
+}
+
+// ObjectReport groups the findings of a root object and of the objects it owns
+type ObjectReport struct {
+	Object   string    `json:"object"`
+	Severity string    `json:"severity"`
+	Findings []Finding `json:"findings"`
+}
+
+type Finding struct {
+	ID          string       `json:"id"`
+	Severity    string       `json:"severity"`
+	Text        string       `json:"text"`
+	Remediation *Remediation `json:"remediation,omitempty"`
+	// Subject is the owned object the finding is about as Kind/name, it is empty for the findings of the root object
+	Subject   string `json:"subject,omitempty"`
+	FieldPath string `json:"fieldPath,omitempty"`
+	// Analyzer is the analyzer that reported the finding
+	Analyzer string `json:"analyzer,omitempty"`
+}
@@ -0,0 +1,15 @@
+package common
+
+type DeleteOptions struct {
+	// PropagationPolicy is Foreground, Background or Orphan, the default of the kind is used when it is empty
+	PropagationPolicy string
+	// GracePeriodSeconds overrides the termination grace period, 0 deletes immediately
+	GracePeriodSeconds *int64
+	// UID and ResourceVersion are preconditions, the delete is rejected when the object no longer matches them
+	UID             string
+	ResourceVersion string
+	DryRun          bool
+	// Confirm is the token returned by the preview of a label selector delete, the objects are only deleted when
+	// it matches the objects currently selected
+	Confirm string
+}
@@ -0,0 +1,25 @@
+package common
+
+import "time"
+
+type EventOptions struct {
+	// AllNamespaces lists the events of every namespace instead of the namespace of the request
+	AllNamespaces bool
+	// Type keeps the events of this type, Normal or Warning
+	Type string
+	// Reason keeps the events with this reason, e.g. BackOff
+	Reason string
+	// Since drops the events last seen before this window, all events are kept when it is 0
+	Since time.Duration
+}
+
+// Event is an event of core/v1 or events.k8s.io/v1, repeated events are collapsed into one with their count
+type Event struct {
+	Type      string          `json:"type"`
+	Reason    string          `json:"reason"`
+	Message   string          `json:"message"`
+	Object    ObjectReference `json:"object"`
+	Count     int32           `json:"count"`
+	FirstSeen time.Time       `json:"firstSeen"`
+	LastSeen  time.Time       `json:"lastSeen"`
+}
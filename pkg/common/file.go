@@ -0,0 +1,22 @@
+package common
+
+type PodFileOptions struct {
+	Container string
+	Path      string
+	// Offset and Length select the byte range to read, a zero Length reads up to the size limit
+	Offset int64
+	Length int64
+	// Content and Encoding are only used when writing, Encoding is text or base64
+	Content  string
+	Encoding string
+}
+
+type PodFileContent struct {
+	Path      string `json:"path"`
+	Size      int64  `json:"size"`
+	Offset    int64  `json:"offset"`
+	Length    int64  `json:"length"`
+	Encoding  string `json:"encoding"`
+	Content   string `json:"content"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
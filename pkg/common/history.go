@@ -0,0 +1,35 @@
+package common
+
+import "time"
+
+// FindingRecord is the history of one finding of one object across cluster scans
+type FindingRecord struct {
+	Namespace string `json:"namespace,omitempty"`
+	Object    string `json:"object"`
+	// Subject is the owned object the finding is about, it is empty for the findings of the object itself
+	Subject   string `json:"subject,omitempty"`
+	FieldPath string `json:"fieldPath,omitempty"`
+	ID        string `json:"id,omitempty"`
+	Severity  string `json:"severity"`
+	Text      string `json:"text"`
+	// Analyzer is the analyzer that reported the finding, it is empty for the findings recorded by older versions
+	Analyzer  string    `json:"analyzer,omitempty"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+	// OpenedAt is when the finding was last reported after not being reported, it equals FirstSeen until the
+	// finding is resolved and comes back
+	OpenedAt time.Time `json:"openedAt"`
+	// ResolvedAt is set when a scan stops reporting the finding and cleared when it comes back
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+	// Reopened lists when the finding came back after being resolved, the oldest entries are dropped
+	Reopened []time.Time `json:"reopened,omitempty"`
+}
+
+// FlappingObject is an object whose findings were resolved and reported again
+type FlappingObject struct {
+	Namespace string `json:"namespace,omitempty"`
+	Object    string `json:"object"`
+	// Flaps counts the times findings of the object came back in the queried window
+	Flaps    int             `json:"flaps"`
+	Findings []FindingRecord `json:"findings"`
+}
@@ -0,0 +1,20 @@
+package common
+
+// DeleteImpact describes what a delete removes, it is shown to the user confirming the delete
+type DeleteImpact struct {
+	Objects []ObjectReference `json:"objects"`
+	// Dependents are the objects owned by Objects that the garbage collector deletes with them
+	Dependents []ObjectReference `json:"dependents,omitempty"`
+	// Pods counts the pods among Objects and Dependents, or in the namespaces being deleted
+	Pods int `json:"pods"`
+	// Token identifies the deleted objects, it is the confirmation token of a label selector delete
+	Token string `json:"token"`
+}
+
+// ScaleImpact describes the current state of an object being scaled
+type ScaleImpact struct {
+	Kind            string `json:"kind"`
+	CurrentReplicas int64  `json:"currentReplicas"`
+	// Pods counts the pods selected by the scale selector
+	Pods int `json:"pods"`
+}
@@ -0,0 +1,30 @@
+package common
+
+const (
+	PatchTypeJSON      = "json"
+	PatchTypeMerge     = "merge"
+	PatchTypeStrategic = "strategic"
+	PatchTypeApply     = "apply"
+)
+
+type PatchOptions struct {
+	// Type is one of PatchTypeJSON, PatchTypeMerge, PatchTypeStrategic or PatchTypeApply
+	Type string
+	// Patch is the patch document in JSON or YAML
+	Patch string
+	// DryRun sends the patch with a server-side dry-run, the object is not changed
+	DryRun bool
+	// Force takes over the fields of an apply patch owned by other field managers
+	Force bool
+}
+
+// MetadataOptions changes the labels or annotations of an object
+type MetadataOptions struct {
+	// Set maps the keys to add or change to their values
+	Set map[string]string
+	// Remove lists the keys to delete, keys the object does not have are ignored
+	Remove []string
+	// Overwrite allows changing the value of an existing key, without it the call fails instead
+	Overwrite bool
+	DryRun    bool
+}
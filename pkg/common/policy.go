@@ -0,0 +1,28 @@
+package common
+
+// PolicyRules is the content of the rules file passed with --rules.
+type PolicyRules struct {
+	Rules []PolicyRule `json:"rules"`
+}
+
+// PolicyRule checks every object of one kind with a CEL expression, objects the expression evaluates to false
+// for are reported as failures.
+type PolicyRule struct {
+	// ID identifies the rule in the failures it reports, e.g. DEPLOYMENT_TEAM_LABEL
+	ID string `json:"id"`
+	// APIVersion and Kind select the objects the rule applies to, custom resources included
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// Namespaces limits the rule to some namespaces, it applies to all of them when empty
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Expression is a CEL expression over the unstructured object bound to `object`, it must evaluate to true
+	// for compliant objects
+	Expression string `json:"expression"`
+	// Severity is one of SeverityCritical, SeverityWarning or SeverityInfo, it defaults to SeverityWarning
+	Severity string `json:"severity,omitempty"`
+	Message  string `json:"message,omitempty"`
+	// MessageExpression is a CEL expression returning the message, it takes precedence over Message
+	MessageExpression string `json:"messageExpression,omitempty"`
+	FieldPath         string `json:"fieldPath,omitempty"`
+	Remediation       string `json:"remediation,omitempty"`
+}
@@ -0,0 +1,24 @@
+package common
+
+type ProbeOptions struct {
+	// Scheme is one of http, https or tcp
+	Scheme  string
+	Port    string
+	Path    string
+	Timeout int
+}
+
+type ProbeResult struct {
+	Target     string            `json:"target"`
+	Pod        string            `json:"pod"`
+	Scheme     string            `json:"scheme"`
+	Port       int32             `json:"port"`
+	Success    bool              `json:"success"`
+	StatusCode int               `json:"statusCode,omitempty"`
+	Status     string            `json:"status,omitempty"`
+	Latency    string            `json:"latency"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	Truncated  bool              `json:"truncated,omitempty"`
+	Error      string            `json:"error,omitempty"`
+}
@@ -0,0 +1,10 @@
+package common
+
+type ScaleOptions struct {
+	Replicas int32
+	// CurrentReplicas is a precondition, the scale is rejected when the current replicas differ
+	CurrentReplicas *int32
+	// Wait blocks until the scaled replicas are ready or Timeout seconds have passed
+	Wait    bool
+	Timeout int
+}
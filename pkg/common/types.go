@@ -4,6 +4,8 @@ import (
 	"context"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	networkv1 "k8s.io/api/networking/v1"
 )
@@ -17,30 +19,94 @@ type PreAnalysis struct {
 	Ingress        networkv1.Ingress
 	StatefulSet    appsv1.StatefulSet
 	Node           v1.Node
+	DaemonSet      appsv1.DaemonSet
+	Job            batchv1.Job
+	HPA            autoscalingv2.HorizontalPodAutoscaler
 }
 type Result struct {
-	Kind         string    `json:"kind"`
-	Name         string    `json:"name"`
-	Error        []Failure `json:"error"`
-	Details      string    `json:"details"`
-	ParentObject string    `json:"parentObject"`
+	Kind  string    `json:"kind"`
+	Name  string    `json:"name"`
+	Error []Failure `json:"error"`
+	// Severity is the highest severity of the failures
+	Severity string `json:"severity"`
+	Details  string `json:"details"`
+	// ParentObject is the root owner of the object as Kind/name, it is the last entry of OwnerChain. A cluster scoped
+	// object belonging to a namespaced one names it as Kind/namespace/name
+	ParentObject string `json:"parentObject"`
+	// OwnerChain lists the owners of the object from its controller up to the root owner,
+	// e.g. ReplicaSet, Deployment then an Argo Rollout for a pod
+	OwnerChain []ObjectReference `json:"ownerChain,omitempty"`
+	// Analyzer is the analyzer that reported the result, it is only set by a cluster scan
+	Analyzer string `json:"analyzer,omitempty"`
 }
 
+// AnalysisReport is the structured content returned by the analyze tools
+type AnalysisReport struct {
+	Analyzer string          `json:"analyzer"`
+	Summary  AnalysisSummary `json:"summary"`
+	Results  []Result        `json:"results"`
+}
+
+type AnalysisSummary struct {
+	Objects  int `json:"objects"`
+	Critical int `json:"critical"`
+	Warning  int `json:"warning"`
+	Info     int `json:"info"`
+}
+
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+)
+
 type Failure struct {
-	Text          string
-	KubernetesDoc string
+	// ID identifies the check that failed and stays the same across releases, e.g. POD_CRASHLOOP
+	ID string `json:"id"`
+	// Severity is one of SeverityCritical, SeverityWarning or SeverityInfo
+	Severity      string `json:"severity"`
+	Text          string `json:"text"`
+	KubernetesDoc string `json:"kubernetesDoc,omitempty"`
+	// FieldPath points at the field of the object the failure is about, e.g. spec.template.spec.containers[0]
+	FieldPath      string            `json:"fieldPath,omitempty"`
+	RelatedObjects []ObjectReference `json:"relatedObjects,omitempty"`
+	Remediation    *Remediation      `json:"remediation,omitempty"`
 	// Sensitive     []Sensitive
 }
 
+type ObjectReference struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+type Remediation struct {
+	Text string `json:"text"`
+	// Patch is a strategic merge patch of the object that fixes the failure, it is only set when the fix
+	// does not depend on values the analyzer cannot know
+	Patch string `json:"patch,omitempty"`
+}
+
 type Sensitive struct {
 	Unmasked string
 	Masked   string
 }
 
 type Request struct {
-	Context       context.Context
-	Namespace     string
-	Kind          string
+	Context   context.Context
+	Namespace string
+	Kind      string
+	// APIVersion optionally pins the group/version of Kind, it is only needed when Kind is served by several groups
+	APIVersion    string
 	Name          string
 	LabelSelector string
+	// MinSeverity drops the analyzer findings below it, all findings are kept when it is empty
+	MinSeverity string
+	// Progress is called by long-running operations to report how far they got, it may be nil
+	Progress Progress
 }
+
+// Progress reports how far a long-running operation got, progress increases with every call and total is 0 when
+// it is not known
+type Progress func(progress, total int, message string)
@@ -0,0 +1,9 @@
+package common
+
+type WaitOptions struct {
+	// For is what to wait for, in the syntax of kubectl wait --for: delete, condition=Ready[=value] or
+	// jsonpath={.status.phase}[=value]
+	For string
+	// Timeout is the wait timeout in seconds
+	Timeout int
+}
@@ -0,0 +1,285 @@
+package history
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	bolt "go.etcd.io/bbolt"
+)
+
+// maxReopened bounds the reopen timestamps kept per finding
+const maxReopened = 20
+
+var findingsBucket = []byte("findings")
+
+// Store keeps the history of cluster scan findings in a bbolt file.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens or creates the history file at path.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(findingsBucket)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// findingKey identifies a finding across scans, by its ID when the analyzer sets one since the text may carry
+// changing details such as counts. The owned object and the field the finding is about are part of the key, so the
+// same check failing for two pods of a deployment is tracked twice.
+func findingKey(namespace, object string, finding common.Finding) []byte {
+	check := finding.ID
+	if check == "" {
+		check = finding.Text
+	}
+	return []byte(strings.Join([]string{namespace, object, finding.Subject, finding.FieldPath, check}, "\x00"))
+}
+
+// Record stores the findings of a cluster scan made at scanTime. Findings seen for the first time are added, the
+// ones reported again are updated and the open ones the scan no longer reports are marked resolved. The findings of
+// the analyzers that failed during the scan are missing from it rather than gone and are not resolved.
+func (s *Store) Record(scanTime time.Time, report common.ClusterReport) error {
+	missed := missedBy(report)
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(findingsBucket)
+		seen := map[string]bool{}
+
+		for _, namespace := range report.Namespaces {
+			for _, object := range namespace.Objects {
+				for _, finding := range object.Findings {
+					key := findingKey(namespace.Namespace, object.Object, finding)
+					if seen[string(key)] {
+						continue
+					}
+					seen[string(key)] = true
+
+					record := common.FindingRecord{
+						Namespace: namespace.Namespace,
+						Object:    object.Object,
+						Subject:   finding.Subject,
+						FieldPath: finding.FieldPath,
+						ID:        finding.ID,
+						FirstSeen: scanTime,
+						OpenedAt:  scanTime,
+					}
+					if data := bucket.Get(key); data != nil {
+						if err := json.Unmarshal(data, &record); err != nil {
+							return err
+						}
+					}
+					if record.ResolvedAt != nil {
+						record.ResolvedAt = nil
+						record.OpenedAt = scanTime
+						record.Reopened = append(record.Reopened, scanTime)
+						if len(record.Reopened) > maxReopened {
+							record.Reopened = record.Reopened[len(record.Reopened)-maxReopened:]
+						}
+					}
+					record.Severity = finding.Severity
+					record.Text = finding.Text
+					record.Analyzer = finding.Analyzer
+					record.LastSeen = scanTime
+					if err := put(bucket, key, record); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		// resolve the open findings the scan did not report, collected first as a bucket must not be modified
+		// while iterating over it
+		var resolved [][]byte
+		var records []common.FindingRecord
+		err := bucket.ForEach(func(key, data []byte) error {
+			if seen[string(key)] {
+				return nil
+			}
+			var record common.FindingRecord
+			if err := json.Unmarshal(data, &record); err != nil {
+				return err
+			}
+			if record.ResolvedAt == nil && !missed(record) {
+				resolved = append(resolved, append([]byte(nil), key...))
+				records = append(records, record)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for i, key := range resolved {
+			records[i].ResolvedAt = &scanTime
+			if err := put(bucket, key, records[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// missedBy returns a function reporting whether the finding of a record may be missing from the report because the
+// analyzer reporting it failed. A namespaced analyzer failing in a namespace misses the findings of that namespace and of the cluster scoped
+// objects it evaluates along the namespaces, a cluster scoped analyzer failing misses all its findings. The findings
+// of unknown analyzers are missed by any failure.
+func missedBy(report common.ClusterReport) func(common.FindingRecord) bool {
+	type scope struct{ analyzer, namespace string }
+	failed := map[scope]bool{}
+	failedAnalyzers := map[string]bool{}
+	for _, f := range report.FailedAnalyzers {
+		failed[scope{f.Analyzer, f.Namespace}] = true
+		failedAnalyzers[f.Analyzer] = true
+	}
+	partial := len(report.Errors) > 0 || len(report.FailedAnalyzers) > 0
+	return func(record common.FindingRecord) bool {
+		switch {
+		case !partial:
+			return false
+		case record.Analyzer == "" || len(report.FailedAnalyzers) == 0:
+			return true
+		case record.Namespace == "":
+			return failedAnalyzers[record.Analyzer]
+		}
+		return failed[scope{record.Analyzer, ""}] || failed[scope{record.Analyzer, record.Namespace}]
+	}
+}
+
+// Prune deletes the findings resolved before before and returns how many were deleted, the open ones are kept.
+func (s *Store) Prune(before time.Time) (int, error) {
+	var pruned [][]byte
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(findingsBucket)
+		// collected first as a bucket must not be modified while iterating over it
+		err := bucket.ForEach(func(key, data []byte) error {
+			var record common.FindingRecord
+			if err := json.Unmarshal(data, &record); err != nil {
+				return err
+			}
+			if record.ResolvedAt != nil && record.ResolvedAt.Before(before) {
+				pruned = append(pruned, append([]byte(nil), key...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range pruned {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(pruned), nil
+}
+
+func put(bucket *bolt.Bucket, key []byte, record common.FindingRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(key, data)
+}
+
+func (s *Store) records(match func(common.FindingRecord) bool) ([]common.FindingRecord, error) {
+	records := []common.FindingRecord{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(findingsBucket).ForEach(func(_, data []byte) error {
+			var record common.FindingRecord
+			if err := json.Unmarshal(data, &record); err != nil {
+				return err
+			}
+			if match(record) {
+				records = append(records, record)
+			}
+			return nil
+		})
+	})
+	return records, err
+}
+
+// Since returns the findings that appeared or came back at or after since, newest first.
+func (s *Store) Since(since time.Time) ([]common.FindingRecord, error) {
+	records, err := s.records(func(record common.FindingRecord) bool {
+		return !record.OpenedAt.Before(since)
+	})
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].OpenedAt.After(records[j].OpenedAt)
+	})
+	return records, err
+}
+
+// Resolved returns the findings resolved at or after since, most recently resolved first.
+func (s *Store) Resolved(since time.Time) ([]common.FindingRecord, error) {
+	records, err := s.records(func(record common.FindingRecord) bool {
+		return record.ResolvedAt != nil && !record.ResolvedAt.Before(since)
+	})
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].ResolvedAt.After(*records[j].ResolvedAt)
+	})
+	return records, err
+}
+
+// Flapping returns the objects whose findings came back at least minFlaps times at or after since, the objects
+// flapping the most first.
+func (s *Store) Flapping(since time.Time, minFlaps int) ([]common.FlappingObject, error) {
+	type objectKey struct{ namespace, object string }
+	objects := map[objectKey]*common.FlappingObject{}
+	_, err := s.records(func(record common.FindingRecord) bool {
+		flaps := 0
+		for _, reopened := range record.Reopened {
+			if !reopened.Before(since) {
+				flaps++
+			}
+		}
+		if flaps == 0 {
+			return false
+		}
+		key := objectKey{namespace: record.Namespace, object: record.Object}
+		if objects[key] == nil {
+			objects[key] = &common.FlappingObject{Namespace: record.Namespace, Object: record.Object}
+		}
+		objects[key].Flaps += flaps
+		objects[key].Findings = append(objects[key].Findings, record)
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	flapping := []common.FlappingObject{}
+	for _, object := range objects {
+		if object.Flaps >= minFlaps {
+			flapping = append(flapping, *object)
+		}
+	}
+	sort.Slice(flapping, func(i, j int) bool {
+		if flapping[i].Flaps != flapping[j].Flaps {
+			return flapping[i].Flaps > flapping[j].Flaps
+		}
+		if flapping[i].Namespace != flapping[j].Namespace {
+			return flapping[i].Namespace < flapping[j].Namespace
+		}
+		return flapping[i].Object < flapping[j].Object
+	})
+	return flapping, nil
+}
@@ -0,0 +1,189 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+)
+
+func newTestStore(t *testing.T) *Store {
+	store, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func reportWith(findings ...common.Finding) common.ClusterReport {
+	if len(findings) == 0 {
+		return common.ClusterReport{}
+	}
+	return common.ClusterReport{Namespaces: []common.NamespaceReport{{
+		Namespace: "default",
+		Objects:   []common.ObjectReport{{Object: "Deployment/api", Findings: findings}},
+	}}}
+}
+
+func TestStoreRecord(t *testing.T) {
+	store := newTestStore(t)
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	crashLoop := common.Finding{ID: "POD_CRASHLOOP", Severity: common.SeverityCritical, Text: "Container api restarted 3 times"}
+
+	// reported, resolved and reported again
+	assert.NoError(t, store.Record(start, reportWith(crashLoop)))
+	crashLoop.Text = "Container api restarted 4 times"
+	assert.NoError(t, store.Record(start.Add(time.Minute), reportWith(crashLoop)))
+	assert.NoError(t, store.Record(start.Add(2*time.Minute), reportWith()))
+
+	resolved, err := store.Resolved(start)
+	assert.NoError(t, err)
+	if assert.Len(t, resolved, 1, "the finding is tracked by its ID although the text changed") {
+		assert.Equal(t, start, resolved[0].FirstSeen)
+		assert.Equal(t, start.Add(time.Minute), resolved[0].LastSeen)
+		assert.Equal(t, start.Add(2*time.Minute), *resolved[0].ResolvedAt)
+	}
+
+	assert.NoError(t, store.Record(start.Add(3*time.Minute), reportWith(crashLoop)))
+
+	since, err := store.Since(start.Add(150 * time.Second))
+	assert.NoError(t, err)
+	if assert.Len(t, since, 1) {
+		assert.Nil(t, since[0].ResolvedAt)
+		assert.Equal(t, start.Add(3*time.Minute), since[0].OpenedAt)
+		assert.Equal(t, start, since[0].FirstSeen)
+	}
+
+	resolved, err = store.Resolved(start)
+	assert.NoError(t, err)
+	assert.Empty(t, resolved)
+}
+
+func TestStoreRecordOwnedObjects(t *testing.T) {
+	store := newTestStore(t)
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	pod1 := common.Finding{ID: "POD_CRASHLOOP", Severity: common.SeverityCritical, Text: "Pod default/api-1: restarting", Subject: "Pod/api-1"}
+	pod2 := common.Finding{ID: "POD_CRASHLOOP", Severity: common.SeverityCritical, Text: "Pod default/api-2: restarting", Subject: "Pod/api-2"}
+
+	// the same check failing for two pods of the deployment is tracked per pod
+	assert.NoError(t, store.Record(start, reportWith(pod1, pod2)))
+	assert.NoError(t, store.Record(start.Add(time.Minute), reportWith(pod2)))
+
+	resolved, err := store.Resolved(start)
+	assert.NoError(t, err)
+	if assert.Len(t, resolved, 1) {
+		assert.Equal(t, "Pod/api-1", resolved[0].Subject)
+		assert.Equal(t, "Pod default/api-1: restarting", resolved[0].Text)
+	}
+	reported, err := store.Since(start)
+	assert.NoError(t, err)
+	if assert.Len(t, reported, 2) {
+		assert.Equal(t, "Pod default/api-2: restarting", reported[1].Text)
+	}
+}
+
+func TestStoreRecordFailedAnalyzer(t *testing.T) {
+	store := newTestStore(t)
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	finding := func(analyzer, id string) common.Finding {
+		return common.Finding{ID: id, Severity: common.SeverityWarning, Text: id, Analyzer: analyzer}
+	}
+	report := func(namespace string, findings ...common.Finding) common.NamespaceReport {
+		return common.NamespaceReport{Namespace: namespace, Objects: []common.ObjectReport{{Object: "Deployment/api", Findings: findings}}}
+	}
+	assert.NoError(t, store.Record(start, common.ClusterReport{Namespaces: []common.NamespaceReport{
+		report("", finding("policy", "NODE_ZONE_LABEL"), finding("webhook", "WEBHOOK_NO_ENDPOINTS")),
+		report("default", finding("pod", "POD_CRASHLOOP"), finding("storage", "PVC_PENDING")),
+		report("shop", finding("pod", "POD_CRASHLOOP")),
+	}}))
+
+	// the pod analyzer failed in default and the policy analyzer in shop, the findings they may have missed stay open
+	assert.NoError(t, store.Record(start.Add(time.Minute), common.ClusterReport{
+		Namespaces: []common.NamespaceReport{},
+		Errors:     []string{"pod analyzer in namespace default: pods is forbidden", "policy analyzer in namespace shop: nodes is forbidden"},
+		FailedAnalyzers: []common.FailedAnalyzer{
+			{Analyzer: "pod", Namespace: "default"},
+			{Analyzer: "policy", Namespace: "shop"},
+		},
+	}))
+
+	resolved, err := store.Resolved(start)
+	assert.NoError(t, err)
+	var ids []string
+	for _, record := range resolved {
+		ids = append(ids, record.Namespace+"/"+record.ID)
+	}
+	assert.ElementsMatch(t, []string{"/WEBHOOK_NO_ENDPOINTS", "default/PVC_PENDING", "shop/POD_CRASHLOOP"}, ids)
+}
+
+func TestStorePrune(t *testing.T) {
+	store := newTestStore(t)
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	crashLoop := common.Finding{ID: "POD_CRASHLOOP", Severity: common.SeverityCritical, Text: "Container api restarted 3 times"}
+	pending := common.Finding{ID: "PVC_PENDING", Severity: common.SeverityWarning, Text: "PersistentVolumeClaim is pending"}
+	unavailable := common.Finding{ID: "DEPLOYMENT_REPLICAS_UNAVAILABLE", Severity: common.SeverityWarning, Text: "Only 1/3 replicas available"}
+
+	assert.NoError(t, store.Record(start, reportWith(crashLoop, pending, unavailable)))
+	assert.NoError(t, store.Record(start.Add(time.Hour), reportWith(pending, unavailable)))
+	assert.NoError(t, store.Record(start.Add(2*time.Hour), reportWith(unavailable)))
+
+	pruned, err := store.Prune(start.Add(90 * time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pruned, "only the finding resolved before the retention window should be deleted")
+	resolved, err := store.Resolved(start)
+	assert.NoError(t, err)
+	if assert.Len(t, resolved, 1) {
+		assert.Equal(t, "PVC_PENDING", resolved[0].ID)
+	}
+	reported, err := store.Since(start)
+	assert.NoError(t, err)
+	assert.Len(t, reported, 2, "the open findings should be kept")
+}
+
+func TestStoreRecordPartialScan(t *testing.T) {
+	store := newTestStore(t)
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	crashLoop := common.Finding{ID: "POD_CRASHLOOP", Severity: common.SeverityCritical, Text: "Pod default/api-1: restarting"}
+
+	assert.NoError(t, store.Record(start, reportWith(crashLoop)))
+	// the pod analyzer failed, its finding is missing from the scan but not resolved
+	partial := reportWith()
+	partial.Errors = []string{"pod analyzer in namespace default: pods is forbidden"}
+	assert.NoError(t, store.Record(start.Add(time.Minute), partial))
+	assert.NoError(t, store.Record(start.Add(2*time.Minute), reportWith(crashLoop)))
+
+	resolved, err := store.Resolved(start)
+	assert.NoError(t, err)
+	assert.Empty(t, resolved)
+	flapping, err := store.Flapping(start, 1)
+	assert.NoError(t, err)
+	assert.Empty(t, flapping)
+}
+
+func TestStoreFlapping(t *testing.T) {
+	store := newTestStore(t)
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	notReady := common.Finding{ID: "DEPLOYMENT_UNAVAILABLE", Severity: common.SeverityWarning, Text: "Deployment default/api has 0 available replicas"}
+
+	for i := 0; i < 6; i++ {
+		report := reportWith()
+		if i%2 == 0 {
+			report = reportWith(notReady)
+		}
+		assert.NoError(t, store.Record(start.Add(time.Duration(i)*time.Minute), report))
+	}
+
+	flapping, err := store.Flapping(start, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, flapping, 1) {
+		assert.Equal(t, "Deployment/api", flapping[0].Object)
+		assert.Equal(t, 2, flapping[0].Flaps)
+	}
+
+	flapping, err = store.Flapping(start.Add(3*time.Minute), 2)
+	assert.NoError(t, err)
+	assert.Empty(t, flapping, "only one flap falls in the window")
+}
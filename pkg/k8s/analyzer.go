@@ -0,0 +1,247 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Analyzer inspects the objects of one or more kinds and reports the ones with failures.
+type Analyzer interface {
+	// Name identifies the analyzer, the MCP tool generated for it is called "<name> analyze".
+	Name() string
+	// Description describes what the analyzer checks, it is used as the tool description.
+	Description() string
+	// Kinds lists the kinds the analyzer reports results for.
+	Kinds() []string
+	// Namespaced reports whether the analyzer runs per namespace, an empty namespace means all namespaces. A
+	// namespaced analyzer is only given the namespace of the request, the others its name and label selector.
+	Namespaced() bool
+	Analyze(ctx context.Context, r common.Request) ([]common.Result, error)
+}
+
+// AnalyzerFactory creates an analyzer that inspects the cluster k is connected to.
+type AnalyzerFactory func(k *Kubernetes) Analyzer
+
+var (
+	analyzersMu       sync.RWMutex
+	analyzerFactories = map[string]AnalyzerFactory{}
+)
+
+// RegisterAnalyzer makes an analyzer available as an MCP tool and to cluster scans. It is meant to be called from
+// an init function and panics if the name is registered twice.
+func RegisterAnalyzer(name string, factory AnalyzerFactory) {
+	analyzersMu.Lock()
+	defer analyzersMu.Unlock()
+	if factory == nil {
+		panic("k8s: RegisterAnalyzer factory is nil")
+	}
+	if _, dup := analyzerFactories[name]; dup {
+		panic("k8s: RegisterAnalyzer called twice for analyzer " + name)
+	}
+	analyzerFactories[name] = factory
+}
+
+// Analyzers returns the registered analyzers sorted by name.
+func (k *Kubernetes) Analyzers() []Analyzer {
+	analyzersMu.RLock()
+	defer analyzersMu.RUnlock()
+	names := make([]string, 0, len(analyzerFactories))
+	for name := range analyzerFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	analyzers := make([]Analyzer, 0, len(names))
+	for _, name := range names {
+		analyzers = append(analyzers, analyzerFactories[name](k))
+	}
+	return analyzers
+}
+
+// Analyze runs the registered analyzer called name and returns its results as JSON.
+func (k *Kubernetes) Analyze(name string, r common.Request) (string, error) {
+	return marshalResults(k.AnalyzeResults(name, r))
+}
+
+// AnalyzeResults runs the registered analyzer called name and returns its ranked results sorted by namespace, kind
+// and name.
+func (k *Kubernetes) AnalyzeResults(name string, r common.Request) ([]common.Result, error) {
+	analyzersMu.RLock()
+	factory, ok := analyzerFactories[name]
+	analyzersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("analyzer %s is not registered", name)
+	}
+	if r.MinSeverity != "" && severityRank[r.MinSeverity] == 0 {
+		return nil, fmt.Errorf("unknown severity %s, expected %s, %s or %s", r.MinSeverity, common.SeverityCritical, common.SeverityWarning, common.SeverityInfo)
+	}
+	results, err := factory(k).Analyze(r.Context, r)
+	if err != nil {
+		return nil, err
+	}
+	return filterResults(rankResults(results), r.MinSeverity), nil
+}
+
+// builtinAnalyzer adapts an analyze method of Kubernetes to the Analyzer interface.
+type builtinAnalyzer struct {
+	k           *Kubernetes
+	name        string
+	description string
+	kinds       []string
+	namespaced  bool
+	analyze     func(k *Kubernetes, ctx context.Context, r common.Request) ([]common.Result, error)
+}
+
+func (a builtinAnalyzer) Name() string        { return a.name }
+func (a builtinAnalyzer) Description() string { return a.description }
+func (a builtinAnalyzer) Kinds() []string     { return a.kinds }
+func (a builtinAnalyzer) Namespaced() bool    { return a.namespaced }
+
+func (a builtinAnalyzer) Analyze(ctx context.Context, r common.Request) ([]common.Result, error) {
+	return a.analyze(a.k, ctx, r)
+}
+
+var builtinAnalyzers = []builtinAnalyzer{
+	{name: "pod", description: "analyze pod", kinds: []string{"Pod"}, namespaced: true, analyze: (*Kubernetes).analyzePod},
+	{name: "deployment", description: "analyze deployment status", kinds: []string{"Deployment"}, namespaced: true, analyze: (*Kubernetes).analyzeDeployment},
+	{name: "statefulset", description: "analyze statefulset status", kinds: []string{"StatefulSet"}, namespaced: true, analyze: (*Kubernetes).analyzeStatefulSet},
+	{name: "daemonset", description: "analyze daemonset status, misscheduled and unavailable pods and nodes whose taints are not tolerated", kinds: []string{"DaemonSet"}, namespaced: true, analyze: (*Kubernetes).analyzeDaemonSet},
+	{name: "job", description: "analyze job status, backoff limit and deadline failures and failed pod reasons", kinds: []string{"Job"}, namespaced: true, analyze: (*Kubernetes).analyzeJob},
+	{name: "cronjob", description: "analyze cronjob status", kinds: []string{"CronJob"}, namespaced: true, analyze: (*Kubernetes).analyzeCronJob},
+	{name: "hpa", description: "analyze horizontal pod autoscaler metrics, scale target and replica limits", kinds: []string{"HorizontalPodAutoscaler"}, namespaced: true, analyze: (*Kubernetes).analyzeHPA},
+	{name: "service", description: "analyze service status", kinds: []string{"Service"}, namespaced: true, analyze: (*Kubernetes).analyzeService},
+	{name: "ingress", description: "analyze ingress status", kinds: []string{"Ingress"}, namespaced: true, analyze: (*Kubernetes).analyzeIngress},
+	{name: "networkpolicy", description: "analyze networkpolicy status", kinds: []string{"NetworkPolicy"}, namespaced: true, analyze: (*Kubernetes).analyzeNetworkPolicy},
+	{
+		name:        "storage",
+		description: "analyze persistentvolumeclaims and the persistentvolumes, storageclasses and volumeattachments backing them",
+		kinds:       []string{"PersistentVolumeClaim", "PersistentVolume", "VolumeAttachment"},
+		namespaced:  true,
+		analyze:     (*Kubernetes).analyzeStorage,
+	},
+	{name: "node", description: "analyze node status", kinds: []string{"Node"}, analyze: (*Kubernetes).analyzeNode},
+	{name: "validatingwebhook", description: "analyze validating webhook configurations", kinds: []string{"ValidatingWebhookConfiguration"}, analyze: (*Kubernetes).analyzeValidatingWebhook},
+	{name: "mutatingwebhook", description: "analyze mutating webhook configurations", kinds: []string{"MutatingWebhookConfiguration"}, analyze: (*Kubernetes).analyzeMutatingWebhook},
+}
+
+func init() {
+	for _, a := range builtinAnalyzers {
+		RegisterAnalyzer(a.name, func(k *Kubernetes) Analyzer {
+			a.k = k
+			return a
+		})
+	}
+}
+
+// preAnalysisResults turns the pre analysis of a kind into results, objectMeta returns the metadata the owners
+// are resolved from and is nil for kinds that have no owners.
+func (k *Kubernetes) preAnalysisResults(ctx context.Context, kind string, preAnalysis map[string]common.PreAnalysis, objectMeta func(common.PreAnalysis) metav1.ObjectMeta) []common.Result {
+	owners := k.newOwnerResolver()
+	results := make([]common.Result, 0, len(preAnalysis))
+	for key, value := range preAnalysis {
+		result := common.Result{
+			Kind:  kind,
+			Name:  key,
+			Error: value.FailureDetails,
+		}
+		if objectMeta != nil {
+			meta := objectMeta(value)
+			owners.setOwners(ctx, &result, meta.Namespace, meta.OwnerReferences)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func marshalResults(results []common.Result, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	jsonData, err := json.Marshal(rankResults(results))
+	if err != nil {
+		return "", err
+	}
+	return string(jsonData), nil
+}
+
+var severityRank = map[string]int{
+	common.SeverityCritical: 3,
+	common.SeverityWarning:  2,
+	common.SeverityInfo:     1,
+}
+
+// failureSeverity returns the severity of a failure, failures of analyzers that do not set one count as warnings.
+func failureSeverity(failure common.Failure) string {
+	if severityRank[failure.Severity] == 0 {
+		return common.SeverityWarning
+	}
+	return failure.Severity
+}
+
+// splitResultName splits the name of a result into the namespace and the object name, the namespace is empty for
+// cluster scoped objects.
+func splitResultName(name string) (string, string) {
+	if i := strings.Index(name, "/"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
+
+// rankResults orders the failures of each result by severity and sets the result severity to the highest one. The
+// results are sorted by namespace, kind and name so the output does not depend on map iteration order.
+func rankResults(results []common.Result) []common.Result {
+	sort.SliceStable(results, func(i, j int) bool {
+		nsI, nameI := splitResultName(results[i].Name)
+		nsJ, nameJ := splitResultName(results[j].Name)
+		if nsI != nsJ {
+			return nsI < nsJ
+		}
+		if results[i].Kind != results[j].Kind {
+			return results[i].Kind < results[j].Kind
+		}
+		return nameI < nameJ
+	})
+	for i := range results {
+		failures := results[i].Error
+		for j := range failures {
+			failures[j].Severity = failureSeverity(failures[j])
+		}
+		sort.SliceStable(failures, func(a, b int) bool {
+			return severityRank[failures[a].Severity] > severityRank[failures[b].Severity]
+		})
+		if len(failures) > 0 {
+			results[i].Severity = failures[0].Severity
+		}
+	}
+	return results
+}
+
+// filterResults drops the failures below minSeverity and the results left without failures, results are expected
+// to be ranked.
+func filterResults(results []common.Result, minSeverity string) []common.Result {
+	if minSeverity == "" {
+		return results
+	}
+	filtered := make([]common.Result, 0, len(results))
+	for _, result := range results {
+		var failures []common.Failure
+		for _, failure := range result.Error {
+			if severityRank[failure.Severity] >= severityRank[minSeverity] {
+				failures = append(failures, failure)
+			}
+		}
+		if len(failures) > 0 {
+			result.Error = failures
+			result.Severity = failures[0].Severity
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
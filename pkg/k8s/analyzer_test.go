@@ -0,0 +1,147 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+// configMapAnalyzer flags ConfigMaps without data, it stands in for an analyzer registered by another package.
+type configMapAnalyzer struct {
+	k *Kubernetes
+}
+
+func (a configMapAnalyzer) Name() string        { return "configmap" }
+func (a configMapAnalyzer) Description() string { return "analyze empty configmaps" }
+func (a configMapAnalyzer) Kinds() []string     { return []string{"ConfigMap"} }
+func (a configMapAnalyzer) Namespaced() bool    { return true }
+
+func (a configMapAnalyzer) Analyze(ctx context.Context, r common.Request) ([]common.Result, error) {
+	cmList, err := a.k.clientset.CoreV1().ConfigMaps(r.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	results := make([]common.Result, 0)
+	for _, cm := range cmList.Items {
+		if len(cm.Data) == 0 && len(cm.BinaryData) == 0 {
+			results = append(results, common.Result{
+				Kind:  "ConfigMap",
+				Name:  cm.Namespace + "/" + cm.Name,
+				Error: []common.Failure{{Text: "ConfigMap has no data"}},
+			})
+		}
+	}
+	return results, nil
+}
+
+func registerTestAnalyzer(t *testing.T) {
+	RegisterAnalyzer("configmap", func(k *Kubernetes) Analyzer { return configMapAnalyzer{k: k} })
+	t.Cleanup(func() {
+		analyzersMu.Lock()
+		defer analyzersMu.Unlock()
+		delete(analyzerFactories, "configmap")
+	})
+}
+
+func TestAnalyzerRegistry(t *testing.T) {
+	registerTestAnalyzer(t)
+	clientset := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "empty", Namespace: "default"}},
+		&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"}, Data: map[string]string{"a": "b"}},
+	)
+	k := newTestKubernetes(clientset, nil)
+
+	t.Run("Registered analyzers", func(t *testing.T) {
+		var names []string
+		for _, a := range k.Analyzers() {
+			names = append(names, a.Name())
+		}
+		assert.IsIncreasing(t, names)
+		assert.Contains(t, names, "configmap")
+		assert.Contains(t, names, "pod")
+		assert.Contains(t, names, "mutatingwebhook")
+	})
+
+	t.Run("Analyze by name", func(t *testing.T) {
+		res, err := k.Analyze("configmap", common.Request{Context: context.Background(), Namespace: "default"})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[{"kind":"ConfigMap","name":"default/empty","error":[{"id":"","severity":"warning","text":"ConfigMap has no data"}],"severity":"warning","details":"","parentObject":""}]`, res)
+
+		_, err = k.Analyze("secret", common.Request{Context: context.Background()})
+		assert.EqualError(t, err, "analyzer secret is not registered")
+	})
+
+	t.Run("Duplicate name", func(t *testing.T) {
+		assert.Panics(t, func() {
+			RegisterAnalyzer("pod", func(k *Kubernetes) Analyzer { return configMapAnalyzer{k: k} })
+		})
+	})
+
+	t.Run("Cluster scan", func(t *testing.T) {
+		res, err := k.AnalyzeCluster(common.Request{Context: context.Background()}, common.ClusterScanOptions{})
+		assert.NoError(t, err)
+		var report common.ClusterReport
+		assert.NoError(t, json.Unmarshal([]byte(res), &report))
+		assert.Len(t, report.Namespaces, 1)
+		assert.Equal(t, "ConfigMap/empty", report.Namespaces[0].Objects[0].Object)
+	})
+}
+
+func TestRankAndFilterResults(t *testing.T) {
+	results := rankResults([]common.Result{
+		{
+			Kind: "Deployment",
+			Name: "default/api",
+			Error: []common.Failure{
+				{ID: "DEPLOYMENT_NO_READINESS_PROBE", Severity: common.SeverityInfo, Text: "Container api has no readiness probe"},
+				{Text: "a failure of an analyzer that does not set a severity"},
+				{ID: "DEPLOYMENT_ROLLOUT_STUCK", Severity: common.SeverityCritical, Text: "New ReplicaSet default/api-2 has no ready replicas"},
+			},
+		},
+		{
+			Kind:  "CronJob",
+			Name:  "default/report",
+			Error: []common.Failure{{ID: "CRONJOB_SUSPENDED", Severity: common.SeverityInfo, Text: "CronJob default/report is suspended"}},
+		},
+	})
+	// results are sorted by namespace, kind and name
+	assert.Equal(t, []string{"CronJob", "Deployment"}, []string{results[0].Kind, results[1].Kind})
+	assert.Equal(t, common.SeverityCritical, results[1].Severity)
+	assert.Equal(t, []string{common.SeverityCritical, common.SeverityWarning, common.SeverityInfo},
+		[]string{results[1].Error[0].Severity, results[1].Error[1].Severity, results[1].Error[2].Severity})
+	assert.Equal(t, common.SeverityInfo, results[0].Severity)
+
+	filtered := filterResults(results, common.SeverityWarning)
+	assert.Len(t, filtered, 1)
+	assert.Len(t, filtered[0].Error, 2)
+	assert.Len(t, filterResults(results, ""), 2)
+}
+
+func TestAnalyzeMinSeverity(t *testing.T) {
+	suspended := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "default"},
+		Spec:       batchv1.CronJobSpec{Schedule: "0 * * * *", Suspend: ptr.To(true)},
+	}
+	k := newTestKubernetes(fake.NewSimpleClientset(suspended), nil)
+
+	res, err := k.Analyze("cronjob", common.Request{Context: context.Background(), Namespace: "default"})
+	assert.NoError(t, err)
+	assert.Contains(t, res, `"id":"CRONJOB_SUSPENDED"`)
+	assert.Contains(t, res, `"patch":"{\"spec\":{\"suspend\":false}}"`)
+
+	res, err = k.Analyze("cronjob", common.Request{Context: context.Background(), Namespace: "default", MinSeverity: common.SeverityWarning})
+	assert.NoError(t, err)
+	assert.Equal(t, "[]", res)
+
+	_, err = k.Analyze("cronjob", common.Request{Context: context.Background(), MinSeverity: "urgent"})
+	assert.EqualError(t, err, "unknown severity urgent, expected critical, warning or info")
+}
@@ -0,0 +1,338 @@
+package k8s
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// defaultCacheSyncTimeout bounds the wait for the first sync of an informer, reads fall back to the API server
+// until it is synced.
+const defaultCacheSyncTimeout = 10 * time.Second
+
+// informerCache serves List and Get calls from informers, the informer of a resource type is started the first time
+// the type is read.
+type informerCache struct {
+	typed       informers.SharedInformerFactory
+	dynamic     dynamicinformer.DynamicSharedInformerFactory
+	stopCh      chan struct{}
+	syncTimeout time.Duration
+
+	mu        sync.Mutex
+	resources map[cacheKey]*cachedResource
+}
+
+type cacheKey struct {
+	gvr     schema.GroupVersionResource
+	dynamic bool
+}
+
+type cachedResource struct {
+	informer  cache.SharedIndexInformer
+	lister    cache.GenericLister
+	startedAt time.Time
+
+	mu               sync.Mutex
+	lastEvent        time.Time
+	lastWatchError   string
+	lastWatchErrorAt time.Time
+}
+
+// EnableCache makes the analyzers and the resource tools read through informers instead of calling the API server
+// on every request. Writes are not cached, so an object read right after it changed may be stale.
+func (k *Kubernetes) EnableCache() {
+	if k.cache != nil {
+		return
+	}
+	k.cache = &informerCache{
+		typed:       informers.NewSharedInformerFactory(k.clientset, 0),
+		dynamic:     dynamicinformer.NewDynamicSharedInformerFactory(k.dynamicClient, 0),
+		stopCh:      make(chan struct{}),
+		syncTimeout: defaultCacheSyncTimeout,
+		resources:   map[cacheKey]*cachedResource{},
+	}
+	k.clientset = newCachedClientset(k.clientset, k.cache)
+	k.dynamicClient = cachedDynamicClient{Interface: k.dynamicClient, cache: k.cache}
+}
+
+// apiDynamicClient returns the dynamic client reading from the API server, a write checked against what it read
+// must not read a stale cached copy.
+func (k *Kubernetes) apiDynamicClient() dynamic.Interface {
+	if c, ok := k.dynamicClient.(cachedDynamicClient); ok {
+		return c.Interface
+	}
+	return k.dynamicClient
+}
+
+// CacheEnabled reports whether reads go through the informer cache.
+func (k *Kubernetes) CacheEnabled() bool {
+	return k.cache != nil
+}
+
+// CacheStatus reports the resource types the cache watches and how fresh they are, it is empty when the cache is
+// not enabled.
+func (k *Kubernetes) CacheStatus() []common.CacheStatus {
+	if k.cache == nil {
+		return []common.CacheStatus{}
+	}
+	return k.cache.status()
+}
+
+// resource returns the informer of a resource type, starting it on first use. The first use waits for the informer
+// to sync at most syncTimeout, resource reports whether it is synced.
+func (c *informerCache) resource(ctx context.Context, gvr schema.GroupVersionResource, isDynamic bool) (*cachedResource, bool) {
+	key := cacheKey{gvr: gvr, dynamic: isDynamic}
+	c.mu.Lock()
+	res, ok := c.resources[key]
+	if !ok {
+		var informer informers.GenericInformer
+		if isDynamic {
+			informer = c.dynamic.ForResource(gvr)
+		} else {
+			var err error
+			if informer, err = c.typed.ForResource(gvr); err != nil {
+				c.mu.Unlock()
+				return nil, false
+			}
+		}
+		res = &cachedResource{
+			informer:  informer.Informer(),
+			lister:    informer.Lister(),
+			startedAt: time.Now(),
+		}
+		_ = res.informer.SetWatchErrorHandlerWithContext(func(ctx context.Context, r *cache.Reflector, err error) {
+			res.mu.Lock()
+			res.lastWatchError, res.lastWatchErrorAt = err.Error(), time.Now()
+			res.mu.Unlock()
+			cache.DefaultWatchErrorHandler(ctx, r, err)
+		})
+		touch := func() {
+			res.mu.Lock()
+			res.lastEvent = time.Now()
+			res.mu.Unlock()
+		}
+		_, _ = res.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(interface{}) { touch() },
+			UpdateFunc: func(interface{}, interface{}) { touch() },
+			DeleteFunc: func(interface{}) { touch() },
+		})
+		c.resources[key] = res
+		if isDynamic {
+			c.dynamic.Start(c.stopCh)
+		} else {
+			c.typed.Start(c.stopCh)
+		}
+	}
+	c.mu.Unlock()
+
+	// only the first read waits, an informer that cannot sync, e.g. for lack of list permissions, must not slow
+	// down every later read
+	if !ok {
+		ctx, cancel := context.WithTimeout(ctx, c.syncTimeout)
+		defer cancel()
+		cache.WaitForCacheSync(ctx.Done(), res.informer.HasSynced)
+	}
+	return res, res.informer.HasSynced()
+}
+
+// cacheable reports whether a list can be answered by a lister, which only filters by labels.
+func cacheable(opts metav1.ListOptions) bool {
+	return opts.FieldSelector == "" && opts.ResourceVersion == "" && opts.Limit == 0 && opts.Continue == ""
+}
+
+// list returns deep copies of the cached objects sorted by namespace and name like the API server does, ok is false
+// when the call has to go to the API server.
+func (c *informerCache) list(ctx context.Context, gvr schema.GroupVersionResource, isDynamic bool, namespace string, opts metav1.ListOptions) ([]runtime.Object, bool, error) {
+	if !cacheable(opts) {
+		return nil, false, nil
+	}
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, true, err
+	}
+	res, ok := c.resource(ctx, gvr, isDynamic)
+	if !ok {
+		return nil, false, nil
+	}
+
+	var objects []runtime.Object
+	if namespace != "" {
+		objects, err = res.lister.ByNamespace(namespace).List(selector)
+	} else {
+		objects, err = res.lister.List(selector)
+	}
+	if err != nil {
+		return nil, true, err
+	}
+	copies := make([]runtime.Object, 0, len(objects))
+	for _, obj := range objects {
+		copies = append(copies, obj.DeepCopyObject())
+	}
+	sort.Slice(copies, func(i, j int) bool {
+		a, _ := meta.Accessor(copies[i])
+		b, _ := meta.Accessor(copies[j])
+		if a.GetNamespace() != b.GetNamespace() {
+			return a.GetNamespace() < b.GetNamespace()
+		}
+		return a.GetName() < b.GetName()
+	})
+	return copies, true, nil
+}
+
+// get returns a deep copy of a cached object, ok is false when the call has to go to the API server.
+func (c *informerCache) get(ctx context.Context, gvr schema.GroupVersionResource, isDynamic bool, namespace, name string, opts metav1.GetOptions) (runtime.Object, bool, error) {
+	if opts.ResourceVersion != "" {
+		return nil, false, nil
+	}
+	res, ok := c.resource(ctx, gvr, isDynamic)
+	if !ok {
+		return nil, false, nil
+	}
+	var obj runtime.Object
+	var err error
+	if namespace != "" {
+		obj, err = res.lister.ByNamespace(namespace).Get(name)
+	} else {
+		obj, err = res.lister.Get(name)
+	}
+	if err != nil {
+		return nil, true, err
+	}
+	return obj.DeepCopyObject(), true, nil
+}
+
+func (c *informerCache) status() []common.CacheStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	statuses := make([]common.CacheStatus, 0, len(c.resources))
+	for key, res := range c.resources {
+		res.mu.Lock()
+		status := common.CacheStatus{
+			Resource:        key.gvr.String(),
+			Dynamic:         key.dynamic,
+			Synced:          res.informer.HasSynced(),
+			StartedAt:       res.startedAt,
+			ResourceVersion: res.informer.LastSyncResourceVersion(),
+			LastWatchError:  res.lastWatchError,
+		}
+		if !res.lastEvent.IsZero() {
+			lastEvent := res.lastEvent
+			status.LastEvent = &lastEvent
+		}
+		if !res.lastWatchErrorAt.IsZero() {
+			lastWatchErrorAt := res.lastWatchErrorAt
+			status.LastWatchErrorAt = &lastWatchErrorAt
+			// the watch failed after the last event it delivered, the cache may miss changes since then
+			status.Stale = res.lastWatchErrorAt.After(res.lastEvent)
+		}
+		res.mu.Unlock()
+		if !status.Synced {
+			status.Stale = true
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Resource != statuses[j].Resource {
+			return statuses[i].Resource < statuses[j].Resource
+		}
+		return !statuses[i].Dynamic
+	})
+	return statuses
+}
+
+// cachedDynamicClient serves the List and Get calls of a dynamic client from the cache.
+type cachedDynamicClient struct {
+	dynamic.Interface
+	cache *informerCache
+}
+
+func (c cachedDynamicClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	resource := c.Interface.Resource(gvr)
+	return cachedDynamicResource{
+		NamespaceableResourceInterface: resource,
+		cachedDynamicReader:            cachedDynamicReader{cache: c.cache, gvr: gvr, direct: resource},
+	}
+}
+
+type cachedDynamicResource struct {
+	dynamic.NamespaceableResourceInterface
+	cachedDynamicReader
+}
+
+func (r cachedDynamicResource) Namespace(namespace string) dynamic.ResourceInterface {
+	resource := r.NamespaceableResourceInterface.Namespace(namespace)
+	return cachedDynamicNamespacedResource{
+		ResourceInterface:   resource,
+		cachedDynamicReader: cachedDynamicReader{cache: r.cache, gvr: r.gvr, namespace: namespace, direct: resource},
+	}
+}
+
+func (r cachedDynamicResource) List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return r.cachedDynamicReader.List(ctx, opts)
+}
+
+func (r cachedDynamicResource) Get(ctx context.Context, name string, opts metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	return r.cachedDynamicReader.Get(ctx, name, opts, subresources...)
+}
+
+type cachedDynamicNamespacedResource struct {
+	dynamic.ResourceInterface
+	cachedDynamicReader
+}
+
+func (r cachedDynamicNamespacedResource) List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return r.cachedDynamicReader.List(ctx, opts)
+}
+
+func (r cachedDynamicNamespacedResource) Get(ctx context.Context, name string, opts metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	return r.cachedDynamicReader.Get(ctx, name, opts, subresources...)
+}
+
+type cachedDynamicReader struct {
+	cache     *informerCache
+	gvr       schema.GroupVersionResource
+	namespace string
+	direct    dynamic.ResourceInterface
+}
+
+func (r cachedDynamicReader) List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	objects, ok, err := r.cache.list(ctx, r.gvr, true, r.namespace, opts)
+	if !ok {
+		return r.direct.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	list := &unstructured.UnstructuredList{Object: map[string]interface{}{}}
+	for _, obj := range objects {
+		list.Items = append(list.Items, *obj.(*unstructured.Unstructured))
+	}
+	return list, nil
+}
+
+func (r cachedDynamicReader) Get(ctx context.Context, name string, opts metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	if len(subresources) > 0 {
+		return r.direct.Get(ctx, name, opts, subresources...)
+	}
+	obj, ok, err := r.cache.get(ctx, r.gvr, true, r.namespace, name, opts)
+	if !ok {
+		return r.direct.Get(ctx, name, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*unstructured.Unstructured), nil
+}
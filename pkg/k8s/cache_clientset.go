@@ -0,0 +1,734 @@
+package k8s
+
+import (
+	"context"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	admissionregistrationv1client "k8s.io/client-go/kubernetes/typed/admissionregistration/v1"
+	appsv1client "k8s.io/client-go/kubernetes/typed/apps/v1"
+	autoscalingv2client "k8s.io/client-go/kubernetes/typed/autoscaling/v2"
+	batchv1client "k8s.io/client-go/kubernetes/typed/batch/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	networkingv1client "k8s.io/client-go/kubernetes/typed/networking/v1"
+	policyv1client "k8s.io/client-go/kubernetes/typed/policy/v1"
+	storagev1client "k8s.io/client-go/kubernetes/typed/storage/v1"
+)
+
+// cachedClientset serves the List and Get calls the analyzers make from the cache, the other calls and the
+// resource types that are not cached, such as secrets and events, go to the API server.
+type cachedClientset struct {
+	kubernetes.Interface
+	cache *informerCache
+}
+
+func newCachedClientset(clientset kubernetes.Interface, cache *informerCache) kubernetes.Interface {
+	return cachedClientset{Interface: clientset, cache: cache}
+}
+
+func cachedGet[T runtime.Object](ctx context.Context, c *informerCache, gvr schema.GroupVersionResource, namespace, name string, opts metav1.GetOptions, direct func(context.Context, string, metav1.GetOptions) (T, error)) (T, error) {
+	obj, ok, err := c.get(ctx, gvr, false, namespace, name, opts)
+	if !ok {
+		return direct(ctx, name, opts)
+	}
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return obj.(T), nil
+}
+
+// cachedList returns the cached items of a list, ok is false when the list has to come from the API server.
+func cachedList[T any](ctx context.Context, c *informerCache, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) ([]T, bool, error) {
+	objects, ok, err := c.list(ctx, gvr, false, namespace, opts)
+	if !ok || err != nil {
+		return nil, ok, err
+	}
+	items := make([]T, 0, len(objects))
+	for _, obj := range objects {
+		items = append(items, *any(obj).(*T))
+	}
+	return items, true, nil
+}
+
+type cachedCoreV1 struct {
+	corev1client.CoreV1Interface
+	cache *informerCache
+}
+
+func (c cachedClientset) CoreV1() corev1client.CoreV1Interface {
+	return cachedCoreV1{CoreV1Interface: c.Interface.CoreV1(), cache: c.cache}
+}
+
+func (c cachedCoreV1) Pods(namespace string) corev1client.PodInterface {
+	return cachedPods{PodInterface: c.CoreV1Interface.Pods(namespace), cache: c.cache, namespace: namespace}
+}
+
+type cachedPods struct {
+	corev1client.PodInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedPods) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Pod, error) {
+	return cachedGet(ctx, c.cache, corev1.SchemeGroupVersion.WithResource("pods"), c.namespace, name, opts, c.PodInterface.Get)
+}
+
+func (c cachedPods) List(ctx context.Context, opts metav1.ListOptions) (*corev1.PodList, error) {
+	items, ok, err := cachedList[corev1.Pod](ctx, c.cache, corev1.SchemeGroupVersion.WithResource("pods"), c.namespace, opts)
+	if !ok {
+		return c.PodInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.PodList{Items: items}, nil
+}
+
+func (c cachedCoreV1) Services(namespace string) corev1client.ServiceInterface {
+	return cachedServices{ServiceInterface: c.CoreV1Interface.Services(namespace), cache: c.cache, namespace: namespace}
+}
+
+type cachedServices struct {
+	corev1client.ServiceInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedServices) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Service, error) {
+	return cachedGet(ctx, c.cache, corev1.SchemeGroupVersion.WithResource("services"), c.namespace, name, opts, c.ServiceInterface.Get)
+}
+
+func (c cachedServices) List(ctx context.Context, opts metav1.ListOptions) (*corev1.ServiceList, error) {
+	items, ok, err := cachedList[corev1.Service](ctx, c.cache, corev1.SchemeGroupVersion.WithResource("services"), c.namespace, opts)
+	if !ok {
+		return c.ServiceInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ServiceList{Items: items}, nil
+}
+
+func (c cachedCoreV1) Endpoints(namespace string) corev1client.EndpointsInterface {
+	return cachedEndpoints{EndpointsInterface: c.CoreV1Interface.Endpoints(namespace), cache: c.cache, namespace: namespace}
+}
+
+type cachedEndpoints struct {
+	corev1client.EndpointsInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedEndpoints) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Endpoints, error) {
+	return cachedGet(ctx, c.cache, corev1.SchemeGroupVersion.WithResource("endpoints"), c.namespace, name, opts, c.EndpointsInterface.Get)
+}
+
+func (c cachedEndpoints) List(ctx context.Context, opts metav1.ListOptions) (*corev1.EndpointsList, error) {
+	items, ok, err := cachedList[corev1.Endpoints](ctx, c.cache, corev1.SchemeGroupVersion.WithResource("endpoints"), c.namespace, opts)
+	if !ok {
+		return c.EndpointsInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.EndpointsList{Items: items}, nil
+}
+
+func (c cachedCoreV1) ConfigMaps(namespace string) corev1client.ConfigMapInterface {
+	return cachedConfigMaps{ConfigMapInterface: c.CoreV1Interface.ConfigMaps(namespace), cache: c.cache, namespace: namespace}
+}
+
+type cachedConfigMaps struct {
+	corev1client.ConfigMapInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedConfigMaps) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.ConfigMap, error) {
+	return cachedGet(ctx, c.cache, corev1.SchemeGroupVersion.WithResource("configmaps"), c.namespace, name, opts, c.ConfigMapInterface.Get)
+}
+
+func (c cachedConfigMaps) List(ctx context.Context, opts metav1.ListOptions) (*corev1.ConfigMapList, error) {
+	items, ok, err := cachedList[corev1.ConfigMap](ctx, c.cache, corev1.SchemeGroupVersion.WithResource("configmaps"), c.namespace, opts)
+	if !ok {
+		return c.ConfigMapInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ConfigMapList{Items: items}, nil
+}
+
+func (c cachedCoreV1) PersistentVolumeClaims(namespace string) corev1client.PersistentVolumeClaimInterface {
+	return cachedPersistentVolumeClaims{PersistentVolumeClaimInterface: c.CoreV1Interface.PersistentVolumeClaims(namespace), cache: c.cache, namespace: namespace}
+}
+
+type cachedPersistentVolumeClaims struct {
+	corev1client.PersistentVolumeClaimInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedPersistentVolumeClaims) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.PersistentVolumeClaim, error) {
+	return cachedGet(ctx, c.cache, corev1.SchemeGroupVersion.WithResource("persistentvolumeclaims"), c.namespace, name, opts, c.PersistentVolumeClaimInterface.Get)
+}
+
+func (c cachedPersistentVolumeClaims) List(ctx context.Context, opts metav1.ListOptions) (*corev1.PersistentVolumeClaimList, error) {
+	items, ok, err := cachedList[corev1.PersistentVolumeClaim](ctx, c.cache, corev1.SchemeGroupVersion.WithResource("persistentvolumeclaims"), c.namespace, opts)
+	if !ok {
+		return c.PersistentVolumeClaimInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.PersistentVolumeClaimList{Items: items}, nil
+}
+
+func (c cachedCoreV1) ReplicationControllers(namespace string) corev1client.ReplicationControllerInterface {
+	return cachedReplicationControllers{ReplicationControllerInterface: c.CoreV1Interface.ReplicationControllers(namespace), cache: c.cache, namespace: namespace}
+}
+
+type cachedReplicationControllers struct {
+	corev1client.ReplicationControllerInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedReplicationControllers) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.ReplicationController, error) {
+	return cachedGet(ctx, c.cache, corev1.SchemeGroupVersion.WithResource("replicationcontrollers"), c.namespace, name, opts, c.ReplicationControllerInterface.Get)
+}
+
+func (c cachedReplicationControllers) List(ctx context.Context, opts metav1.ListOptions) (*corev1.ReplicationControllerList, error) {
+	items, ok, err := cachedList[corev1.ReplicationController](ctx, c.cache, corev1.SchemeGroupVersion.WithResource("replicationcontrollers"), c.namespace, opts)
+	if !ok {
+		return c.ReplicationControllerInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ReplicationControllerList{Items: items}, nil
+}
+
+func (c cachedCoreV1) Nodes() corev1client.NodeInterface {
+	return cachedNodes{NodeInterface: c.CoreV1Interface.Nodes(), cache: c.cache}
+}
+
+type cachedNodes struct {
+	corev1client.NodeInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedNodes) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Node, error) {
+	return cachedGet(ctx, c.cache, corev1.SchemeGroupVersion.WithResource("nodes"), c.namespace, name, opts, c.NodeInterface.Get)
+}
+
+func (c cachedNodes) List(ctx context.Context, opts metav1.ListOptions) (*corev1.NodeList, error) {
+	items, ok, err := cachedList[corev1.Node](ctx, c.cache, corev1.SchemeGroupVersion.WithResource("nodes"), c.namespace, opts)
+	if !ok {
+		return c.NodeInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.NodeList{Items: items}, nil
+}
+
+func (c cachedCoreV1) Namespaces() corev1client.NamespaceInterface {
+	return cachedNamespaces{NamespaceInterface: c.CoreV1Interface.Namespaces(), cache: c.cache}
+}
+
+type cachedNamespaces struct {
+	corev1client.NamespaceInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedNamespaces) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Namespace, error) {
+	return cachedGet(ctx, c.cache, corev1.SchemeGroupVersion.WithResource("namespaces"), c.namespace, name, opts, c.NamespaceInterface.Get)
+}
+
+func (c cachedNamespaces) List(ctx context.Context, opts metav1.ListOptions) (*corev1.NamespaceList, error) {
+	items, ok, err := cachedList[corev1.Namespace](ctx, c.cache, corev1.SchemeGroupVersion.WithResource("namespaces"), c.namespace, opts)
+	if !ok {
+		return c.NamespaceInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.NamespaceList{Items: items}, nil
+}
+
+func (c cachedCoreV1) PersistentVolumes() corev1client.PersistentVolumeInterface {
+	return cachedPersistentVolumes{PersistentVolumeInterface: c.CoreV1Interface.PersistentVolumes(), cache: c.cache}
+}
+
+type cachedPersistentVolumes struct {
+	corev1client.PersistentVolumeInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedPersistentVolumes) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.PersistentVolume, error) {
+	return cachedGet(ctx, c.cache, corev1.SchemeGroupVersion.WithResource("persistentvolumes"), c.namespace, name, opts, c.PersistentVolumeInterface.Get)
+}
+
+func (c cachedPersistentVolumes) List(ctx context.Context, opts metav1.ListOptions) (*corev1.PersistentVolumeList, error) {
+	items, ok, err := cachedList[corev1.PersistentVolume](ctx, c.cache, corev1.SchemeGroupVersion.WithResource("persistentvolumes"), c.namespace, opts)
+	if !ok {
+		return c.PersistentVolumeInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.PersistentVolumeList{Items: items}, nil
+}
+
+type cachedAppsV1 struct {
+	appsv1client.AppsV1Interface
+	cache *informerCache
+}
+
+func (c cachedClientset) AppsV1() appsv1client.AppsV1Interface {
+	return cachedAppsV1{AppsV1Interface: c.Interface.AppsV1(), cache: c.cache}
+}
+
+func (c cachedAppsV1) Deployments(namespace string) appsv1client.DeploymentInterface {
+	return cachedDeployments{DeploymentInterface: c.AppsV1Interface.Deployments(namespace), cache: c.cache, namespace: namespace}
+}
+
+type cachedDeployments struct {
+	appsv1client.DeploymentInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedDeployments) Get(ctx context.Context, name string, opts metav1.GetOptions) (*appsv1.Deployment, error) {
+	return cachedGet(ctx, c.cache, appsv1.SchemeGroupVersion.WithResource("deployments"), c.namespace, name, opts, c.DeploymentInterface.Get)
+}
+
+func (c cachedDeployments) List(ctx context.Context, opts metav1.ListOptions) (*appsv1.DeploymentList, error) {
+	items, ok, err := cachedList[appsv1.Deployment](ctx, c.cache, appsv1.SchemeGroupVersion.WithResource("deployments"), c.namespace, opts)
+	if !ok {
+		return c.DeploymentInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &appsv1.DeploymentList{Items: items}, nil
+}
+
+func (c cachedAppsV1) ReplicaSets(namespace string) appsv1client.ReplicaSetInterface {
+	return cachedReplicaSets{ReplicaSetInterface: c.AppsV1Interface.ReplicaSets(namespace), cache: c.cache, namespace: namespace}
+}
+
+type cachedReplicaSets struct {
+	appsv1client.ReplicaSetInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedReplicaSets) Get(ctx context.Context, name string, opts metav1.GetOptions) (*appsv1.ReplicaSet, error) {
+	return cachedGet(ctx, c.cache, appsv1.SchemeGroupVersion.WithResource("replicasets"), c.namespace, name, opts, c.ReplicaSetInterface.Get)
+}
+
+func (c cachedReplicaSets) List(ctx context.Context, opts metav1.ListOptions) (*appsv1.ReplicaSetList, error) {
+	items, ok, err := cachedList[appsv1.ReplicaSet](ctx, c.cache, appsv1.SchemeGroupVersion.WithResource("replicasets"), c.namespace, opts)
+	if !ok {
+		return c.ReplicaSetInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &appsv1.ReplicaSetList{Items: items}, nil
+}
+
+func (c cachedAppsV1) StatefulSets(namespace string) appsv1client.StatefulSetInterface {
+	return cachedStatefulSets{StatefulSetInterface: c.AppsV1Interface.StatefulSets(namespace), cache: c.cache, namespace: namespace}
+}
+
+type cachedStatefulSets struct {
+	appsv1client.StatefulSetInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedStatefulSets) Get(ctx context.Context, name string, opts metav1.GetOptions) (*appsv1.StatefulSet, error) {
+	return cachedGet(ctx, c.cache, appsv1.SchemeGroupVersion.WithResource("statefulsets"), c.namespace, name, opts, c.StatefulSetInterface.Get)
+}
+
+func (c cachedStatefulSets) List(ctx context.Context, opts metav1.ListOptions) (*appsv1.StatefulSetList, error) {
+	items, ok, err := cachedList[appsv1.StatefulSet](ctx, c.cache, appsv1.SchemeGroupVersion.WithResource("statefulsets"), c.namespace, opts)
+	if !ok {
+		return c.StatefulSetInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &appsv1.StatefulSetList{Items: items}, nil
+}
+
+func (c cachedAppsV1) DaemonSets(namespace string) appsv1client.DaemonSetInterface {
+	return cachedDaemonSets{DaemonSetInterface: c.AppsV1Interface.DaemonSets(namespace), cache: c.cache, namespace: namespace}
+}
+
+type cachedDaemonSets struct {
+	appsv1client.DaemonSetInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedDaemonSets) Get(ctx context.Context, name string, opts metav1.GetOptions) (*appsv1.DaemonSet, error) {
+	return cachedGet(ctx, c.cache, appsv1.SchemeGroupVersion.WithResource("daemonsets"), c.namespace, name, opts, c.DaemonSetInterface.Get)
+}
+
+func (c cachedDaemonSets) List(ctx context.Context, opts metav1.ListOptions) (*appsv1.DaemonSetList, error) {
+	items, ok, err := cachedList[appsv1.DaemonSet](ctx, c.cache, appsv1.SchemeGroupVersion.WithResource("daemonsets"), c.namespace, opts)
+	if !ok {
+		return c.DaemonSetInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &appsv1.DaemonSetList{Items: items}, nil
+}
+
+type cachedBatchV1 struct {
+	batchv1client.BatchV1Interface
+	cache *informerCache
+}
+
+func (c cachedClientset) BatchV1() batchv1client.BatchV1Interface {
+	return cachedBatchV1{BatchV1Interface: c.Interface.BatchV1(), cache: c.cache}
+}
+
+func (c cachedBatchV1) Jobs(namespace string) batchv1client.JobInterface {
+	return cachedJobs{JobInterface: c.BatchV1Interface.Jobs(namespace), cache: c.cache, namespace: namespace}
+}
+
+type cachedJobs struct {
+	batchv1client.JobInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedJobs) Get(ctx context.Context, name string, opts metav1.GetOptions) (*batchv1.Job, error) {
+	return cachedGet(ctx, c.cache, batchv1.SchemeGroupVersion.WithResource("jobs"), c.namespace, name, opts, c.JobInterface.Get)
+}
+
+func (c cachedJobs) List(ctx context.Context, opts metav1.ListOptions) (*batchv1.JobList, error) {
+	items, ok, err := cachedList[batchv1.Job](ctx, c.cache, batchv1.SchemeGroupVersion.WithResource("jobs"), c.namespace, opts)
+	if !ok {
+		return c.JobInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &batchv1.JobList{Items: items}, nil
+}
+
+func (c cachedBatchV1) CronJobs(namespace string) batchv1client.CronJobInterface {
+	return cachedCronJobs{CronJobInterface: c.BatchV1Interface.CronJobs(namespace), cache: c.cache, namespace: namespace}
+}
+
+type cachedCronJobs struct {
+	batchv1client.CronJobInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedCronJobs) Get(ctx context.Context, name string, opts metav1.GetOptions) (*batchv1.CronJob, error) {
+	return cachedGet(ctx, c.cache, batchv1.SchemeGroupVersion.WithResource("cronjobs"), c.namespace, name, opts, c.CronJobInterface.Get)
+}
+
+func (c cachedCronJobs) List(ctx context.Context, opts metav1.ListOptions) (*batchv1.CronJobList, error) {
+	items, ok, err := cachedList[batchv1.CronJob](ctx, c.cache, batchv1.SchemeGroupVersion.WithResource("cronjobs"), c.namespace, opts)
+	if !ok {
+		return c.CronJobInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &batchv1.CronJobList{Items: items}, nil
+}
+
+type cachedAutoscalingV2 struct {
+	autoscalingv2client.AutoscalingV2Interface
+	cache *informerCache
+}
+
+func (c cachedClientset) AutoscalingV2() autoscalingv2client.AutoscalingV2Interface {
+	return cachedAutoscalingV2{AutoscalingV2Interface: c.Interface.AutoscalingV2(), cache: c.cache}
+}
+
+func (c cachedAutoscalingV2) HorizontalPodAutoscalers(namespace string) autoscalingv2client.HorizontalPodAutoscalerInterface {
+	return cachedHorizontalPodAutoscalers{HorizontalPodAutoscalerInterface: c.AutoscalingV2Interface.HorizontalPodAutoscalers(namespace), cache: c.cache, namespace: namespace}
+}
+
+type cachedHorizontalPodAutoscalers struct {
+	autoscalingv2client.HorizontalPodAutoscalerInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedHorizontalPodAutoscalers) Get(ctx context.Context, name string, opts metav1.GetOptions) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	return cachedGet(ctx, c.cache, autoscalingv2.SchemeGroupVersion.WithResource("horizontalpodautoscalers"), c.namespace, name, opts, c.HorizontalPodAutoscalerInterface.Get)
+}
+
+func (c cachedHorizontalPodAutoscalers) List(ctx context.Context, opts metav1.ListOptions) (*autoscalingv2.HorizontalPodAutoscalerList, error) {
+	items, ok, err := cachedList[autoscalingv2.HorizontalPodAutoscaler](ctx, c.cache, autoscalingv2.SchemeGroupVersion.WithResource("horizontalpodautoscalers"), c.namespace, opts)
+	if !ok {
+		return c.HorizontalPodAutoscalerInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &autoscalingv2.HorizontalPodAutoscalerList{Items: items}, nil
+}
+
+type cachedNetworkingV1 struct {
+	networkingv1client.NetworkingV1Interface
+	cache *informerCache
+}
+
+func (c cachedClientset) NetworkingV1() networkingv1client.NetworkingV1Interface {
+	return cachedNetworkingV1{NetworkingV1Interface: c.Interface.NetworkingV1(), cache: c.cache}
+}
+
+func (c cachedNetworkingV1) Ingresses(namespace string) networkingv1client.IngressInterface {
+	return cachedIngresses{IngressInterface: c.NetworkingV1Interface.Ingresses(namespace), cache: c.cache, namespace: namespace}
+}
+
+type cachedIngresses struct {
+	networkingv1client.IngressInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedIngresses) Get(ctx context.Context, name string, opts metav1.GetOptions) (*networkingv1.Ingress, error) {
+	return cachedGet(ctx, c.cache, networkingv1.SchemeGroupVersion.WithResource("ingresses"), c.namespace, name, opts, c.IngressInterface.Get)
+}
+
+func (c cachedIngresses) List(ctx context.Context, opts metav1.ListOptions) (*networkingv1.IngressList, error) {
+	items, ok, err := cachedList[networkingv1.Ingress](ctx, c.cache, networkingv1.SchemeGroupVersion.WithResource("ingresses"), c.namespace, opts)
+	if !ok {
+		return c.IngressInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &networkingv1.IngressList{Items: items}, nil
+}
+
+func (c cachedNetworkingV1) NetworkPolicies(namespace string) networkingv1client.NetworkPolicyInterface {
+	return cachedNetworkPolicies{NetworkPolicyInterface: c.NetworkingV1Interface.NetworkPolicies(namespace), cache: c.cache, namespace: namespace}
+}
+
+type cachedNetworkPolicies struct {
+	networkingv1client.NetworkPolicyInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedNetworkPolicies) Get(ctx context.Context, name string, opts metav1.GetOptions) (*networkingv1.NetworkPolicy, error) {
+	return cachedGet(ctx, c.cache, networkingv1.SchemeGroupVersion.WithResource("networkpolicies"), c.namespace, name, opts, c.NetworkPolicyInterface.Get)
+}
+
+func (c cachedNetworkPolicies) List(ctx context.Context, opts metav1.ListOptions) (*networkingv1.NetworkPolicyList, error) {
+	items, ok, err := cachedList[networkingv1.NetworkPolicy](ctx, c.cache, networkingv1.SchemeGroupVersion.WithResource("networkpolicies"), c.namespace, opts)
+	if !ok {
+		return c.NetworkPolicyInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &networkingv1.NetworkPolicyList{Items: items}, nil
+}
+
+func (c cachedNetworkingV1) IngressClasses() networkingv1client.IngressClassInterface {
+	return cachedIngressClasses{IngressClassInterface: c.NetworkingV1Interface.IngressClasses(), cache: c.cache}
+}
+
+type cachedIngressClasses struct {
+	networkingv1client.IngressClassInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedIngressClasses) Get(ctx context.Context, name string, opts metav1.GetOptions) (*networkingv1.IngressClass, error) {
+	return cachedGet(ctx, c.cache, networkingv1.SchemeGroupVersion.WithResource("ingressclasses"), c.namespace, name, opts, c.IngressClassInterface.Get)
+}
+
+func (c cachedIngressClasses) List(ctx context.Context, opts metav1.ListOptions) (*networkingv1.IngressClassList, error) {
+	items, ok, err := cachedList[networkingv1.IngressClass](ctx, c.cache, networkingv1.SchemeGroupVersion.WithResource("ingressclasses"), c.namespace, opts)
+	if !ok {
+		return c.IngressClassInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &networkingv1.IngressClassList{Items: items}, nil
+}
+
+type cachedPolicyV1 struct {
+	policyv1client.PolicyV1Interface
+	cache *informerCache
+}
+
+func (c cachedClientset) PolicyV1() policyv1client.PolicyV1Interface {
+	return cachedPolicyV1{PolicyV1Interface: c.Interface.PolicyV1(), cache: c.cache}
+}
+
+func (c cachedPolicyV1) PodDisruptionBudgets(namespace string) policyv1client.PodDisruptionBudgetInterface {
+	return cachedPodDisruptionBudgets{PodDisruptionBudgetInterface: c.PolicyV1Interface.PodDisruptionBudgets(namespace), cache: c.cache, namespace: namespace}
+}
+
+type cachedPodDisruptionBudgets struct {
+	policyv1client.PodDisruptionBudgetInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedPodDisruptionBudgets) Get(ctx context.Context, name string, opts metav1.GetOptions) (*policyv1.PodDisruptionBudget, error) {
+	return cachedGet(ctx, c.cache, policyv1.SchemeGroupVersion.WithResource("poddisruptionbudgets"), c.namespace, name, opts, c.PodDisruptionBudgetInterface.Get)
+}
+
+func (c cachedPodDisruptionBudgets) List(ctx context.Context, opts metav1.ListOptions) (*policyv1.PodDisruptionBudgetList, error) {
+	items, ok, err := cachedList[policyv1.PodDisruptionBudget](ctx, c.cache, policyv1.SchemeGroupVersion.WithResource("poddisruptionbudgets"), c.namespace, opts)
+	if !ok {
+		return c.PodDisruptionBudgetInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &policyv1.PodDisruptionBudgetList{Items: items}, nil
+}
+
+type cachedStorageV1 struct {
+	storagev1client.StorageV1Interface
+	cache *informerCache
+}
+
+func (c cachedClientset) StorageV1() storagev1client.StorageV1Interface {
+	return cachedStorageV1{StorageV1Interface: c.Interface.StorageV1(), cache: c.cache}
+}
+
+func (c cachedStorageV1) StorageClasses() storagev1client.StorageClassInterface {
+	return cachedStorageClasses{StorageClassInterface: c.StorageV1Interface.StorageClasses(), cache: c.cache}
+}
+
+type cachedStorageClasses struct {
+	storagev1client.StorageClassInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedStorageClasses) Get(ctx context.Context, name string, opts metav1.GetOptions) (*storagev1.StorageClass, error) {
+	return cachedGet(ctx, c.cache, storagev1.SchemeGroupVersion.WithResource("storageclasses"), c.namespace, name, opts, c.StorageClassInterface.Get)
+}
+
+func (c cachedStorageClasses) List(ctx context.Context, opts metav1.ListOptions) (*storagev1.StorageClassList, error) {
+	items, ok, err := cachedList[storagev1.StorageClass](ctx, c.cache, storagev1.SchemeGroupVersion.WithResource("storageclasses"), c.namespace, opts)
+	if !ok {
+		return c.StorageClassInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &storagev1.StorageClassList{Items: items}, nil
+}
+
+func (c cachedStorageV1) VolumeAttachments() storagev1client.VolumeAttachmentInterface {
+	return cachedVolumeAttachments{VolumeAttachmentInterface: c.StorageV1Interface.VolumeAttachments(), cache: c.cache}
+}
+
+type cachedVolumeAttachments struct {
+	storagev1client.VolumeAttachmentInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedVolumeAttachments) Get(ctx context.Context, name string, opts metav1.GetOptions) (*storagev1.VolumeAttachment, error) {
+	return cachedGet(ctx, c.cache, storagev1.SchemeGroupVersion.WithResource("volumeattachments"), c.namespace, name, opts, c.VolumeAttachmentInterface.Get)
+}
+
+func (c cachedVolumeAttachments) List(ctx context.Context, opts metav1.ListOptions) (*storagev1.VolumeAttachmentList, error) {
+	items, ok, err := cachedList[storagev1.VolumeAttachment](ctx, c.cache, storagev1.SchemeGroupVersion.WithResource("volumeattachments"), c.namespace, opts)
+	if !ok {
+		return c.VolumeAttachmentInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &storagev1.VolumeAttachmentList{Items: items}, nil
+}
+
+type cachedAdmissionregistrationV1 struct {
+	admissionregistrationv1client.AdmissionregistrationV1Interface
+	cache *informerCache
+}
+
+func (c cachedClientset) AdmissionregistrationV1() admissionregistrationv1client.AdmissionregistrationV1Interface {
+	return cachedAdmissionregistrationV1{AdmissionregistrationV1Interface: c.Interface.AdmissionregistrationV1(), cache: c.cache}
+}
+
+func (c cachedAdmissionregistrationV1) ValidatingWebhookConfigurations() admissionregistrationv1client.ValidatingWebhookConfigurationInterface {
+	return cachedValidatingWebhookConfigurations{ValidatingWebhookConfigurationInterface: c.AdmissionregistrationV1Interface.ValidatingWebhookConfigurations(), cache: c.cache}
+}
+
+type cachedValidatingWebhookConfigurations struct {
+	admissionregistrationv1client.ValidatingWebhookConfigurationInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedValidatingWebhookConfigurations) Get(ctx context.Context, name string, opts metav1.GetOptions) (*admissionregistrationv1.ValidatingWebhookConfiguration, error) {
+	return cachedGet(ctx, c.cache, admissionregistrationv1.SchemeGroupVersion.WithResource("validatingwebhookconfigurations"), c.namespace, name, opts, c.ValidatingWebhookConfigurationInterface.Get)
+}
+
+func (c cachedValidatingWebhookConfigurations) List(ctx context.Context, opts metav1.ListOptions) (*admissionregistrationv1.ValidatingWebhookConfigurationList, error) {
+	items, ok, err := cachedList[admissionregistrationv1.ValidatingWebhookConfiguration](ctx, c.cache, admissionregistrationv1.SchemeGroupVersion.WithResource("validatingwebhookconfigurations"), c.namespace, opts)
+	if !ok {
+		return c.ValidatingWebhookConfigurationInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &admissionregistrationv1.ValidatingWebhookConfigurationList{Items: items}, nil
+}
+
+func (c cachedAdmissionregistrationV1) MutatingWebhookConfigurations() admissionregistrationv1client.MutatingWebhookConfigurationInterface {
+	return cachedMutatingWebhookConfigurations{MutatingWebhookConfigurationInterface: c.AdmissionregistrationV1Interface.MutatingWebhookConfigurations(), cache: c.cache}
+}
+
+type cachedMutatingWebhookConfigurations struct {
+	admissionregistrationv1client.MutatingWebhookConfigurationInterface
+	cache     *informerCache
+	namespace string
+}
+
+func (c cachedMutatingWebhookConfigurations) Get(ctx context.Context, name string, opts metav1.GetOptions) (*admissionregistrationv1.MutatingWebhookConfiguration, error) {
+	return cachedGet(ctx, c.cache, admissionregistrationv1.SchemeGroupVersion.WithResource("mutatingwebhookconfigurations"), c.namespace, name, opts, c.MutatingWebhookConfigurationInterface.Get)
+}
+
+func (c cachedMutatingWebhookConfigurations) List(ctx context.Context, opts metav1.ListOptions) (*admissionregistrationv1.MutatingWebhookConfigurationList, error) {
+	items, ok, err := cachedList[admissionregistrationv1.MutatingWebhookConfiguration](ctx, c.cache, admissionregistrationv1.SchemeGroupVersion.WithResource("mutatingwebhookconfigurations"), c.namespace, opts)
+	if !ok {
+		return c.MutatingWebhookConfigurationInterface.List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &admissionregistrationv1.MutatingWebhookConfigurationList{Items: items}, nil
+}
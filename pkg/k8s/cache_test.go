@@ -0,0 +1,77 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// countListCalls returns the number of list calls the fake clientset served for a resource
+func countListCalls(clientset *fake.Clientset, resource string) int {
+	count := 0
+	for _, action := range clientset.Actions() {
+		if action.GetVerb() == "list" && action.GetResource().Resource == resource {
+			count++
+		}
+	}
+	return count
+}
+
+func TestCache(t *testing.T) {
+	t.Run("Analyzers read from the cache", func(t *testing.T) {
+		clientset := newFailingPodsClientset()
+		k := newTestKubernetes(clientset, newDynamicClient())
+		k.EnableCache()
+
+		for i := 0; i < 3; i++ {
+			result, err := k.AnalyzePod(context.Background(), "test-namespace")
+			assert.NoError(t, err)
+			assert.Contains(t, result, "test-namespace/crashloop-pod")
+		}
+		assert.Equal(t, 1, countListCalls(clientset, "pods"), "only the informer should list pods")
+
+		pod, err := k.clientset.CoreV1().Pods("test-namespace").Get(context.Background(), "pending-pod", metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, v1.PodPending, pod.Status.Phase)
+
+		status := k.CacheStatus()
+		if assert.Len(t, status, 1) {
+			assert.Equal(t, "/v1, Resource=pods", status[0].Resource)
+			assert.True(t, status[0].Synced)
+			assert.False(t, status[0].Stale)
+		}
+	})
+
+	t.Run("Field selectors go to the API server", func(t *testing.T) {
+		clientset := newFailingPodsClientset()
+		k := newTestKubernetes(clientset, nil)
+		k.EnableCache()
+
+		_, err := k.clientset.CoreV1().Pods("test-namespace").List(context.Background(), metav1.ListOptions{FieldSelector: "spec.nodeName=node-1"})
+		assert.NoError(t, err)
+		assert.Empty(t, k.CacheStatus(), "no informer should be started")
+	})
+
+	t.Run("Resource tools read from the cache", func(t *testing.T) {
+		k := newTestKubernetes(fake.NewSimpleClientset(), newDynamicClient())
+		k.EnableCache()
+
+		result, err := k.ResourceList(common.Request{Context: context.Background(), Kind: "Deployment", Namespace: "test-namespace"})
+		assert.NoError(t, err)
+		assert.Contains(t, result, "test-deployment")
+
+		result, err = k.ResourceGet(common.Request{Context: context.Background(), Kind: "Deployment", Namespace: "test-namespace", Name: "test-deployment"})
+		assert.NoError(t, err)
+		assert.Contains(t, result, "test-deployment")
+
+		status := k.CacheStatus()
+		if assert.Len(t, status, 1) {
+			assert.True(t, status[0].Dynamic)
+		}
+	})
+}
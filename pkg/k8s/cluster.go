@@ -0,0 +1,295 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	"golang.org/x/sync/errgroup"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const defaultClusterScanConcurrency = 8
+
+// AnalyzeCluster runs every registered analyzer across the selected namespaces with bounded concurrency and returns
+// a report grouped by namespace and root owner, ranked by severity.
+func (k *Kubernetes) AnalyzeCluster(r common.Request, opts common.ClusterScanOptions) (string, error) {
+	report, err := k.ScanCluster(r, opts)
+	if err != nil {
+		return "", err
+	}
+	jsonData, err := json.Marshal(report)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonData), nil
+}
+
+// ScanCluster is AnalyzeCluster returning the report instead of its JSON.
+func (k *Kubernetes) ScanCluster(r common.Request, opts common.ClusterScanOptions) (common.ClusterReport, error) {
+	if opts.MinSeverity != "" && severityRank[opts.MinSeverity] == 0 {
+		return common.ClusterReport{}, fmt.Errorf("unknown severity %s, expected %s, %s or %s", opts.MinSeverity, common.SeverityCritical, common.SeverityWarning, common.SeverityInfo)
+	}
+	namespaces := opts.Namespaces
+	if len(namespaces) == 0 {
+		nsList, err := k.clientset.CoreV1().Namespaces().List(r.Context, metav1.ListOptions{})
+		if err != nil {
+			return common.ClusterReport{}, err
+		}
+		for _, ns := range nsList.Items {
+			namespaces = append(namespaces, ns.Name)
+		}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultClusterScanConcurrency
+	}
+
+	analyzers := k.Analyzers()
+	// remaining counts the analyzers still to run in each namespace, the cluster scoped ones are counted under ""
+	remaining := map[string]int{}
+	for _, a := range analyzers {
+		if !a.Namespaced() {
+			remaining[""]++
+			continue
+		}
+		for _, ns := range namespaces {
+			remaining[ns]++
+		}
+	}
+	var total int
+	for _, n := range remaining {
+		total += n
+	}
+
+	var (
+		lock     sync.Mutex
+		results  []common.Result
+		errs     []string
+		failed   []common.FailedAnalyzer
+		done     int
+		analyzed int
+	)
+	run := func(ctx context.Context, a Analyzer, namespace string) {
+		found, err := a.Analyze(ctx, common.Request{Context: ctx, Namespace: namespace})
+
+		lock.Lock()
+		defer lock.Unlock()
+		done++
+		if remaining[namespace]--; remaining[namespace] == 0 && namespace != "" {
+			analyzed++
+		}
+		if r.Progress != nil {
+			r.Progress(done, total, fmt.Sprintf("analyzed %d/%d namespaces", analyzed, len(namespaces)))
+		}
+		if err != nil {
+			scope := "cluster"
+			if a.Namespaced() {
+				scope = "namespace " + namespace
+			}
+			errs = append(errs, fmt.Sprintf("%s analyzer in %s: %v", a.Name(), scope, err))
+			failed = append(failed, common.FailedAnalyzer{Analyzer: a.Name(), Namespace: namespace})
+			return
+		}
+		for i := range found {
+			found[i].Analyzer = a.Name()
+		}
+		results = append(results, found...)
+	}
+
+	g, ctx := errgroup.WithContext(withScanState(r.Context))
+	g.SetLimit(concurrency)
+	for _, a := range analyzers {
+		if !a.Namespaced() {
+			g.Go(func() error {
+				run(ctx, a, "")
+				return ctx.Err()
+			})
+			continue
+		}
+		for _, ns := range namespaces {
+			g.Go(func() error {
+				run(ctx, a, ns)
+				return ctx.Err()
+			})
+		}
+	}
+	if err := g.Wait(); err != nil {
+		return common.ClusterReport{}, err
+	}
+
+	report := buildClusterReport(filterResults(rankResults(results), opts.MinSeverity))
+	sort.Strings(errs)
+	report.Errors = errs
+	sort.Slice(failed, func(i, j int) bool {
+		if failed[i].Analyzer != failed[j].Analyzer {
+			return failed[i].Analyzer < failed[j].Analyzer
+		}
+		return failed[i].Namespace < failed[j].Namespace
+	})
+	report.FailedAnalyzers = failed
+	return report, nil
+}
+
+// scanState is shared by the analyzers of a cluster scan through the context, the namespaced analyzers read the
+// cluster-scoped objects they need once per scan rather than once per namespace.
+type scanState struct {
+	mu    sync.Mutex
+	lists map[string]*scanList
+}
+
+type scanList struct {
+	once sync.Once
+	list any
+	err  error
+}
+
+type scanStateKey struct{}
+
+func withScanState(ctx context.Context) context.Context {
+	return context.WithValue(ctx, scanStateKey{}, &scanState{lists: map[string]*scanList{}})
+}
+
+func inClusterScan(ctx context.Context) bool {
+	_, ok := ctx.Value(scanStateKey{}).(*scanState)
+	return ok
+}
+
+// clusterList returns the list of cluster-scoped objects load reads. During a cluster scan it is read once under
+// key and shared by the analyzers of every namespace, first reports whether the caller read it, it is read on every
+// call otherwise.
+func clusterList[T any](ctx context.Context, key string, load func() (T, error)) (list T, first bool, err error) {
+	state, ok := ctx.Value(scanStateKey{}).(*scanState)
+	if !ok {
+		list, err = load()
+		return list, true, err
+	}
+	state.mu.Lock()
+	entry, ok := state.lists[key]
+	if !ok {
+		entry = &scanList{}
+		state.lists[key] = entry
+	}
+	state.mu.Unlock()
+
+	entry.once.Do(func() {
+		first = true
+		entry.list, entry.err = load()
+	})
+	list, _ = entry.list.(T)
+	return list, first, entry.err
+}
+
+// buildClusterReport groups results under the namespace and root owner of each object, findings of owned objects
+// are prefixed with the object and deduplicated against the ones the owner analyzer already rolled up.
+func buildClusterReport(results []common.Result) common.ClusterReport {
+	type root struct {
+		namespace string
+		object    string
+	}
+	findings := map[root][]common.Finding{}
+	// seen indexes the findings of each root by text
+	seen := map[root]map[string]int{}
+
+	for _, result := range results {
+		namespace, name := splitResultName(result.Name)
+		object := fmt.Sprintf("%s/%s", result.Kind, name)
+		key := root{namespace: namespace, object: object}
+		if result.ParentObject != "" {
+			key.object = result.ParentObject
+			// a cluster scoped object owned by a namespaced one names it as Kind/namespace/name
+			if parts := strings.SplitN(result.ParentObject, "/", 3); len(parts) == 3 {
+				key = root{namespace: parts[1], object: parts[0] + "/" + parts[2]}
+			}
+		}
+		if seen[key] == nil {
+			seen[key] = map[string]int{}
+		}
+
+		for _, failure := range result.Error {
+			text := failure.Text
+			var subject string
+			if key.object != object {
+				text = fmt.Sprintf("%s %s: %s", result.Kind, result.Name, text)
+				subject = object
+			}
+			if i, ok := seen[key][text]; ok {
+				// a finding the owner rolled up is about the owned object reporting it too
+				if findings[key][i].Subject == "" {
+					findings[key][i].Subject = subject
+				}
+				continue
+			}
+			seen[key][text] = len(findings[key])
+			findings[key] = append(findings[key], common.Finding{
+				ID:          failure.ID,
+				Severity:    failureSeverity(failure),
+				Text:        text,
+				Remediation: failure.Remediation,
+				Subject:     subject,
+				FieldPath:   failure.FieldPath,
+				Analyzer:    result.Analyzer,
+			})
+		}
+	}
+
+	var report common.ClusterReport
+	byNamespace := map[string][]common.ObjectReport{}
+	for key, list := range findings {
+		sort.SliceStable(list, func(i, j int) bool {
+			if severityRank[list[i].Severity] != severityRank[list[j].Severity] {
+				return severityRank[list[i].Severity] > severityRank[list[j].Severity]
+			}
+			return list[i].Text < list[j].Text
+		})
+		for _, f := range list {
+			switch f.Severity {
+			case common.SeverityCritical:
+				report.Summary.Critical++
+			case common.SeverityWarning:
+				report.Summary.Warning++
+			default:
+				report.Summary.Info++
+			}
+		}
+		byNamespace[key.namespace] = append(byNamespace[key.namespace], common.ObjectReport{
+			Object:   key.object,
+			Severity: list[0].Severity,
+			Findings: list,
+		})
+		report.Summary.Objects++
+	}
+
+	for namespace, objects := range byNamespace {
+		sort.Slice(objects, func(i, j int) bool {
+			if severityRank[objects[i].Severity] != severityRank[objects[j].Severity] {
+				return severityRank[objects[i].Severity] > severityRank[objects[j].Severity]
+			}
+			if len(objects[i].Findings) != len(objects[j].Findings) {
+				return len(objects[i].Findings) > len(objects[j].Findings)
+			}
+			return objects[i].Object < objects[j].Object
+		})
+		report.Namespaces = append(report.Namespaces, common.NamespaceReport{
+			Namespace: namespace,
+			Objects:   objects,
+		})
+	}
+	// the most severe namespaces first, cluster scoped objects sort as the empty namespace
+	sort.Slice(report.Namespaces, func(i, j int) bool {
+		a, b := report.Namespaces[i], report.Namespaces[j]
+		if severityRank[a.Objects[0].Severity] != severityRank[b.Objects[0].Severity] {
+			return severityRank[a.Objects[0].Severity] > severityRank[b.Objects[0].Severity]
+		}
+		return a.Namespace < b.Namespace
+	})
+	report.Summary.Namespaces = len(report.Namespaces)
+	if report.Namespaces == nil {
+		report.Namespaces = []common.NamespaceReport{}
+	}
+	return report
+}
@@ -0,0 +1,172 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBuildClusterReport(t *testing.T) {
+	results := []common.Result{
+		{
+			Kind: "Deployment",
+			Name: "shop/api",
+			Error: []common.Failure{
+				{ID: "DEPLOYMENT_REPLICAS_UNAVAILABLE", Severity: common.SeverityWarning, Text: "Only 1/3 replicas available"},
+				{ID: "POD_IMAGE_PULL", Severity: common.SeverityCritical, Text: "Pod shop/api-7c9-x2k: ImagePullBackOff: Back-off pulling image \"api:2.0\""},
+				{ID: "DEPLOYMENT_NO_RESOURCE_REQUESTS", Severity: common.SeverityInfo, Text: "Container api has no resource requests, the scheduler can overcommit the node"},
+			},
+		},
+		{
+			Kind:         "Pod",
+			Name:         "shop/api-7c9-x2k",
+			ParentObject: "Deployment/api",
+			Error:        []common.Failure{{ID: "POD_IMAGE_PULL", Severity: common.SeverityCritical, Text: "ImagePullBackOff: Back-off pulling image \"api:2.0\""}},
+		},
+		{
+			Kind:  "CronJob",
+			Name:  "shop/report",
+			Error: []common.Failure{{ID: "CRONJOB_NEVER_SCHEDULED", Severity: common.SeverityWarning, Text: "CronJob has never been scheduled"}},
+		},
+		{
+			Kind:  "Node",
+			Name:  "worker-1",
+			Error: []common.Failure{{ID: "NODE_NOT_READY", Severity: common.SeverityCritical, Text: "worker-1 condition type Ready is False, reason KubeletNotReady: PLEG is not healthy"}},
+		},
+		{
+			Kind:  "CronJob",
+			Name:  "batch/cleanup",
+			Error: []common.Failure{{ID: "CRONJOB_SUSPENDED", Severity: common.SeverityInfo, Text: "CronJob batch/cleanup is suspended"}},
+		},
+		{
+			Kind:         "VolumeAttachment",
+			Name:         "csi-123",
+			ParentObject: "PersistentVolumeClaim/batch/data",
+			Error:        []common.Failure{{ID: "VOLUME_DETACH_FAILED", Severity: common.SeverityWarning, Text: "Detaching PersistentVolume pv-data from node worker-1 failed: timeout"}},
+		},
+	}
+
+	report := buildClusterReport(results)
+	assert.Equal(t, common.ClusterSummary{Namespaces: 3, Objects: 5, Critical: 2, Warning: 3, Info: 2}, report.Summary)
+
+	// critical namespaces first, alphabetical within the same severity
+	assert.Equal(t, "", report.Namespaces[0].Namespace)
+	assert.Equal(t, "shop", report.Namespaces[1].Namespace)
+	assert.Equal(t, "batch", report.Namespaces[2].Namespace)
+
+	shop := report.Namespaces[1].Objects
+	assert.Equal(t, "Deployment/api", shop[0].Object)
+	assert.Equal(t, common.SeverityCritical, shop[0].Severity)
+	// the pod finding is deduplicated against the one rolled up by the deployment analyzer
+	assert.Len(t, shop[0].Findings, 3)
+	assert.Equal(t, "Pod/api-7c9-x2k", shop[0].Findings[0].Subject)
+	assert.Empty(t, shop[0].Findings[1].Subject)
+	assert.Equal(t, common.SeverityInfo, shop[0].Findings[2].Severity)
+	assert.Equal(t, "CronJob/report", shop[1].Object)
+	assert.Equal(t, common.SeverityWarning, shop[1].Severity)
+
+	// the cluster scoped attachment is reported under the claim of its volume
+	batch := report.Namespaces[2].Objects
+	assert.Equal(t, "PersistentVolumeClaim/data", batch[0].Object)
+	assert.Equal(t, "VolumeAttachment/csi-123", batch[0].Findings[0].Subject)
+}
+
+func TestAnalyzeCluster(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "empty"}},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{{
+					Name: "web",
+					State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{
+						Reason:  "CrashLoopBackOff",
+						Message: "back-off 5m0s restarting failed container",
+					}},
+					LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled"}},
+				}},
+			},
+		},
+	)
+	k := newTestKubernetes(clientset, nil)
+
+	res, err := k.AnalyzeCluster(common.Request{Context: context.Background()}, common.ClusterScanOptions{Concurrency: 2})
+	assert.NoError(t, err)
+
+	var report common.ClusterReport
+	assert.NoError(t, json.Unmarshal([]byte(res), &report))
+	assert.Empty(t, report.Errors)
+	assert.Len(t, report.Namespaces, 1)
+	assert.Equal(t, "shop", report.Namespaces[0].Namespace)
+	assert.Equal(t, "Pod/web", report.Namespaces[0].Objects[0].Object)
+	assert.Contains(t, report.Namespaces[0].Objects[0].Findings[0].Text, "OOMKilled")
+	assert.Equal(t, "pod", report.Namespaces[0].Objects[0].Findings[0].Analyzer)
+}
+
+func TestScanClusterProgress(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "empty"}},
+	)
+	k := newTestKubernetes(clientset, nil)
+
+	var progress []int
+	var last string
+	r := common.Request{Context: context.Background(), Progress: func(p, total int, message string) {
+		assert.Equal(t, len(k.Analyzers())*2-countClusterAnalyzers(k), total)
+		progress = append(progress, p)
+		last = message
+	}}
+	_, err := k.ScanCluster(r, common.ClusterScanOptions{Concurrency: 2})
+	assert.NoError(t, err)
+	for i, p := range progress {
+		assert.Equal(t, i+1, p, "progress should increase by one with every analyzer run")
+	}
+	assert.Equal(t, "analyzed 2/2 namespaces", last)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = k.ScanCluster(common.Request{Context: ctx}, common.ClusterScanOptions{Namespaces: []string{"shop"}})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func countClusterAnalyzers(k *Kubernetes) int {
+	var n int
+	for _, a := range k.Analyzers() {
+		if !a.Namespaced() {
+			n++
+		}
+	}
+	return n
+}
+
+func TestScanClusterListsClusterScopedObjectsOnce(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "staging"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "empty"}},
+	)
+	k := newTestKubernetes(clientset, nil)
+
+	_, err := k.ScanCluster(common.Request{Context: context.Background()}, common.ClusterScanOptions{Concurrency: 3})
+	assert.NoError(t, err)
+	lists := map[string]int{}
+	for _, action := range clientset.Actions() {
+		if action.GetVerb() == "list" {
+			lists[action.GetResource().Resource]++
+		}
+	}
+	assert.Equal(t, 1, lists["persistentvolumes"])
+	assert.Equal(t, 1, lists["storageclasses"])
+	assert.Equal(t, 1, lists["volumeattachments"])
+	// once by the node analyzer and once for the daemonset analyzers of all namespaces
+	assert.Equal(t, 2, lists["nodes"])
+}
@@ -0,0 +1,135 @@
+package k8s
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+)
+
+// completionTTL bounds how long the values suggested for an argument are served from memory, completions are
+// requested on every keystroke and must not list the cluster each time.
+const completionTTL = 30 * time.Second
+
+type completionCache struct {
+	mu      sync.Mutex
+	entries map[string]completionEntry
+}
+
+type completionEntry struct {
+	values  []string
+	expires time.Time
+}
+
+// get returns the values cached under key, loading them when they are missing or expired.
+func (c *completionCache) get(key string, load func() ([]string, error)) ([]string, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.values, nil
+	}
+
+	values, err := load()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(values)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = map[string]completionEntry{}
+	}
+	c.entries[key] = completionEntry{values: values, expires: time.Now().Add(completionTTL)}
+	return values, nil
+}
+
+// CompleteNamespaces returns the namespaces starting with prefix.
+func (k *Kubernetes) CompleteNamespaces(ctx context.Context, prefix string) ([]string, error) {
+	values, err := k.completions.get("namespaces", func() ([]string, error) {
+		list, err := k.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(list.Items))
+		for _, ns := range list.Items {
+			names = append(names, ns.Name)
+		}
+		return names, nil
+	})
+	return withPrefix(values, prefix), err
+}
+
+// CompleteKinds returns the kinds served by the cluster, custom resources included, whose kind starts with prefix.
+// The prefix is case insensitive. The kinds are discovered again when they expire so the kinds served since are
+// completed too, without the discovery cache shared with resolveKind which refreshes itself on a missing kind.
+func (k *Kubernetes) CompleteKinds(prefix string) ([]string, error) {
+	values, err := k.completions.get("kinds", func() ([]string, error) {
+		lists, err := k.discoveryClient.ServerPreferredResources()
+		if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+			return nil, err
+		}
+		seen := map[string]bool{}
+		var kinds []string
+		for _, list := range lists {
+			for _, resource := range list.APIResources {
+				if strings.Contains(resource.Name, "/") || seen[resource.Kind] {
+					continue
+				}
+				seen[resource.Kind] = true
+				kinds = append(kinds, resource.Kind)
+			}
+		}
+		return kinds, nil
+	})
+	var matches []string
+	for _, kind := range values {
+		if strings.HasPrefix(strings.ToLower(kind), strings.ToLower(prefix)) {
+			matches = append(matches, kind)
+		}
+	}
+	return matches, err
+}
+
+// CompleteNames returns the names of the objects of r.Kind in r.Namespace starting with prefix, the namespace is
+// ignored for cluster-scoped kinds. The kind is resolved from the discovery cache without refreshing it, an unknown
+// kind must not discover the cluster on every keystroke.
+func (k *Kubernetes) CompleteNames(r common.Request, prefix string) ([]string, error) {
+	mapping, err := k.mapKind(r.Kind, r.APIVersion, false)
+	if err != nil {
+		return nil, err
+	}
+	namespace := ""
+	if isNamespaced(mapping) {
+		namespace = utils.NamespaceOrDefault(r.Namespace)
+	}
+	key := "names/" + mapping.Resource.String() + "/" + namespace
+	values, err := k.completions.get(key, func() ([]string, error) {
+		list, err := k.dynamicClient.Resource(mapping.Resource).Namespace(namespace).List(r.Context, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(list.Items))
+		for _, obj := range list.Items {
+			names = append(names, obj.GetName())
+		}
+		return names, nil
+	})
+	return withPrefix(values, prefix), err
+}
+
+func withPrefix(values []string, prefix string) []string {
+	var matches []string
+	for _, value := range values {
+		if strings.HasPrefix(value, prefix) {
+			matches = append(matches, value)
+		}
+	}
+	return matches
+}
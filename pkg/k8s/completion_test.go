@@ -0,0 +1,91 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestComplete(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "staging"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+	)
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+		newDeleteDeployment("web-1", "web"),
+		newDeleteDeployment("web-2", "web"),
+		newDeleteDeployment("api", "api"),
+	)
+	k := newTestKubernetes(clientset, dynamicClient)
+
+	t.Run("Namespaces", func(t *testing.T) {
+		values, err := k.CompleteNamespaces(context.Background(), "s")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"shop", "staging"}, values)
+	})
+
+	t.Run("Kinds", func(t *testing.T) {
+		values, err := k.CompleteKinds("dep")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Deployment"}, values)
+	})
+
+	t.Run("Names are cached", func(t *testing.T) {
+		r := common.Request{Context: context.Background(), Kind: "deploy", Namespace: "default"}
+		values, err := k.CompleteNames(r, "web")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"web-1", "web-2"}, values)
+
+		_, err = dynamicClient.Resource(deploymentsResource).Namespace("default").Create(context.Background(), newDeleteDeployment("web-3", "web"), metav1.CreateOptions{})
+		assert.NoError(t, err)
+		values, err = k.CompleteNames(r, "web")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"web-1", "web-2"}, values, "names should be served from the cache until it expires")
+	})
+
+	t.Run("Names of an unknown kind do not refresh the discovery", func(t *testing.T) {
+		discovery := newKindDiscovery()
+		useDiscovery(k, discovery)
+		_, err := k.CompleteNames(common.Request{Context: context.Background(), Kind: "deploy"}, "")
+		assert.NoError(t, err)
+
+		discovery.lock.Lock()
+		discovery.resourceMap["apps/v1"] = &resourceMapEntry{list: &metav1.APIResourceList{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+			{Name: "deployments", SingularName: "deployment", Namespaced: true, Kind: "Deployment", ShortNames: []string{"deploy"}},
+			{Name: "statefulsets", SingularName: "statefulset", Namespaced: true, Kind: "StatefulSet", ShortNames: []string{"sts"}},
+		}}}
+		discovery.lock.Unlock()
+		_, err = k.CompleteNames(common.Request{Context: context.Background(), Kind: "sts"}, "")
+		assert.Error(t, err, "the kind should be resolved from the cache only")
+		_, err = k.resolveKind("sts", "")
+		assert.NoError(t, err, "a tool call should refresh the cache")
+	})
+
+	t.Run("Expired kinds do not invalidate the discovery cache", func(t *testing.T) {
+		discovery := newKindDiscovery()
+		useDiscovery(k, discovery)
+		k.completions = completionCache{}
+		_, err := k.CompleteNames(common.Request{Context: context.Background(), Kind: "deploy"}, "")
+		assert.NoError(t, err)
+
+		discovery.lock.Lock()
+		discovery.resourceMap["apps/v1"] = &resourceMapEntry{list: &metav1.APIResourceList{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+			{Name: "deployments", SingularName: "deployment", Namespaced: true, Kind: "Deployment", ShortNames: []string{"deploy"}},
+			{Name: "statefulsets", SingularName: "statefulset", Namespaced: true, Kind: "StatefulSet", ShortNames: []string{"sts"}},
+		}}}
+		discovery.lock.Unlock()
+		values, err := k.CompleteKinds("state")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"StatefulSet"}, values)
+		_, err = k.CompleteNames(common.Request{Context: context.Background(), Kind: "sts"}, "")
+		assert.Error(t, err, "completing the kinds should not refresh the shared cache")
+	})
+}
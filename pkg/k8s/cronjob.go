@@ -1,7 +1,7 @@
 package k8s
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 
 	cron "github.com/robfig/cron/v3"
@@ -12,6 +12,10 @@ import (
 
 // AnalyzeCronJob analyzes the cronjobs and returns a list of failures.
 func (k *Kubernetes) AnalyzeCronJob(r common.Request) (string, error) {
+	return marshalResults(k.analyzeCronJob(r.Context, r))
+}
+
+func (k *Kubernetes) analyzeCronJob(ctx context.Context, r common.Request) ([]common.Result, error) {
 	kind := "CronJob"
 	apiDoc := K8sApiReference{
 		Kind: kind,
@@ -21,9 +25,9 @@ func (k *Kubernetes) AnalyzeCronJob(r common.Request) (string, error) {
 		},
 		OpenapiSchema: k.openapiSchema,
 	}
-	cronJobList, err := k.clientset.BatchV1().CronJobs(r.Namespace).List(r.Context, metav1.ListOptions{})
+	cronJobList, err := k.clientset.BatchV1().CronJobs(r.Namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	var preAnalysis = map[string]common.PreAnalysis{}
@@ -35,23 +39,38 @@ func (k *Kubernetes) AnalyzeCronJob(r common.Request) (string, error) {
 		if cronjob.Spec.Suspend != nil && *cronjob.Spec.Suspend {
 			doc := apiDoc.GetApiDocV2("spec.suspend")
 			failures = append(failures, common.Failure{
+				ID:            "CRONJOB_SUSPENDED",
+				Severity:      common.SeverityInfo,
 				Text:          fmt.Sprintf("CronJob %s/%s is suspended", cronjob.Namespace, cronjob.Name),
 				KubernetesDoc: doc,
+				FieldPath:     "spec.suspend",
+				Remediation: &common.Remediation{
+					Text:  "Resume the cronjob if it should run",
+					Patch: `{"spec":{"suspend":false}}`,
+				},
 			})
 		} else {
 			// check the schedule format
 			if _, err := cron.ParseStandard(cronjob.Spec.Schedule); err != nil {
 				doc := apiDoc.GetApiDocV2("spec.schedule")
 				failures = append(failures, common.Failure{
+					ID:            "CRONJOB_INVALID_SCHEDULE",
+					Severity:      common.SeverityCritical,
 					Text:          fmt.Sprintf("CronJob has an invalid schedule: %v", err),
 					KubernetesDoc: doc,
+					FieldPath:     "spec.schedule",
+					Remediation:   &common.Remediation{Text: "Use a standard five field cron expression, e.g. */5 * * * *"},
 				})
 			}
 
 			// check if cronjob has never been scheduled
 			if cronjob.Status.LastScheduleTime == nil {
 				failures = append(failures, common.Failure{
-					Text: fmt.Sprint("CronJob has never been scheduled"),
+					ID:          "CRONJOB_NEVER_SCHEDULED",
+					Severity:    common.SeverityWarning,
+					Text:        fmt.Sprint("CronJob has never been scheduled"),
+					FieldPath:   "status.lastScheduleTime",
+					Remediation: &common.Remediation{Text: "Check that the schedule is due and the controller manager is running, a missed starting deadline also skips runs"},
 				})
 			}
 
@@ -60,8 +79,15 @@ func (k *Kubernetes) AnalyzeCronJob(r common.Request) (string, error) {
 				doc := apiDoc.GetApiDocV2("spec.startingDeadlineSeconds")
 				if *cronjob.Spec.StartingDeadlineSeconds < 0 {
 					failures = append(failures, common.Failure{
+						ID:            "CRONJOB_NEGATIVE_STARTING_DEADLINE",
+						Severity:      common.SeverityCritical,
 						Text:          fmt.Sprintf("CronJob has a negative starting deadline"),
 						KubernetesDoc: doc,
+						FieldPath:     "spec.startingDeadlineSeconds",
+						Remediation: &common.Remediation{
+							Text:  "Remove the starting deadline or set it to a positive number of seconds",
+							Patch: `{"spec":{"startingDeadlineSeconds":null}}`,
+						},
 					})
 				}
 			}
@@ -74,19 +100,5 @@ func (k *Kubernetes) AnalyzeCronJob(r common.Request) (string, error) {
 		}
 	}
 
-	results := make([]common.Result, 0)
-	for key, value := range preAnalysis {
-		result := common.Result{
-			Kind:  kind,
-			Name:  key,
-			Error: value.FailureDetails,
-		}
-		results = append(results, result)
-	}
-
-	jsonData, err := json.Marshal(results)
-	if err != nil {
-		return "", err
-	}
-	return string(jsonData), nil
+	return k.preAnalysisResults(ctx, kind, preAnalysis, nil), nil
 }
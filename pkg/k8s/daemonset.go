@@ -0,0 +1,155 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
+)
+
+// daemonSetDefaultTolerations are added to every daemon pod by the DaemonSet controller.
+var daemonSetDefaultTolerations = []v1.Toleration{
+	{Key: v1.TaintNodeNotReady, Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoExecute},
+	{Key: v1.TaintNodeUnreachable, Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoExecute},
+	{Key: v1.TaintNodeDiskPressure, Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule},
+	{Key: v1.TaintNodeMemoryPressure, Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule},
+	{Key: v1.TaintNodePIDPressure, Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule},
+	{Key: v1.TaintNodeUnschedulable, Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule},
+}
+
+// AnalyzeDaemonSet analyzes the daemonsets and returns a list of failures.
+func (k *Kubernetes) AnalyzeDaemonSet(r common.Request) (string, error) {
+	return marshalResults(k.analyzeDaemonSet(r.Context, r))
+}
+
+func (k *Kubernetes) analyzeDaemonSet(ctx context.Context, r common.Request) ([]common.Result, error) {
+	kind := "DaemonSet"
+	apiDoc := K8sApiReference{
+		Kind: kind,
+		ApiVersion: schema.GroupVersion{
+			Group:   "apps",
+			Version: "v1",
+		},
+		OpenapiSchema: k.openapiSchema,
+	}
+
+	dsList, err := k.clientset.AppsV1().DaemonSets(r.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// the nodes are read once per cluster scan
+	nodeList, _, err := clusterList(ctx, "nodes", func() (*v1.NodeList, error) {
+		return k.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var preAnalysis = map[string]common.PreAnalysis{}
+
+	for _, ds := range dsList.Items {
+		var failures []common.Failure
+
+		if ds.Status.NumberMisscheduled > 0 {
+			failures = append(failures, common.Failure{
+				ID:          "DAEMONSET_MISSCHEDULED",
+				Severity:    common.SeverityWarning,
+				Text:        fmt.Sprintf("%d daemon pods are running on nodes that are not supposed to run them", ds.Status.NumberMisscheduled),
+				FieldPath:   "status.numberMisscheduled",
+				Remediation: &common.Remediation{Text: "Check the node selector, affinity and node labels, the controller removes misscheduled pods unless they tolerate the node taints"},
+			})
+		}
+		if ds.Status.NumberUnavailable > 0 {
+			failures = append(failures, common.Failure{
+				ID:          "DAEMONSET_PODS_UNAVAILABLE",
+				Severity:    common.SeverityCritical,
+				Text:        fmt.Sprintf("%d/%d daemon pods are unavailable", ds.Status.NumberUnavailable, ds.Status.DesiredNumberScheduled),
+				FieldPath:   "status.numberUnavailable",
+				Remediation: &common.Remediation{Text: "Analyze the pods of the daemonset to find why they are not ready"},
+			})
+		}
+		if ds.Status.CurrentNumberScheduled < ds.Status.DesiredNumberScheduled {
+			failures = append(failures, common.Failure{
+				ID:          "DAEMONSET_PODS_NOT_SCHEDULED",
+				Severity:    common.SeverityWarning,
+				Text:        fmt.Sprintf("Only %d/%d daemon pods are scheduled", ds.Status.CurrentNumberScheduled, ds.Status.DesiredNumberScheduled),
+				FieldPath:   "status.currentNumberScheduled",
+				Remediation: &common.Remediation{Text: "Check the resources left on the nodes and the priority class of the daemon pods, they cannot preempt pods without one"},
+			})
+		}
+
+		// nodes are often tainted on purpose, e.g. control-plane nodes, the nodes are reported once per taint
+		var taints []v1.Taint
+		taintedNodes := map[string][]common.ObjectReference{}
+		for _, node := range nodeList.Items {
+			if taint, found := untoleratedTaint(ds, node); found {
+				if _, ok := taintedNodes[taint.ToString()]; !ok {
+					taints = append(taints, taint)
+				}
+				taintedNodes[taint.ToString()] = append(taintedNodes[taint.ToString()], common.ObjectReference{Kind: "Node", Name: node.Name})
+			}
+		}
+		for _, taint := range taints {
+			nodes := taintedNodes[taint.ToString()]
+			names := make([]string, 0, len(nodes))
+			for _, node := range nodes {
+				names = append(names, node.Name)
+			}
+			doc := apiDoc.GetApiDocV2("spec.template.spec.tolerations")
+			failures = append(failures, common.Failure{
+				ID:             "DAEMONSET_TAINT_NOT_TOLERATED",
+				Severity:       common.SeverityInfo,
+				Text:           fmt.Sprintf("The taint %s which the daemon pods do not tolerate keeps them off %d nodes matching the node selection: %s", taint.ToString(), len(nodes), strings.Join(names, ", ")),
+				KubernetesDoc:  doc,
+				FieldPath:      "spec.template.spec.tolerations",
+				RelatedObjects: nodes,
+				Remediation:    &common.Remediation{Text: fmt.Sprintf("Add a toleration for %s to the pod template if the nodes should run the daemon pods, or exclude them with a node selector or affinity", taint.ToString())},
+			})
+		}
+
+		if len(failures) > 0 {
+			preAnalysis[fmt.Sprintf("%s/%s", ds.Namespace, ds.Name)] = common.PreAnalysis{
+				DaemonSet:      ds,
+				FailureDetails: failures,
+			}
+		}
+	}
+
+	return k.preAnalysisResults(ctx, kind, preAnalysis, func(p common.PreAnalysis) metav1.ObjectMeta { return p.DaemonSet.ObjectMeta }), nil
+}
+
+// untoleratedTaint returns the first NoSchedule or NoExecute taint keeping daemon pods off a node that their node selection targets.
+func untoleratedTaint(ds appsv1.DaemonSet, node v1.Node) (v1.Taint, bool) {
+	pod := &v1.Pod{Spec: ds.Spec.Template.Spec}
+	if match, err := nodeaffinity.GetRequiredNodeAffinity(pod).Match(&node); err != nil || !match {
+		return v1.Taint{}, false
+	}
+
+	tolerations := append(append([]v1.Toleration{}, ds.Spec.Template.Spec.Tolerations...), daemonSetDefaultTolerations...)
+	if ds.Spec.Template.Spec.HostNetwork {
+		tolerations = append(tolerations, v1.Toleration{Key: v1.TaintNodeNetworkUnavailable, Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule})
+	}
+
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect == v1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for _, toleration := range tolerations {
+			if toleration.ToleratesTaint(&taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return taint, true
+		}
+	}
+	return v1.Taint{}, false
+}
@@ -0,0 +1,95 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestDaemonSet() *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "node-exporter",
+			Namespace: "monitoring",
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+				},
+			},
+		},
+		Status: appsv1.DaemonSetStatus{
+			DesiredNumberScheduled: 2,
+			CurrentNumberScheduled: 2,
+			NumberReady:            2,
+			NumberAvailable:        2,
+		},
+	}
+}
+
+func newTestNode(name string, nodeLabels map[string]string, taints ...v1.Taint) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nodeLabels},
+		Spec:       v1.NodeSpec{Taints: taints},
+	}
+}
+
+func TestAnalyzeDaemonSet(t *testing.T) {
+	request := common.Request{Context: context.Background(), Namespace: "monitoring"}
+	linux := map[string]string{"kubernetes.io/os": "linux"}
+
+	t.Run("Healthy daemonset", func(t *testing.T) {
+		k := newTestKubernetes(fake.NewSimpleClientset(
+			newTestDaemonSet(),
+			newTestNode("worker-1", linux),
+			// cordoned nodes are tolerated by the controller defaults
+			newTestNode("worker-2", linux, v1.Taint{Key: v1.TaintNodeUnschedulable, Effect: v1.TaintEffectNoSchedule}),
+			// nodes outside the node selection are ignored
+			newTestNode("windows-1", map[string]string{"kubernetes.io/os": "windows"}, v1.Taint{Key: "os", Value: "windows", Effect: v1.TaintEffectNoSchedule}),
+		), nil)
+		result, err := k.AnalyzeDaemonSet(request)
+		assert.NoError(t, err)
+		assert.Equal(t, "[]", result)
+	})
+
+	t.Run("Unavailable, misscheduled and untolerated taints", func(t *testing.T) {
+		ds := newTestDaemonSet()
+		ds.Status.NumberMisscheduled = 1
+		ds.Status.NumberUnavailable = 1
+		ds.Status.CurrentNumberScheduled = 1
+		k := newTestKubernetes(fake.NewSimpleClientset(
+			ds,
+			newTestNode("worker-1", linux),
+			newTestNode("gpu-1", linux, v1.Taint{Key: "nvidia.com/gpu", Value: "present", Effect: v1.TaintEffectNoSchedule}),
+			newTestNode("gpu-2", linux, v1.Taint{Key: "nvidia.com/gpu", Value: "present", Effect: v1.TaintEffectNoSchedule}),
+		), nil)
+		result, err := k.AnalyzeDaemonSet(request)
+		assert.NoError(t, err)
+		assert.Contains(t, result, "1 daemon pods are running on nodes that are not supposed to run them")
+		assert.Contains(t, result, "1/2 daemon pods are unavailable")
+		assert.Contains(t, result, "Only 1/2 daemon pods are scheduled")
+		assert.Contains(t, result, "The taint nvidia.com/gpu=present:NoSchedule which the daemon pods do not tolerate keeps them off 2 nodes matching the node selection: gpu-1, gpu-2")
+		assert.Equal(t, 1, strings.Count(result, "DAEMONSET_TAINT_NOT_TOLERATED"), "the nodes should be reported once per taint")
+		assert.NotContains(t, result, "worker-1")
+	})
+
+	t.Run("Tolerated taint", func(t *testing.T) {
+		ds := newTestDaemonSet()
+		ds.Spec.Template.Spec.Tolerations = []v1.Toleration{{Key: "nvidia.com/gpu", Operator: v1.TolerationOpExists}}
+		k := newTestKubernetes(fake.NewSimpleClientset(
+			ds,
+			newTestNode("gpu-1", linux, v1.Taint{Key: "nvidia.com/gpu", Value: "present", Effect: v1.TaintEffectNoSchedule}),
+		), nil)
+		result, err := k.AnalyzeDaemonSet(request)
+		assert.NoError(t, err)
+		assert.Equal(t, "[]", result)
+	})
+}
@@ -0,0 +1,188 @@
+package k8s
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/utils"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var propagationPolicies = []metav1.DeletionPropagation{
+	metav1.DeletePropagationForeground,
+	metav1.DeletePropagationBackground,
+	metav1.DeletePropagationOrphan,
+}
+
+// ResourceDelete deletes the object named by r, or the objects matching r.LabelSelector. A label selector delete
+// first only lists the objects it would delete with a confirmation token, the objects are deleted when the
+// token is passed back in opts.Confirm and the selector still matches the same objects.
+func (k *Kubernetes) ResourceDelete(r common.Request, opts common.DeleteOptions) (string, error) {
+	if err := validateDelete(r, opts); err != nil {
+		return "", err
+	}
+	deleteOptions, err := newDeleteOptions(opts)
+	if err != nil {
+		return "", err
+	}
+	mapping, err := k.resolveKind(r.Kind, r.APIVersion)
+	if err != nil {
+		return "", err
+	}
+	namespace := ""
+	if isNamespaced(mapping) {
+		namespace = utils.NamespaceOrDefault(r.Namespace)
+	}
+	if r.Name == "" {
+		return k.deleteSelected(r, mapping, namespace, opts, deleteOptions)
+	}
+
+	err = k.dynamicClient.Resource(mapping.Resource).Namespace(namespace).Delete(r.Context, r.Name, deleteOptions)
+	if err != nil {
+		return "", err
+	}
+	return deletedMessage(mapping, namespace, r.Name, opts.DryRun), nil
+}
+
+func validateDelete(r common.Request, opts common.DeleteOptions) error {
+	switch {
+	case r.Name != "" && r.LabelSelector != "":
+		return fmt.Errorf("name and label selector are exclusive")
+	case r.Name == "" && r.LabelSelector == "":
+		return fmt.Errorf("a name or a label selector is required")
+	case r.Name == "" && (opts.UID != "" || opts.ResourceVersion != ""):
+		return fmt.Errorf("uid and resourceVersion preconditions need a name")
+	}
+	return nil
+}
+
+func newDeleteOptions(opts common.DeleteOptions) (metav1.DeleteOptions, error) {
+	var deleteOptions metav1.DeleteOptions
+	if opts.PropagationPolicy != "" {
+		policy := metav1.DeletionPropagation(opts.PropagationPolicy)
+		if !slices.Contains(propagationPolicies, policy) {
+			return deleteOptions, fmt.Errorf("unknown propagation policy %s, expected %s, %s or %s", opts.PropagationPolicy,
+				metav1.DeletePropagationForeground, metav1.DeletePropagationBackground, metav1.DeletePropagationOrphan)
+		}
+		deleteOptions.PropagationPolicy = &policy
+	}
+	if opts.GracePeriodSeconds != nil {
+		if *opts.GracePeriodSeconds < 0 {
+			return deleteOptions, fmt.Errorf("grace period must not be negative")
+		}
+		deleteOptions.GracePeriodSeconds = opts.GracePeriodSeconds
+	}
+	if opts.UID != "" || opts.ResourceVersion != "" {
+		deleteOptions.Preconditions = &metav1.Preconditions{}
+		if opts.UID != "" {
+			uid := types.UID(opts.UID)
+			deleteOptions.Preconditions.UID = &uid
+		}
+		if opts.ResourceVersion != "" {
+			deleteOptions.Preconditions.ResourceVersion = &opts.ResourceVersion
+		}
+	}
+	if opts.DryRun {
+		deleteOptions.DryRun = []string{metav1.DryRunAll}
+	}
+	return deleteOptions, nil
+}
+
+// deleteSelected previews or deletes the objects matching the label selector. Every object is deleted with a UID
+// precondition, so an object recreated under the same name after the preview is kept.
+func (k *Kubernetes) deleteSelected(r common.Request, mapping *meta.RESTMapping, namespace string, opts common.DeleteOptions, deleteOptions metav1.DeleteOptions) (string, error) {
+	client := k.dynamicClient.Resource(mapping.Resource).Namespace(namespace)
+	list, err := client.List(r.Context, metav1.ListOptions{LabelSelector: r.LabelSelector})
+	if err != nil {
+		return "", err
+	}
+	objects := sortObjects(list.Items)
+	if len(objects) == 0 {
+		return fmt.Sprintf("no %s matches the label selector %s", mapping.GroupVersionKind.Kind, r.LabelSelector), nil
+	}
+
+	token := deleteToken(mapping, namespace, r.LabelSelector, opts, objects)
+	if opts.Confirm == "" {
+		var b strings.Builder
+		fmt.Fprintf(&b, "%d objects match the label selector %s and would be deleted:\n", len(objects), r.LabelSelector)
+		for _, obj := range objects {
+			fmt.Fprintf(&b, "- %s %s\n", mapping.GroupVersionKind.Kind, objectName(obj))
+		}
+		fmt.Fprintf(&b, "\nDelete them by repeating the call with the confirmation token %s", token)
+		return b.String(), nil
+	}
+	if opts.Confirm != token {
+		return "", fmt.Errorf("the confirmation token does not match the objects the label selector matches now, preview the delete again")
+	}
+
+	var b strings.Builder
+	var failed int
+	for _, obj := range objects {
+		objectOptions := *deleteOptions.DeepCopy()
+		uid := obj.GetUID()
+		objectOptions.Preconditions = &metav1.Preconditions{UID: &uid}
+		if err := client.Delete(r.Context, obj.GetName(), objectOptions); err != nil {
+			failed++
+			fmt.Fprintf(&b, "failed to delete %s %s: %v\n", mapping.GroupVersionKind.Kind, objectName(obj), err)
+			continue
+		}
+		fmt.Fprintln(&b, deletedMessage(mapping, obj.GetNamespace(), obj.GetName(), opts.DryRun))
+	}
+	if failed > 0 {
+		return "", fmt.Errorf("failed to delete %d of %d objects:\n%s", failed, len(objects), strings.TrimSuffix(b.String(), "\n"))
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// deleteToken hashes what a delete is about to delete and how, the resource, namespace, selector and options are
+// included so a token cannot confirm another delete, e.g. a cascading delete with the token of an orphaning one.
+func deleteToken(mapping *meta.RESTMapping, namespace, selector string, opts common.DeleteOptions, objects []unstructured.Unstructured) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00", mapping.Resource, namespace, selector)
+	gracePeriod := "default"
+	if opts.GracePeriodSeconds != nil {
+		gracePeriod = fmt.Sprint(*opts.GracePeriodSeconds)
+	}
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00", opts.PropagationPolicy, gracePeriod, opts.UID, opts.ResourceVersion)
+	for _, obj := range objects {
+		fmt.Fprintf(h, "%s\x00%s\x00%s\x00", obj.GetNamespace(), obj.GetName(), obj.GetUID())
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+func sortObjects(objects []unstructured.Unstructured) []unstructured.Unstructured {
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].GetNamespace() != objects[j].GetNamespace() {
+			return objects[i].GetNamespace() < objects[j].GetNamespace()
+		}
+		return objects[i].GetName() < objects[j].GetName()
+	})
+	return objects
+}
+
+func objectName(obj unstructured.Unstructured) string {
+	return objectKey(obj.GetNamespace(), obj.GetName())
+}
+
+func objectKey(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
+
+func deletedMessage(mapping *meta.RESTMapping, namespace, name string, dryRun bool) string {
+	msg := fmt.Sprintf("%s %s deleted successfully", mapping.GroupVersionKind.Kind, objectKey(namespace, name))
+	if dryRun {
+		msg += " (dry run)"
+	}
+	return msg
+}
@@ -0,0 +1,134 @@
+package k8s
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var deploymentsResource = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+func newDeleteDeployment(name, app string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"namespace": "default",
+				"name":      name,
+				"uid":       name + "-uid",
+				"labels":    map[string]interface{}{"app": app},
+			},
+		},
+	}
+}
+
+func newDeleteDynamicClient() *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+		newDeleteDeployment("web-1", "web"),
+		newDeleteDeployment("web-2", "web"),
+		newDeleteDeployment("api", "api"),
+	)
+}
+
+func deploymentNames(t *testing.T, client *dynamicfake.FakeDynamicClient) []string {
+	list, err := client.Resource(deploymentsResource).Namespace("default").List(context.Background(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	var names []string
+	for _, obj := range list.Items {
+		names = append(names, obj.GetName())
+	}
+	return names
+}
+
+func TestResourceDeleteSelector(t *testing.T) {
+	r := common.Request{Context: context.Background(), Kind: "Deployment", Namespace: "default", LabelSelector: "app=web"}
+	tokenPattern := regexp.MustCompile(`confirmation token ([0-9a-f]+)`)
+
+	t.Run("Preview then confirm", func(t *testing.T) {
+		dynamicClient := newDeleteDynamicClient()
+		k := newTestKubernetes(fake.NewSimpleClientset(), dynamicClient)
+
+		preview, err := k.ResourceDelete(r, common.DeleteOptions{})
+		assert.NoError(t, err)
+		assert.Contains(t, preview, "2 objects match the label selector app=web and would be deleted:\n- Deployment default/web-1\n- Deployment default/web-2\n")
+		assert.Len(t, deploymentNames(t, dynamicClient), 3, "the preview should not delete anything")
+
+		token := tokenPattern.FindStringSubmatch(preview)
+		if !assert.Len(t, token, 2) {
+			return
+		}
+		res, err := k.ResourceDelete(r, common.DeleteOptions{Confirm: token[1]})
+		assert.NoError(t, err)
+		assert.Equal(t, "Deployment default/web-1 deleted successfully\nDeployment default/web-2 deleted successfully", res)
+		assert.Equal(t, []string{"api"}, deploymentNames(t, dynamicClient))
+	})
+
+	t.Run("Token of a changed selection is rejected", func(t *testing.T) {
+		dynamicClient := newDeleteDynamicClient()
+		k := newTestKubernetes(fake.NewSimpleClientset(), dynamicClient)
+
+		preview, err := k.ResourceDelete(r, common.DeleteOptions{})
+		assert.NoError(t, err)
+		token := tokenPattern.FindStringSubmatch(preview)
+		if !assert.Len(t, token, 2) {
+			return
+		}
+		_, err = dynamicClient.Resource(deploymentsResource).Namespace("default").Create(context.Background(), newDeleteDeployment("web-3", "web"), metav1.CreateOptions{})
+		assert.NoError(t, err)
+
+		_, err = k.ResourceDelete(r, common.DeleteOptions{Confirm: token[1]})
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "the confirmation token does not match")
+		}
+		assert.Len(t, deploymentNames(t, dynamicClient), 4)
+	})
+
+	t.Run("Token of another selector is rejected", func(t *testing.T) {
+		k := newTestKubernetes(fake.NewSimpleClientset(), newDeleteDynamicClient())
+		preview, err := k.ResourceDelete(common.Request{Context: context.Background(), Kind: "Deployment", Namespace: "default", LabelSelector: "app=api"}, common.DeleteOptions{})
+		assert.NoError(t, err)
+		token := tokenPattern.FindStringSubmatch(preview)
+		if !assert.Len(t, token, 2) {
+			return
+		}
+		_, err = k.ResourceDelete(r, common.DeleteOptions{Confirm: token[1]})
+		assert.Error(t, err)
+	})
+}
+
+func TestNewDeleteOptions(t *testing.T) {
+	gracePeriod := int64(0)
+	opts, err := newDeleteOptions(common.DeleteOptions{
+		PropagationPolicy:  "Foreground",
+		GracePeriodSeconds: &gracePeriod,
+		UID:                "web-uid",
+		ResourceVersion:    "42",
+		DryRun:             true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, metav1.DeletePropagationForeground, *opts.PropagationPolicy)
+	assert.Equal(t, int64(0), *opts.GracePeriodSeconds)
+	assert.Equal(t, types.UID("web-uid"), *opts.Preconditions.UID)
+	assert.Equal(t, "42", *opts.Preconditions.ResourceVersion)
+	assert.Equal(t, []string{metav1.DryRunAll}, opts.DryRun)
+
+	_, err = newDeleteOptions(common.DeleteOptions{PropagationPolicy: "Cascade"})
+	assert.Error(t, err)
+
+	k := newTestKubernetes(fake.NewSimpleClientset(), newDeleteDynamicClient())
+	_, err = k.ResourceDelete(common.Request{Context: context.Background(), Kind: "Deployment", Name: "api", LabelSelector: "app=api"}, common.DeleteOptions{})
+	assert.EqualError(t, err, "name and label selector are exclusive")
+	_, err = k.ResourceDelete(common.Request{Context: context.Background(), Kind: "Deployment", LabelSelector: "app=api"}, common.DeleteOptions{UID: "api-uid"})
+	assert.EqualError(t, err, "uid and resourceVersion preconditions need a name")
+}
@@ -2,31 +2,40 @@ package k8s
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+	defaultProgressDeadline      = 600 * time.Second
 )
 
 // DeploymentScale scales a deployment.
 func (k *Kubernetes) DeploymentScale(ctx context.Context, namespace, name string, replicas int32) (string, error) {
-	deploy, err := k.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
-	if err != nil {
-		return "", err
-	}
-
-	deploy.Spec.Replicas = &replicas
-	_, err = k.clientset.AppsV1().Deployments(namespace).Update(ctx, deploy, metav1.UpdateOptions{})
-	if err != nil {
-		return "", err
-	}
-	return fmt.Sprintf("Deployment %s/%s scaled to %d replicas", namespace, name, replicas), nil
+	return k.ResourceScale(common.Request{
+		Context:   ctx,
+		Kind:      "Deployment",
+		Namespace: namespace,
+		Name:      name,
+	}, common.ScaleOptions{Replicas: replicas})
 }
 
 // AnalyzeDeployments analyzes the deployments and returns a list of failures.
 func (k *Kubernetes) AnalyzeDeployment(ctx context.Context, namespace string) (string, error) {
+	return marshalResults(k.analyzeDeployment(ctx, common.Request{Namespace: namespace}))
+}
+
+func (k *Kubernetes) analyzeDeployment(ctx context.Context, r common.Request) ([]common.Result, error) {
 	kind := "Deployment"
 	apiDoc := K8sApiReference{
 		Kind: kind,
@@ -37,9 +46,21 @@ func (k *Kubernetes) AnalyzeDeployment(ctx context.Context, namespace string) (s
 		OpenapiSchema: k.openapiSchema,
 	}
 
-	deployList, err := k.clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	deployList, err := k.clientset.AppsV1().Deployments(r.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	rsList, err := k.clientset.AppsV1().ReplicaSets(r.Namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	podList, err := k.clientset.CoreV1().Pods(r.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pdbList, err := k.clientset.PolicyV1().PodDisruptionBudgets(r.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
 	}
 
 	var preAnalysis = map[string]common.PreAnalysis{}
@@ -47,34 +68,244 @@ func (k *Kubernetes) AnalyzeDeployment(ctx context.Context, namespace string) (s
 	for _, deploy := range deployList.Items {
 		var failures []common.Failure
 
-		if deploy.Status.AvailableReplicas < *deploy.Spec.Replicas {
+		// the API server defaults replicas to 1
+		replicas := int32(1)
+		if deploy.Spec.Replicas != nil {
+			replicas = *deploy.Spec.Replicas
+		}
+
+		if deploy.Status.AvailableReplicas < replicas {
 			doc := apiDoc.GetApiDocV2("spec.replicas")
+			text := fmt.Sprintf("Only %d/%d replicas available", deploy.Status.AvailableReplicas, replicas)
+			if cond := deploymentCondition(deploy.Status, appsv1.DeploymentAvailable); cond != nil && cond.Status == v1.ConditionFalse {
+				text = fmt.Sprintf("%s: %s", text, cond.Message)
+			}
+			severity := common.SeverityWarning
+			if deploy.Status.AvailableReplicas == 0 {
+				severity = common.SeverityCritical
+			}
 			failures = append(failures, common.Failure{
-				Text:          fmt.Sprintf("Only %d/%d replicas available", deploy.Status.AvailableReplicas, *deploy.Spec.Replicas),
+				ID:            "DEPLOYMENT_REPLICAS_UNAVAILABLE",
+				Severity:      severity,
+				Text:          text,
 				KubernetesDoc: doc,
+				FieldPath:     "status.availableReplicas",
+				Remediation: &common.Remediation{
+					Text: "Fix the failures of the pods that are not available, they are listed with the deployment",
+				},
 			})
+		}
 
-			if len(failures) > 0 {
-				preAnalysis[fmt.Sprintf("%s/%s", deploy.Namespace, deploy.Name)] = common.PreAnalysis{
-					Deployment:     deploy,
-					FailureDetails: failures,
+		if cond := deploymentCondition(deploy.Status, appsv1.DeploymentProgressing); cond != nil && cond.Status == v1.ConditionFalse && cond.Reason == "ProgressDeadlineExceeded" {
+			doc := apiDoc.GetApiDocV2("spec.progressDeadlineSeconds")
+			failures = append(failures, common.Failure{
+				ID:            "DEPLOYMENT_PROGRESS_DEADLINE_EXCEEDED",
+				Severity:      common.SeverityCritical,
+				Text:          fmt.Sprintf("Rollout exceeded its progress deadline: %s", cond.Message),
+				KubernetesDoc: doc,
+				FieldPath:     "status.conditions",
+				Remediation: &common.Remediation{
+					Text: "Fix the pods of the new revision or roll back to the previous revision with rollout undo",
+				},
+			})
+		}
+		if cond := deploymentCondition(deploy.Status, appsv1.DeploymentReplicaFailure); cond != nil && cond.Status == v1.ConditionTrue {
+			failures = append(failures, common.Failure{
+				ID:        "DEPLOYMENT_REPLICA_FAILURE",
+				Severity:  common.SeverityCritical,
+				Text:      fmt.Sprintf("ReplicaSet failed to create pods (%s): %s", cond.Reason, cond.Message),
+				FieldPath: "status.conditions",
+				Remediation: &common.Remediation{
+					Text: "Resolve the quota, LimitRange, admission webhook or pod security rejection named in the message",
+				},
+			})
+		}
+
+		var owned []appsv1.ReplicaSet
+		for _, rs := range rsList.Items {
+			if metav1.IsControlledBy(&rs, &deploy) {
+				owned = append(owned, rs)
+			}
+		}
+		if rs := newReplicaSet(deploy, owned); rs != nil && stuckReplicaSet(deploy, *rs) {
+			failures = append(failures, common.Failure{
+				ID:       "DEPLOYMENT_ROLLOUT_STUCK",
+				Severity: common.SeverityCritical,
+				Text: fmt.Sprintf("New ReplicaSet %s/%s has no ready replicas %s after it was created",
+					rs.Namespace, rs.Name, time.Since(rs.CreationTimestamp.Time).Round(time.Second)),
+				RelatedObjects: []common.ObjectReference{{Kind: "ReplicaSet", Namespace: rs.Namespace, Name: rs.Name}},
+				Remediation: &common.Remediation{
+					Text: "Fix the pods of the new ReplicaSet or roll back to the previous revision with rollout undo",
+				},
+			})
+		}
+
+		for _, pod := range podList.Items {
+			if !ownedByAny(pod.ObjectMeta, owned) {
+				continue
+			}
+			failures = append(failures, ownedPodFailures(pod, k.analyzePodFailures(ctx, pod))...)
+		}
+
+		failures = append(failures, analyzeDeploymentSpec(deploy, replicas, pdbList.Items, apiDoc)...)
+
+		if len(failures) > 0 {
+			preAnalysis[fmt.Sprintf("%s/%s", deploy.Namespace, deploy.Name)] = common.PreAnalysis{
+				Deployment:     deploy,
+				FailureDetails: failures,
+			}
+		}
+	}
+
+	return k.preAnalysisResults(ctx, kind, preAnalysis, func(p common.PreAnalysis) metav1.ObjectMeta { return p.Deployment.ObjectMeta }), nil
+}
+
+// analyzeDeploymentSpec flags risky settings that do not fail yet but hurt availability during rollouts and disruptions.
+func analyzeDeploymentSpec(deploy appsv1.Deployment, replicas int32, pdbs []policyv1.PodDisruptionBudget, apiDoc K8sApiReference) []common.Failure {
+	var failures []common.Failure
+
+	for i, container := range deploy.Spec.Template.Spec.Containers {
+		fieldPath := fmt.Sprintf("spec.template.spec.containers[%d]", i)
+		if container.ReadinessProbe == nil {
+			text := fmt.Sprintf("Container %s has no readiness probe, pods receive traffic and count as available as soon as they start", container.Name)
+			if container.LivenessProbe == nil {
+				text = fmt.Sprintf("Container %s has no readiness or liveness probe, failures are only detected when the process exits", container.Name)
+			}
+			failures = append(failures, common.Failure{
+				ID:            "DEPLOYMENT_NO_READINESS_PROBE",
+				Severity:      common.SeverityInfo,
+				Text:          text,
+				KubernetesDoc: apiDoc.GetApiDocV2("spec.template.spec.containers.readinessProbe"),
+				FieldPath:     fieldPath + ".readinessProbe",
+				Remediation: &common.Remediation{
+					Text: fmt.Sprintf("Add a readiness probe to container %s that checks the application can serve requests", container.Name),
+				},
+			})
+		}
+		if len(container.Resources.Requests) == 0 {
+			failures = append(failures, common.Failure{
+				ID:            "DEPLOYMENT_NO_RESOURCE_REQUESTS",
+				Severity:      common.SeverityInfo,
+				Text:          fmt.Sprintf("Container %s has no resource requests, the scheduler can overcommit the node", container.Name),
+				KubernetesDoc: apiDoc.GetApiDocV2("spec.template.spec.containers.resources"),
+				FieldPath:     fieldPath + ".resources.requests",
+				Remediation: &common.Remediation{
+					Text: fmt.Sprintf("Set cpu and memory requests on container %s close to its observed usage", container.Name),
+				},
+			})
+		}
+	}
+
+	if deploy.Spec.Strategy.Type != appsv1.RecreateDeploymentStrategyType && deploy.Spec.Strategy.RollingUpdate != nil && replicas > 0 {
+		maxUnavailable := deploy.Spec.Strategy.RollingUpdate.MaxUnavailable
+		if maxUnavailable != nil {
+			value, err := intstr.GetScaledValueFromIntOrPercent(maxUnavailable, int(replicas), false)
+			if err == nil && value >= int(replicas) {
+				remediation := &common.Remediation{
+					Text: "Lower maxUnavailable so some replicas keep serving during a rollout",
 				}
+				// maxUnavailable and maxSurge cannot both be zero
+				if surge := deploy.Spec.Strategy.RollingUpdate.MaxSurge; surge == nil || surge.String() != "0" && surge.String() != "0%" {
+					remediation.Patch = `{"spec":{"strategy":{"rollingUpdate":{"maxUnavailable":"25%"}}}}`
+				}
+				failures = append(failures, common.Failure{
+					ID:            "DEPLOYMENT_MAX_UNAVAILABLE_ALL",
+					Severity:      common.SeverityWarning,
+					Text:          fmt.Sprintf("maxUnavailable %s allows all %d replicas to be unavailable during a rollout", maxUnavailable.String(), replicas),
+					KubernetesDoc: apiDoc.GetApiDocV2("spec.strategy.rollingUpdate.maxUnavailable"),
+					FieldPath:     "spec.strategy.rollingUpdate.maxUnavailable",
+					Remediation:   remediation,
+				})
 			}
 		}
 	}
-	var results []common.Result
-	for key, value := range preAnalysis {
-		result := common.Result{
-			Kind:  kind,
-			Name:  key,
-			Error: value.FailureDetails,
+
+	if replicas == 1 {
+		for _, pdb := range pdbs {
+			if blocksSingleReplica(pdb, deploy.Spec.Template.Labels) {
+				failures = append(failures, common.Failure{
+					ID:             "DEPLOYMENT_PDB_BLOCKS_EVICTION",
+					Severity:       common.SeverityWarning,
+					Text:           fmt.Sprintf("Single replica Deployment is covered by PodDisruptionBudget %s/%s, which blocks evictions and node drains", pdb.Namespace, pdb.Name),
+					FieldPath:      "spec.replicas",
+					RelatedObjects: []common.ObjectReference{{Kind: "PodDisruptionBudget", Namespace: pdb.Namespace, Name: pdb.Name}},
+					Remediation: &common.Remediation{
+						Text:  "Run at least two replicas, or relax the PodDisruptionBudget to allow one disruption",
+						Patch: `{"spec":{"replicas":2}}`,
+					},
+				})
+			}
 		}
-		results = append(results, result)
 	}
 
-	jsonData, err := json.Marshal(results)
-	if err != nil {
-		return "", err
+	return failures
+}
+
+// blocksSingleReplica reports whether the PodDisruptionBudget selects the pod labels and allows no disruption of a single pod.
+func blocksSingleReplica(pdb policyv1.PodDisruptionBudget, podLabels map[string]string) bool {
+	if pdb.Spec.Selector == nil {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+	if err != nil || selector.Empty() || !selector.Matches(labels.Set(podLabels)) {
+		return false
+	}
+	// the disruption controller rounds percentages up
+	if pdb.Spec.MinAvailable != nil {
+		value, err := intstr.GetScaledValueFromIntOrPercent(pdb.Spec.MinAvailable, 1, true)
+		return err == nil && value >= 1
+	}
+	if pdb.Spec.MaxUnavailable != nil {
+		value, err := intstr.GetScaledValueFromIntOrPercent(pdb.Spec.MaxUnavailable, 1, true)
+		return err == nil && value == 0
+	}
+	return false
+}
+
+func deploymentCondition(status appsv1.DeploymentStatus, condType appsv1.DeploymentConditionType) *appsv1.DeploymentCondition {
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == condType {
+			return &status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// newReplicaSet returns the owned ReplicaSet of the current deployment revision.
+func newReplicaSet(deploy appsv1.Deployment, owned []appsv1.ReplicaSet) *appsv1.ReplicaSet {
+	revision, ok := deploy.Annotations[deploymentRevisionAnnotation]
+	if !ok {
+		return nil
+	}
+	for i := range owned {
+		if owned[i].Annotations[deploymentRevisionAnnotation] == revision {
+			return &owned[i]
+		}
+	}
+	return nil
+}
+
+// stuckReplicaSet reports whether a ReplicaSet wants pods but none became ready within the progress deadline.
+func stuckReplicaSet(deploy appsv1.Deployment, rs appsv1.ReplicaSet) bool {
+	if rs.Spec.Replicas == nil || *rs.Spec.Replicas == 0 || rs.Status.ReadyReplicas > 0 {
+		return false
+	}
+	deadline := defaultProgressDeadline
+	if deploy.Spec.ProgressDeadlineSeconds != nil {
+		deadline = time.Duration(*deploy.Spec.ProgressDeadlineSeconds) * time.Second
+	}
+	return time.Since(rs.CreationTimestamp.Time) > deadline
+}
+
+func ownedByAny(meta metav1.ObjectMeta, owners []appsv1.ReplicaSet) bool {
+	ref := metav1.GetControllerOf(&meta)
+	if ref == nil {
+		return false
+	}
+	for _, owner := range owners {
+		if ref.UID == owner.UID {
+			return true
+		}
 	}
-	return string(jsonData), nil
+	return false
 }
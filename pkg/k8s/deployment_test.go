@@ -0,0 +1,191 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+// newHealthyDeployment returns a deployment that passes every check of the analyzer.
+func newHealthyDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "api",
+			Namespace:   "default",
+			UID:         types.UID("api-uid"),
+			Annotations: map[string]string{deploymentRevisionAnnotation: "3"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(int32(3)),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "api"}},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{
+						Name:           "api",
+						Image:          "api:1.0",
+						ReadinessProbe: &v1.Probe{},
+						LivenessProbe:  &v1.Probe{},
+						Resources: v1.ResourceRequirements{
+							Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+						},
+					}},
+				},
+			},
+		},
+		Status: appsv1.DeploymentStatus{
+			Replicas:          3,
+			ReadyReplicas:     3,
+			AvailableReplicas: 3,
+		},
+	}
+}
+
+func newDeploymentReplicaSet(deploy *appsv1.Deployment, name, revision string, replicas, ready int32, created time.Time) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         deploy.Namespace,
+			UID:               types.UID(name),
+			CreationTimestamp: metav1.NewTime(created),
+			Annotations:       map[string]string{deploymentRevisionAnnotation: revision},
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "Deployment", Name: deploy.Name, UID: deploy.UID, Controller: ptr.To(true)},
+			},
+		},
+		Spec:   appsv1.ReplicaSetSpec{Replicas: ptr.To(replicas)},
+		Status: appsv1.ReplicaSetStatus{ReadyReplicas: ready},
+	}
+}
+
+func TestAnalyzeDeployment(t *testing.T) {
+	t.Run("Healthy deployment", func(t *testing.T) {
+		k := newTestKubernetes(fake.NewSimpleClientset(newHealthyDeployment()), nil)
+		result, err := k.AnalyzeDeployment(context.Background(), "default")
+		assert.NoError(t, err)
+		assert.Equal(t, "[]", result)
+	})
+
+	t.Run("Nil replicas default to one", func(t *testing.T) {
+		deploy := newHealthyDeployment()
+		deploy.Spec.Replicas = nil
+		deploy.Status.AvailableReplicas = 0
+		k := newTestKubernetes(fake.NewSimpleClientset(deploy), nil)
+		result, err := k.AnalyzeDeployment(context.Background(), "default")
+		assert.NoError(t, err)
+		assert.Contains(t, result, "Only 0/1 replicas available")
+	})
+
+	t.Run("Rollout conditions", func(t *testing.T) {
+		deploy := newHealthyDeployment()
+		deploy.Status.Conditions = []appsv1.DeploymentCondition{
+			{Type: appsv1.DeploymentProgressing, Status: v1.ConditionFalse, Reason: "ProgressDeadlineExceeded", Message: `ReplicaSet "api-7c9" has timed out progressing.`},
+			{Type: appsv1.DeploymentReplicaFailure, Status: v1.ConditionTrue, Reason: "FailedCreate", Message: "exceeded quota: compute-resources"},
+		}
+		k := newTestKubernetes(fake.NewSimpleClientset(deploy), nil)
+		result, err := k.AnalyzeDeployment(context.Background(), "default")
+		assert.NoError(t, err)
+		assert.Contains(t, result, "Rollout exceeded its progress deadline")
+		assert.Contains(t, result, "ReplicaSet failed to create pods (FailedCreate): exceeded quota: compute-resources")
+	})
+
+	t.Run("Stuck new ReplicaSet and pod failures", func(t *testing.T) {
+		deploy := newHealthyDeployment()
+		stuck := newDeploymentReplicaSet(deploy, "api-7c9", "3", 1, 0, time.Now().Add(-time.Hour))
+		old := newDeploymentReplicaSet(deploy, "api-5f4", "2", 3, 3, time.Now().Add(-24*time.Hour))
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "api-7c9-x2k",
+				Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: stuck.Name, UID: stuck.UID, Controller: ptr.To(true)},
+				},
+			},
+			Status: v1.PodStatus{
+				Phase: v1.PodPending,
+				ContainerStatuses: []v1.ContainerStatus{{
+					Name: "api",
+					State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{
+						Reason:  "ImagePullBackOff",
+						Message: `Back-off pulling image "api:2.0"`,
+					}},
+				}},
+			},
+		}
+		k := newTestKubernetes(fake.NewSimpleClientset(deploy, stuck, old, pod), nil)
+		result, err := k.AnalyzeDeployment(context.Background(), "default")
+		assert.NoError(t, err)
+		assert.Contains(t, result, "New ReplicaSet default/api-7c9 has no ready replicas")
+		assert.Contains(t, result, `Pod default/api-7c9-x2k: Back-off pulling image \"api:2.0\"`)
+		assert.NotContains(t, result, "api-5f4")
+	})
+
+	t.Run("Risky spec", func(t *testing.T) {
+		deploy := newHealthyDeployment()
+		deploy.Spec.Template.Spec.Containers[0].ReadinessProbe = nil
+		deploy.Spec.Template.Spec.Containers[0].LivenessProbe = nil
+		deploy.Spec.Template.Spec.Containers[0].Resources = v1.ResourceRequirements{}
+		deploy.Spec.Strategy = appsv1.DeploymentStrategy{
+			Type:          appsv1.RollingUpdateDeploymentStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateDeployment{MaxUnavailable: ptr.To(intstr.FromString("100%"))},
+		}
+		k := newTestKubernetes(fake.NewSimpleClientset(deploy), nil)
+		result, err := k.AnalyzeDeployment(context.Background(), "default")
+		assert.NoError(t, err)
+		assert.Contains(t, result, "Container api has no readiness or liveness probe")
+		assert.Contains(t, result, "Container api has no resource requests")
+		assert.Contains(t, result, "maxUnavailable 100% allows all 3 replicas to be unavailable")
+	})
+
+	t.Run("Single replica covered by PodDisruptionBudget", func(t *testing.T) {
+		deploy := newHealthyDeployment()
+		deploy.Spec.Replicas = ptr.To(int32(1))
+		deploy.Status.AvailableReplicas = 1
+		pdb := &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				MinAvailable: ptr.To(intstr.FromInt32(1)),
+				Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+			},
+		}
+		k := newTestKubernetes(fake.NewSimpleClientset(deploy, pdb), nil)
+		result, err := k.AnalyzeDeployment(context.Background(), "default")
+		assert.NoError(t, err)
+		assert.Contains(t, result, "Single replica Deployment is covered by PodDisruptionBudget default/api")
+	})
+}
+
+func TestBlocksSingleReplica(t *testing.T) {
+	podLabels := map[string]string{"app": "api"}
+	selector := &metav1.LabelSelector{MatchLabels: podLabels}
+	cases := []struct {
+		name string
+		spec policyv1.PodDisruptionBudgetSpec
+		want bool
+	}{
+		{"minAvailable 1", policyv1.PodDisruptionBudgetSpec{Selector: selector, MinAvailable: ptr.To(intstr.FromInt32(1))}, true},
+		{"minAvailable 50% rounds up", policyv1.PodDisruptionBudgetSpec{Selector: selector, MinAvailable: ptr.To(intstr.FromString("50%"))}, true},
+		{"maxUnavailable 0", policyv1.PodDisruptionBudgetSpec{Selector: selector, MaxUnavailable: ptr.To(intstr.FromInt32(0))}, true},
+		{"maxUnavailable 1", policyv1.PodDisruptionBudgetSpec{Selector: selector, MaxUnavailable: ptr.To(intstr.FromInt32(1))}, false},
+		{"Other selector", policyv1.PodDisruptionBudgetSpec{
+			Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			MinAvailable: ptr.To(intstr.FromInt32(1)),
+		}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.want, blocksSingleReplica(policyv1.PodDisruptionBudget{Spec: c.spec}, podLabels))
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// APIResources lists the resources the cluster serves in their preferred version like kubectl api-resources, group
+// keeps the resources of one API group, "core" or an empty string meaning all groups, and verb the resources
+// supporting it. Groups whose discovery failed are reported after the table, the others are still listed.
+func (k *Kubernetes) APIResources(group, verb string) (string, error) {
+	lists, err := k.discoveryClient.ServerPreferredResources()
+	var failed *discovery.ErrGroupDiscoveryFailed
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return "", err
+	}
+	if err != nil {
+		failed = err.(*discovery.ErrGroupDiscoveryFailed)
+	}
+
+	type row struct {
+		gv       schema.GroupVersion
+		resource metav1.APIResource
+	}
+	var rows []row
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		if group != "" && gv.Group != group && !(group == "core" && gv.Group == "") {
+			continue
+		}
+		for _, resource := range list.APIResources {
+			// subresources such as pods/log are reachable through their resource
+			if strings.Contains(resource.Name, "/") {
+				continue
+			}
+			if verb != "" && !slices.Contains(resource.Verbs, verb) {
+				continue
+			}
+			rows = append(rows, row{gv: gv, resource: resource})
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].gv.Group != rows[j].gv.Group {
+			return rows[i].gv.Group < rows[j].gv.Group
+		}
+		return rows[i].resource.Name < rows[j].resource.Name
+	})
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSHORTNAMES\tAPIVERSION\tNAMESPACED\tKIND\tVERBS")
+	for _, r := range rows {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\t%s\n", r.resource.Name, strings.Join(r.resource.ShortNames, ","),
+			r.gv.String(), r.resource.Namespaced, r.resource.Kind, strings.Join(r.resource.Verbs, ","))
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	if failed != nil {
+		var groups []string
+		for gv, err := range failed.Groups {
+			groups = append(groups, fmt.Sprintf("%s: %v", gv, err))
+		}
+		sort.Strings(groups)
+		fmt.Fprintf(&b, "\nfailed to discover the resources of:\n%s\n", strings.Join(groups, "\n"))
+	}
+	return b.String(), nil
+}
+
+// APIVersions lists the group versions the cluster serves like kubectl api-versions.
+func (k *Kubernetes) APIVersions() (string, error) {
+	groups, err := k.discoveryClient.ServerGroups()
+	if err != nil {
+		return "", err
+	}
+	versions := []string{}
+	for _, group := range groups.Groups {
+		for _, version := range group.Versions {
+			versions = append(versions, version.GroupVersion)
+		}
+	}
+	sort.Strings(versions)
+	return strings.Join(versions, "\n"), nil
+}
@@ -0,0 +1,60 @@
+package k8s
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newAPIResourcesDiscovery() *fakeDiscovery {
+	return &fakeDiscovery{
+		groupList: &metav1.APIGroupList{Groups: []metav1.APIGroup{
+			{Name: "", Versions: []metav1.GroupVersionForDiscovery{{GroupVersion: "v1", Version: "v1"}}, PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "v1", Version: "v1"}},
+			{Name: "apps", Versions: []metav1.GroupVersionForDiscovery{{GroupVersion: "apps/v1", Version: "v1"}}, PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "apps/v1", Version: "v1"}},
+			{Name: "metrics.k8s.io", Versions: []metav1.GroupVersionForDiscovery{{GroupVersion: "metrics.k8s.io/v1beta1", Version: "v1beta1"}}, PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "metrics.k8s.io/v1beta1", Version: "v1beta1"}},
+		}},
+		resourceMap: map[string]*resourceMapEntry{
+			"v1": {list: &metav1.APIResourceList{GroupVersion: "v1", APIResources: []metav1.APIResource{
+				{Name: "pods", Namespaced: true, Kind: "Pod", ShortNames: []string{"po"}, Verbs: []string{"get", "list", "watch", "delete"}},
+				{Name: "pods/log", Namespaced: true, Kind: "Pod", Verbs: []string{"get"}},
+				{Name: "componentstatuses", Kind: "ComponentStatus", ShortNames: []string{"cs"}, Verbs: []string{"get", "list"}},
+			}}},
+			"apps/v1": {list: &metav1.APIResourceList{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+				{Name: "deployments", Namespaced: true, Kind: "Deployment", ShortNames: []string{"deploy"}, Verbs: []string{"get", "list", "watch", "patch"}},
+			}}},
+			"metrics.k8s.io/v1beta1": {err: errors.New("the server is currently unable to handle the request")},
+		},
+	}
+}
+
+func TestAPIResources(t *testing.T) {
+	k := &Kubernetes{discoveryClient: newAPIResourcesDiscovery()}
+
+	t.Run("List all resources", func(t *testing.T) {
+		res, err := k.APIResources("", "")
+		assert.NoError(t, err)
+		assert.Regexp(t, `(?m)^NAME\s+SHORTNAMES\s+APIVERSION\s+NAMESPACED\s+KIND\s+VERBS$`, res)
+		assert.Regexp(t, `(?m)^pods\s+po\s+v1\s+true\s+Pod\s+get,list,watch,delete$`, res)
+		assert.Regexp(t, `(?m)^deployments\s+deploy\s+apps/v1\s+true\s+Deployment\s+get,list,watch,patch$`, res)
+		assert.NotContains(t, res, "pods/log", "subresources should not be listed")
+		assert.Contains(t, res, "failed to discover the resources of:\nmetrics.k8s.io/v1beta1: the server is currently unable to handle the request")
+	})
+
+	t.Run("Filter by group and verb", func(t *testing.T) {
+		res, err := k.APIResources("core", "watch")
+		assert.NoError(t, err)
+		assert.Contains(t, res, "pods")
+		assert.NotContains(t, res, "componentstatuses")
+		assert.NotContains(t, res, "deployments")
+	})
+}
+
+func TestAPIVersions(t *testing.T) {
+	k := &Kubernetes{discoveryClient: newAPIResourcesDiscovery()}
+
+	res, err := k.APIVersions()
+	assert.NoError(t, err)
+	assert.Equal(t, "apps/v1\nmetrics.k8s.io/v1beta1\nv1", res)
+}
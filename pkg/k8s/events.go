@@ -0,0 +1,278 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/utils"
+	v1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// eventKey identifies the object an event is about, the namespace is empty for cluster-scoped objects
+type eventKey struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// Events renders the events matching r and opts as a timeline, oldest first. With r.Kind and r.Name the timeline
+// covers the object and the objects it owns, e.g. the ReplicaSets and Pods of a Deployment, with r.Kind alone the
+// objects of that kind.
+func (k *Kubernetes) Events(r common.Request, opts common.EventOptions) (string, error) {
+	events, subject, err := k.listEvents(r, opts)
+	if err != nil {
+		return "", err
+	}
+	if len(events) == 0 {
+		return fmt.Sprintf("No events for %s", subject), nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d events for %s, oldest first:", len(events), subject)
+	for _, e := range events {
+		fmt.Fprintf(&b, "\n%s %s %s %s %s", e.LastSeen.UTC().Format(time.RFC3339), e.Type, e.Reason, e.Object.Kind, objectKey(e.Object.Namespace, e.Object.Name))
+		if e.Count > 1 {
+			fmt.Fprintf(&b, " (x%d since %s)", e.Count, e.FirstSeen.UTC().Format(time.RFC3339))
+		}
+		fmt.Fprintf(&b, ": %s", strings.TrimSpace(e.Message))
+	}
+	return b.String(), nil
+}
+
+// listEvents returns the collapsed events matching r and opts sorted by the time they were last seen, along with a
+// description of what they are about.
+func (k *Kubernetes) listEvents(r common.Request, opts common.EventOptions) ([]common.Event, string, error) {
+	if opts.Type != "" && opts.Type != v1.EventTypeNormal && opts.Type != v1.EventTypeWarning {
+		return nil, "", fmt.Errorf("unknown event type %s, expected %s or %s", opts.Type, v1.EventTypeNormal, v1.EventTypeWarning)
+	}
+	namespace := metav1.NamespaceAll
+	subject := "all namespaces"
+	if !opts.AllNamespaces {
+		namespace = utils.NamespaceOrDefault(r.Namespace)
+		subject = "namespace " + namespace
+	}
+
+	var kind string
+	var involved map[eventKey]bool
+	if r.Kind != "" {
+		mapping, err := k.resolveKind(r.Kind, r.APIVersion)
+		if err != nil {
+			return nil, "", err
+		}
+		kind = mapping.GroupVersionKind.Kind
+		subject = kind + " objects in " + subject
+		// the events of cluster-scoped objects are recorded in the default namespace or in none
+		if !isNamespaced(mapping) {
+			namespace = metav1.NamespaceAll
+			subject = kind + " objects"
+		}
+		if r.Name != "" {
+			if involved, subject, err = k.involvedObjects(r, opts, mapping); err != nil {
+				return nil, "", err
+			}
+		}
+	} else if r.Name != "" {
+		subject = "objects named " + r.Name + " in " + subject
+	}
+
+	selector := fields.Set{}
+	if opts.Type != "" {
+		selector["type"] = opts.Type
+	}
+	if opts.Reason != "" {
+		selector["reason"] = opts.Reason
+	}
+	listOptions := metav1.ListOptions{FieldSelector: selector.AsSelector().String()}
+
+	// both APIs serve the same events, an event is read from events.k8s.io/v1 only when core/v1 did not return it,
+	// e.g. because core/v1 cannot be listed
+	var events []common.Event
+	seen := map[types.UID]bool{}
+	coreList, coreErr := k.clientset.CoreV1().Events(namespace).List(r.Context, listOptions)
+	if coreErr == nil {
+		for _, e := range coreList.Items {
+			seen[e.UID] = true
+			events = append(events, coreEvent(e))
+		}
+	}
+	eventsList, eventsErr := k.clientset.EventsV1().Events(namespace).List(r.Context, listOptions)
+	if eventsErr == nil {
+		for _, e := range eventsList.Items {
+			if !seen[e.UID] {
+				events = append(events, eventsV1Event(e))
+			}
+		}
+	}
+	if coreErr != nil && eventsErr != nil {
+		return nil, "", coreErr
+	}
+
+	var cutoff time.Time
+	if opts.Since > 0 {
+		cutoff = time.Now().Add(-opts.Since)
+	}
+	var matched []common.Event
+	for _, e := range events {
+		switch {
+		case opts.Type != "" && e.Type != opts.Type,
+			opts.Reason != "" && e.Reason != opts.Reason,
+			kind != "" && involved == nil && e.Object.Kind != kind,
+			r.Name != "" && involved == nil && e.Object.Name != r.Name,
+			involved != nil && !involved[eventKey{e.Object.Kind, e.Object.Namespace, e.Object.Name}] && !involved[eventKey{e.Object.Kind, "", e.Object.Name}]:
+			continue
+		}
+		matched = append(matched, e)
+	}
+
+	var timeline []common.Event
+	for _, e := range collapseEvents(matched) {
+		if e.LastSeen.Before(cutoff) {
+			continue
+		}
+		timeline = append(timeline, e)
+	}
+	return timeline, subject, nil
+}
+
+// involvedObjects returns the object named by r and the objects it owns. An object that no longer exists only
+// matches its own events.
+func (k *Kubernetes) involvedObjects(r common.Request, opts common.EventOptions, mapping *meta.RESTMapping) (map[eventKey]bool, string, error) {
+	kind := mapping.GroupVersionKind.Kind
+	namespace := ""
+	if isNamespaced(mapping) {
+		if opts.AllNamespaces {
+			return nil, "", fmt.Errorf("the namespace of %s %s is required, all namespaces cannot be used with a name", kind, r.Name)
+		}
+		namespace = utils.NamespaceOrDefault(r.Namespace)
+	}
+	involved := map[eventKey]bool{{kind: kind, namespace: namespace, name: r.Name}: true}
+	subject := fmt.Sprintf("%s %s", kind, objectKey(namespace, r.Name))
+
+	obj, err := k.dynamicClient.Resource(mapping.Resource).Namespace(namespace).Get(r.Context, r.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) || namespace == "" {
+		return involved, subject, nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	dependents, _ := k.dependents(r, namespace, []unstructured.Unstructured{*obj})
+	for _, ref := range dependents {
+		involved[eventKey{kind: ref.Kind, namespace: ref.Namespace, name: ref.Name}] = true
+	}
+	if len(dependents) > 0 {
+		subject = fmt.Sprintf("%s and the %d objects it owns", subject, len(dependents))
+	}
+	return involved, subject, nil
+}
+
+func coreEvent(e v1.Event) common.Event {
+	event := common.Event{
+		Type:    e.Type,
+		Reason:  e.Reason,
+		Message: e.Message,
+		Object: common.ObjectReference{
+			APIVersion: e.InvolvedObject.APIVersion,
+			Kind:       e.InvolvedObject.Kind,
+			Namespace:  e.InvolvedObject.Namespace,
+			Name:       e.InvolvedObject.Name,
+		},
+		Count:     e.Count,
+		FirstSeen: e.FirstTimestamp.Time,
+		LastSeen:  e.LastTimestamp.Time,
+	}
+	if e.Series != nil {
+		event.Count, event.LastSeen = e.Series.Count, e.Series.LastObservedTime.Time
+	}
+	return normalizeEvent(event, e.EventTime.Time, e.CreationTimestamp.Time)
+}
+
+func eventsV1Event(e eventsv1.Event) common.Event {
+	event := common.Event{
+		Type:    e.Type,
+		Reason:  e.Reason,
+		Message: e.Note,
+		Object: common.ObjectReference{
+			APIVersion: e.Regarding.APIVersion,
+			Kind:       e.Regarding.Kind,
+			Namespace:  e.Regarding.Namespace,
+			Name:       e.Regarding.Name,
+		},
+		Count:     e.DeprecatedCount,
+		FirstSeen: e.DeprecatedFirstTimestamp.Time,
+		LastSeen:  e.DeprecatedLastTimestamp.Time,
+	}
+	if e.Series != nil {
+		event.Count, event.LastSeen = e.Series.Count, e.Series.LastObservedTime.Time
+	}
+	return normalizeEvent(event, e.EventTime.Time, e.CreationTimestamp.Time)
+}
+
+// normalizeEvent fills the times and count the recorder left empty, newer recorders only set the event time and
+// older ones only the first and last timestamps.
+func normalizeEvent(event common.Event, eventTime, created time.Time) common.Event {
+	if event.FirstSeen.IsZero() {
+		event.FirstSeen = eventTime
+	}
+	if event.FirstSeen.IsZero() {
+		event.FirstSeen = created
+	}
+	if event.LastSeen.IsZero() {
+		event.LastSeen = event.FirstSeen
+	}
+	if event.Count == 0 {
+		event.Count = 1
+	}
+	return event
+}
+
+// collapseEvents merges the events repeating the same type, reason and message for the same object, adding up
+// their counts, and sorts them by the time they were last seen.
+func collapseEvents(events []common.Event) []common.Event {
+	type key struct {
+		object  eventKey
+		typ     string
+		reason  string
+		message string
+	}
+	index := map[key]int{}
+	var collapsed []common.Event
+	for _, e := range events {
+		k := key{
+			object:  eventKey{kind: e.Object.Kind, namespace: e.Object.Namespace, name: e.Object.Name},
+			typ:     e.Type,
+			reason:  e.Reason,
+			message: e.Message,
+		}
+		i, ok := index[k]
+		if !ok {
+			index[k] = len(collapsed)
+			collapsed = append(collapsed, e)
+			continue
+		}
+		c := &collapsed[i]
+		c.Count += e.Count
+		if e.FirstSeen.Before(c.FirstSeen) {
+			c.FirstSeen = e.FirstSeen
+		}
+		if e.LastSeen.After(c.LastSeen) {
+			c.LastSeen = e.LastSeen
+		}
+	}
+	sort.SliceStable(collapsed, func(i, j int) bool {
+		if !collapsed[i].LastSeen.Equal(collapsed[j].LastSeen) {
+			return collapsed[i].LastSeen.Before(collapsed[j].LastSeen)
+		}
+		return collapsed[i].FirstSeen.Before(collapsed[j].FirstSeen)
+	})
+	return collapsed
+}
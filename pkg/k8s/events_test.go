@@ -0,0 +1,108 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	v1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newCoreEvent(name, kind, object, eventType, reason, message string, count int32, first, last time.Time) *v1.Event {
+	return &v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: name, UID: types.UID(name)},
+		InvolvedObject: v1.ObjectReference{Kind: kind, Namespace: "default", Name: object},
+		Type:           eventType,
+		Reason:         reason,
+		Message:        message,
+		Count:          count,
+		FirstTimestamp: metav1.NewTime(first),
+		LastTimestamp:  metav1.NewTime(last),
+	}
+}
+
+func newEventsClientset(now time.Time) *fake.Clientset {
+	return fake.NewSimpleClientset(
+		newCoreEvent("deploy-scaled", "Deployment", "web", v1.EventTypeNormal, "ScalingReplicaSet", "Scaled up replica set web-1 to 2", 1, now.Add(-10*time.Minute), now.Add(-10*time.Minute)),
+		newCoreEvent("deploy-old", "Deployment", "web", v1.EventTypeNormal, "ScalingReplicaSet", "Scaled up replica set web-0 to 2", 1, now.Add(-3*time.Hour), now.Add(-3*time.Hour)),
+		newCoreEvent("pod-a-backoff-1", "Pod", "web-1-a", v1.EventTypeWarning, "BackOff", "Back-off restarting failed container", 3, now.Add(-8*time.Minute), now.Add(-5*time.Minute)),
+		newCoreEvent("pod-a-backoff-2", "Pod", "web-1-a", v1.EventTypeWarning, "BackOff", "Back-off restarting failed container", 2, now.Add(-4*time.Minute), now.Add(-2*time.Minute)),
+		newCoreEvent("standalone-pulled", "Pod", "standalone", v1.EventTypeNormal, "Pulled", "Successfully pulled image", 1, now.Add(-time.Minute), now.Add(-time.Minute)),
+		// the same event read through events.k8s.io/v1 is not reported twice
+		&eventsv1.Event{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "deploy-scaled", UID: "deploy-scaled"},
+			Regarding:  v1.ObjectReference{Kind: "Deployment", Namespace: "default", Name: "web"},
+			Type:       v1.EventTypeNormal,
+			Reason:     "ScalingReplicaSet",
+			Note:       "Scaled up replica set web-1 to 2",
+			EventTime:  metav1.NewMicroTime(now.Add(-10 * time.Minute)),
+		},
+		&eventsv1.Event{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-b-unhealthy", UID: "pod-b-unhealthy"},
+			Regarding:  v1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "web-1-b"},
+			Type:       v1.EventTypeWarning,
+			Reason:     "Unhealthy",
+			Note:       "Readiness probe failed",
+			EventTime:  metav1.NewMicroTime(now.Add(-6 * time.Minute)),
+			Series:     &eventsv1.EventSeries{Count: 4, LastObservedTime: metav1.NewMicroTime(now.Add(-3 * time.Minute))},
+		},
+	)
+}
+
+func TestEvents(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	k := newTestKubernetes(newEventsClientset(now), newImpactDynamicClient())
+	useDiscovery(k, newKindDiscovery())
+
+	t.Run("Timeline of an object and the objects it owns", func(t *testing.T) {
+		events, subject, err := k.listEvents(common.Request{Context: context.Background(), Kind: "deploy", Namespace: "default", Name: "web"}, common.EventOptions{Since: time.Hour})
+		assert.NoError(t, err)
+		assert.Equal(t, "Deployment default/web and the 3 objects it owns", subject)
+		if assert.Len(t, events, 3) {
+			assert.Equal(t, "ScalingReplicaSet", events[0].Reason)
+			assert.Equal(t, int32(1), events[0].Count)
+			assert.Equal(t, "Unhealthy", events[1].Reason)
+			assert.Equal(t, int32(4), events[1].Count)
+			assert.Equal(t, now.Add(-3*time.Minute), events[1].LastSeen)
+			assert.Equal(t, "BackOff", events[2].Reason)
+			assert.Equal(t, int32(5), events[2].Count)
+			assert.Equal(t, now.Add(-8*time.Minute), events[2].FirstSeen)
+			assert.Equal(t, now.Add(-2*time.Minute), events[2].LastSeen)
+		}
+	})
+
+	t.Run("Filter by type and kind", func(t *testing.T) {
+		events, _, err := k.listEvents(common.Request{Context: context.Background(), Kind: "po"}, common.EventOptions{Type: v1.EventTypeWarning})
+		assert.NoError(t, err)
+		assert.Len(t, events, 2)
+		for _, e := range events {
+			assert.Equal(t, "Pod", e.Object.Kind)
+			assert.Equal(t, v1.EventTypeWarning, e.Type)
+		}
+	})
+
+	t.Run("Filter by reason across namespaces", func(t *testing.T) {
+		res, err := k.Events(common.Request{Context: context.Background()}, common.EventOptions{AllNamespaces: true, Reason: "Pulled"})
+		assert.NoError(t, err)
+		assert.Equal(t, "1 events for all namespaces, oldest first:\n"+now.Add(-time.Minute).UTC().Format(time.RFC3339)+" Normal Pulled Pod default/standalone: Successfully pulled image", res)
+	})
+
+	t.Run("Repeated events show their count", func(t *testing.T) {
+		res, err := k.Events(common.Request{Context: context.Background(), Name: "web-1-a"}, common.EventOptions{})
+		assert.NoError(t, err)
+		assert.Contains(t, res, "Warning BackOff Pod default/web-1-a (x5 since "+now.Add(-8*time.Minute).UTC().Format(time.RFC3339)+"): Back-off restarting failed container")
+	})
+
+	t.Run("Invalid options", func(t *testing.T) {
+		_, err := k.Events(common.Request{Context: context.Background()}, common.EventOptions{Type: "Error"})
+		assert.EqualError(t, err, "unknown event type Error, expected Normal or Warning")
+		_, err = k.Events(common.Request{Context: context.Background(), Kind: "deploy", Name: "web"}, common.EventOptions{AllNamespaces: true})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,250 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// ResourceExplain describes a kind or one of its fields like kubectl explain, field is a dotted path below the kind
+// such as spec.template.spec.containers. The OpenAPI v3 schema served by the cluster is used, so custom resources
+// are explained as well as built-in kinds. recursive lists the nested fields with their types instead of the
+// descriptions of the direct fields.
+func (k *Kubernetes) ResourceExplain(r common.Request, field string, recursive bool) (string, error) {
+	mapping, err := k.resolveKind(r.Kind, r.APIVersion)
+	if err != nil {
+		return "", err
+	}
+	gvk := mapping.GroupVersionKind
+	schemas, root, err := k.openAPIV3Schema(gvk)
+	if err != nil {
+		return "", err
+	}
+
+	e := explainer{schemas: schemas}
+	s, name, required := e.resolve(root), "", false
+	var path []string
+	if field != "" {
+		path = strings.Split(strings.Trim(field, "."), ".")
+	}
+	for i, segment := range path {
+		parent := e.element(s)
+		prop, ok := parent.Properties[segment]
+		if !ok {
+			return "", fmt.Errorf("field %s does not exist in %s", strings.Join(path[:i+1], "."), gvk.Kind)
+		}
+		s, name, required = &prop, segment, slices.Contains(parent.Required, segment)
+	}
+
+	var b strings.Builder
+	if gvk.Group != "" {
+		fmt.Fprintf(&b, "GROUP:      %s\n", gvk.Group)
+	}
+	fmt.Fprintf(&b, "KIND:       %s\nVERSION:    %s\n\n", gvk.Kind, gvk.Version)
+	if name != "" {
+		fmt.Fprintf(&b, "FIELD: %s <%s>%s\n", name, e.typeName(s), requiredMarker(required))
+		if enum := enumValues(e.resolve(s)); enum != "" {
+			fmt.Fprintf(&b, "ENUM: %s\n", enum)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "DESCRIPTION:\n%s\n", indent(e.description(s), "    "))
+
+	fields := e.element(s)
+	if len(fields.Properties) == 0 {
+		return b.String(), nil
+	}
+	b.WriteString("\nFIELDS:\n")
+	if recursive {
+		e.writeRecursive(&b, fields, "  ", map[*spec.Schema]bool{})
+	} else {
+		for _, prop := range sortedProperties(fields) {
+			child := fields.Properties[prop]
+			fmt.Fprintf(&b, "  %s\t<%s>%s\n", prop, e.typeName(&child), requiredMarker(slices.Contains(fields.Required, prop)))
+			if enum := enumValues(e.resolve(&child)); enum != "" {
+				fmt.Fprintf(&b, "    enum: %s\n", enum)
+			}
+			fmt.Fprintf(&b, "%s\n\n", indent(e.description(&child), "    "))
+		}
+	}
+	return b.String(), nil
+}
+
+// openAPIV3Schema returns the schemas of the group version of gvk and the schema of gvk itself.
+func (k *Kubernetes) openAPIV3Schema(gvk schema.GroupVersionKind) (map[string]*spec.Schema, *spec.Schema, error) {
+	path := "apis/" + gvk.GroupVersion().String()
+	if gvk.Group == "" {
+		path = "api/" + gvk.Version
+	}
+	paths, err := k.discoveryClient.OpenAPIV3().Paths()
+	if err != nil {
+		return nil, nil, err
+	}
+	gv, ok := paths[path]
+	if !ok {
+		return nil, nil, fmt.Errorf("the cluster serves no OpenAPI v3 schema for %s", gvk.GroupVersion())
+	}
+	data, err := gv.Schema("application/json")
+	if err != nil {
+		return nil, nil, err
+	}
+	var doc spec3.OpenAPI
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the OpenAPI v3 schema of %s: %w", gvk.GroupVersion(), err)
+	}
+	if doc.Components == nil {
+		return nil, nil, fmt.Errorf("the OpenAPI v3 schema of %s has no components", gvk.GroupVersion())
+	}
+	for _, s := range doc.Components.Schemas {
+		if hasGroupVersionKind(s, gvk) {
+			return doc.Components.Schemas, s, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("the OpenAPI v3 schema of %s does not describe kind %s", gvk.GroupVersion(), gvk.Kind)
+}
+
+func hasGroupVersionKind(s *spec.Schema, gvk schema.GroupVersionKind) bool {
+	gvks, _ := s.Extensions["x-kubernetes-group-version-kind"].([]interface{})
+	for _, v := range gvks {
+		m, _ := v.(map[string]interface{})
+		if m["group"] == gvk.Group && m["version"] == gvk.Version && m["kind"] == gvk.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+type explainer struct {
+	schemas map[string]*spec.Schema
+}
+
+// resolve follows a reference, also when it is wrapped in an allOf to carry a description as the OpenAPI v3
+// schemas of kubernetes do.
+func (e explainer) resolve(s *spec.Schema) *spec.Schema {
+	for i := 0; i < 10; i++ {
+		ref := refName(s)
+		if ref == "" {
+			return s
+		}
+		target, ok := e.schemas[ref]
+		if !ok {
+			return s
+		}
+		s = target
+	}
+	return s
+}
+
+func refName(s *spec.Schema) string {
+	if ref := s.Ref.String(); ref != "" {
+		return strings.TrimPrefix(ref, "#/components/schemas/")
+	}
+	if len(s.AllOf) == 1 {
+		return refName(&s.AllOf[0])
+	}
+	return ""
+}
+
+// element returns the schema holding the fields of s, stepping into the items of arrays and the values of maps.
+func (e explainer) element(s *spec.Schema) *spec.Schema {
+	s = e.resolve(s)
+	for i := 0; i < 10; i++ {
+		switch {
+		case s.Items != nil && s.Items.Schema != nil:
+			s = e.resolve(s.Items.Schema)
+		case s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil && len(s.Properties) == 0:
+			s = e.resolve(s.AdditionalProperties.Schema)
+		default:
+			return s
+		}
+	}
+	return s
+}
+
+// typeName formats the type of a field like kubectl explain, e.g. <string>, <[]Container> or <map[string]string>.
+func (e explainer) typeName(s *spec.Schema) string {
+	if ref := refName(s); ref != "" {
+		return ref[strings.LastIndex(ref, ".")+1:]
+	}
+	if v, _ := s.Extensions["x-kubernetes-int-or-string"].(bool); v {
+		return "IntOrString"
+	}
+	switch {
+	case s.Type.Contains("array") && s.Items != nil && s.Items.Schema != nil:
+		return "[]" + e.typeName(s.Items.Schema)
+	case s.Type.Contains("object") && s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil:
+		return "map[string]" + e.typeName(s.AdditionalProperties.Schema)
+	case len(s.Type) > 0 && !s.Type.Contains("object"):
+		return s.Type[0]
+	}
+	return "Object"
+}
+
+// description prefers the description of the field over the one of the type it references.
+func (e explainer) description(s *spec.Schema) string {
+	if s.Description != "" {
+		return s.Description
+	}
+	if d := e.resolve(s).Description; d != "" {
+		return d
+	}
+	return "<empty>"
+}
+
+func (e explainer) writeRecursive(b *strings.Builder, s *spec.Schema, prefix string, visiting map[*spec.Schema]bool) {
+	// recursive types, such as the JSONSchemaProps of custom resource definitions, are listed once
+	if visiting[s] {
+		return
+	}
+	visiting[s] = true
+	defer delete(visiting, s)
+
+	for _, prop := range sortedProperties(s) {
+		child := s.Properties[prop]
+		fmt.Fprintf(b, "%s%s\t<%s>%s", prefix, prop, e.typeName(&child), requiredMarker(slices.Contains(s.Required, prop)))
+		if enum := enumValues(e.resolve(&child)); enum != "" {
+			fmt.Fprintf(b, " enum: %s", enum)
+		}
+		b.WriteString("\n")
+		e.writeRecursive(b, e.element(&child), prefix+"  ", visiting)
+	}
+}
+
+func sortedProperties(s *spec.Schema) []string {
+	props := make([]string, 0, len(s.Properties))
+	for prop := range s.Properties {
+		props = append(props, prop)
+	}
+	sort.Strings(props)
+	return props
+}
+
+func enumValues(s *spec.Schema) string {
+	values := make([]string, 0, len(s.Enum))
+	for _, v := range s.Enum {
+		values = append(values, fmt.Sprint(v))
+	}
+	return strings.Join(values, ", ")
+}
+
+func requiredMarker(required bool) string {
+	if required {
+		return " -required-"
+	}
+	return ""
+}
+
+func indent(text, prefix string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
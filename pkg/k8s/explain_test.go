@@ -0,0 +1,123 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/openapi"
+	"k8s.io/client-go/restmapper"
+)
+
+const testOpenAPIV3Schema = `{
+  "openapi": "3.0.0",
+  "components": {"schemas": {
+    "io.k8s.api.apps.v1.Deployment": {
+      "description": "Deployment enables declarative updates for Pods and ReplicaSets.",
+      "type": "object",
+      "x-kubernetes-group-version-kind": [{"group": "apps", "kind": "Deployment", "version": "v1"}],
+      "properties": {
+        "apiVersion": {"description": "APIVersion defines the versioned schema of this representation of an object.", "type": "string"},
+        "spec": {"allOf": [{"$ref": "#/components/schemas/io.k8s.api.apps.v1.DeploymentSpec"}], "default": {}, "description": "Specification of the desired behavior of the Deployment."}
+      }
+    },
+    "io.k8s.api.apps.v1.DeploymentSpec": {
+      "description": "DeploymentSpec is the specification of the desired behavior of the Deployment.",
+      "type": "object",
+      "required": ["selector"],
+      "properties": {
+        "replicas": {"description": "Number of desired pods.", "type": "integer", "format": "int32"},
+        "selector": {"allOf": [{"$ref": "#/components/schemas/io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector"}], "description": "Label selector for pods."},
+        "strategy": {"allOf": [{"$ref": "#/components/schemas/io.k8s.api.apps.v1.DeploymentStrategy"}], "description": "The deployment strategy to use."}
+      }
+    },
+    "io.k8s.api.apps.v1.DeploymentStrategy": {
+      "type": "object",
+      "properties": {
+        "type": {"description": "Type of deployment.", "type": "string", "enum": ["Recreate", "RollingUpdate"]}
+      }
+    },
+    "io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector": {
+      "description": "A label selector is a label query over a set of resources.",
+      "type": "object",
+      "properties": {
+        "matchLabels": {"description": "matchLabels is a map of {key,value} pairs.", "type": "object", "additionalProperties": {"type": "string", "default": ""}}
+      }
+    }
+  }}
+}`
+
+type fakeOpenAPIGroupVersion struct {
+	schema string
+}
+
+func (g fakeOpenAPIGroupVersion) Schema(contentType string) ([]byte, error) {
+	return []byte(g.schema), nil
+}
+
+func (g fakeOpenAPIGroupVersion) ServerRelativeURL() string {
+	return ""
+}
+
+type fakeOpenAPIV3Client map[string]openapi.GroupVersion
+
+func (c fakeOpenAPIV3Client) Paths() (map[string]openapi.GroupVersion, error) {
+	return c, nil
+}
+
+type fakeOpenAPIV3Discovery struct {
+	*fakeDiscovery
+	client openapi.Client
+}
+
+func (d fakeOpenAPIV3Discovery) OpenAPIV3() openapi.Client {
+	return d.client
+}
+
+func TestResourceExplain(t *testing.T) {
+	discovery := fakeOpenAPIV3Discovery{
+		fakeDiscovery: newTestDiscoveryClient(),
+		client:        fakeOpenAPIV3Client{"apis/apps/v1": fakeOpenAPIGroupVersion{schema: testOpenAPIV3Schema}},
+	}
+	cachedDiscovery := memory.NewMemCacheClient(discovery)
+	k := &Kubernetes{
+		clientset:                   fake.NewSimpleClientset(),
+		discoveryClient:             discovery,
+		cachedDiscoveryClient:       cachedDiscovery,
+		deferredDiscoveryRESTMapper: restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscovery),
+	}
+
+	t.Run("Explain a kind", func(t *testing.T) {
+		res, err := k.ResourceExplain(common.Request{Kind: "deployment"}, "", false)
+		assert.NoError(t, err)
+		assert.Contains(t, res, "GROUP:      apps\nKIND:       Deployment\nVERSION:    v1")
+		assert.Contains(t, res, "Deployment enables declarative updates")
+		assert.Contains(t, res, "  spec\t<DeploymentSpec>\n    Specification of the desired behavior of the Deployment.")
+	})
+
+	t.Run("Explain a field", func(t *testing.T) {
+		res, err := k.ResourceExplain(common.Request{Kind: "Deployment", APIVersion: "apps/v1"}, "spec", false)
+		assert.NoError(t, err)
+		assert.Contains(t, res, "FIELD: spec <DeploymentSpec>")
+		assert.Contains(t, res, "  selector\t<LabelSelector> -required-")
+		assert.Contains(t, res, "  replicas\t<integer>\n    Number of desired pods.")
+
+		res, err = k.ResourceExplain(common.Request{Kind: "Deployment"}, "spec.strategy.type", false)
+		assert.NoError(t, err)
+		assert.Contains(t, res, "FIELD: type <string>\nENUM: Recreate, RollingUpdate")
+	})
+
+	t.Run("Explain recursively", func(t *testing.T) {
+		res, err := k.ResourceExplain(common.Request{Kind: "Deployment"}, "spec", true)
+		assert.NoError(t, err)
+		assert.Contains(t, res, "  selector\t<LabelSelector> -required-\n    matchLabels\t<map[string]string>\n")
+		assert.Contains(t, res, "  strategy\t<DeploymentStrategy>\n    type\t<string> enum: Recreate, RollingUpdate\n")
+	})
+
+	t.Run("Unknown field", func(t *testing.T) {
+		_, err := k.ResourceExplain(common.Request{Kind: "Deployment"}, "spec.replica", false)
+		assert.EqualError(t, err, "field spec.replica does not exist in Deployment")
+	})
+}
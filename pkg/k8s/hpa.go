@@ -0,0 +1,185 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// AnalyzeHPA analyzes the horizontal pod autoscalers and returns a list of failures.
+func (k *Kubernetes) AnalyzeHPA(r common.Request) (string, error) {
+	return marshalResults(k.analyzeHPA(r.Context, r))
+}
+
+func (k *Kubernetes) analyzeHPA(ctx context.Context, r common.Request) ([]common.Result, error) {
+	kind := "HorizontalPodAutoscaler"
+	apiDoc := K8sApiReference{
+		Kind: kind,
+		ApiVersion: schema.GroupVersion{
+			Group:   "autoscaling",
+			Version: "v2",
+		},
+		OpenapiSchema: k.openapiSchema,
+	}
+
+	hpaList, err := k.clientset.AutoscalingV2().HorizontalPodAutoscalers(r.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var preAnalysis = map[string]common.PreAnalysis{}
+
+	for _, hpa := range hpaList.Items {
+		var failures []common.Failure
+
+		for _, cond := range hpa.Status.Conditions {
+			if cond.Status != v1.ConditionFalse {
+				continue
+			}
+			switch cond.Type {
+			case autoscalingv2.ScalingActive:
+				failures = append(failures, common.Failure{
+					ID:            "HPA_METRICS_UNAVAILABLE",
+					Severity:      common.SeverityCritical,
+					Text:          fmt.Sprintf("HPA is unable to compute metrics (%s): %s", cond.Reason, cond.Message),
+					KubernetesDoc: apiDoc.GetApiDocV2("spec.metrics"),
+					FieldPath:     "spec.metrics",
+					Remediation:   &common.Remediation{Text: "Check that the metrics server or the custom metrics adapter serves the metrics the HPA reads"},
+				})
+			case autoscalingv2.AbleToScale:
+				failures = append(failures, common.Failure{
+					ID:          "HPA_UNABLE_TO_SCALE",
+					Severity:    common.SeverityCritical,
+					Text:        fmt.Sprintf("HPA is unable to scale (%s): %s", cond.Reason, cond.Message),
+					FieldPath:   "status.conditions",
+					Remediation: &common.Remediation{Text: "Check that the scale target exists and exposes the scale subresource"},
+				})
+			}
+		}
+
+		if hpa.Spec.MaxReplicas > 0 && hpa.Status.CurrentReplicas >= hpa.Spec.MaxReplicas && hpa.Status.DesiredReplicas >= hpa.Spec.MaxReplicas {
+			failures = append(failures, common.Failure{
+				ID:            "HPA_AT_MAX_REPLICAS",
+				Severity:      common.SeverityWarning,
+				Text:          fmt.Sprintf("HPA is pinned at its maximum of %d replicas, the load may need more", hpa.Spec.MaxReplicas),
+				KubernetesDoc: apiDoc.GetApiDocV2("spec.maxReplicas"),
+				FieldPath:     "spec.maxReplicas",
+				Remediation:   &common.Remediation{Text: "Raise maxReplicas if the cluster has room, or make each replica handle more load"},
+			})
+		}
+
+		ref := hpa.Spec.ScaleTargetRef
+		podSpec, supported, err := k.scaleTargetPodSpec(ctx, hpa.Namespace, ref)
+		switch {
+		case errors.IsNotFound(err):
+			failures = append(failures, common.Failure{
+				ID:             "HPA_TARGET_NOT_FOUND",
+				Severity:       common.SeverityCritical,
+				Text:           fmt.Sprintf("HPA targets the %s %s/%s which does not exist", ref.Kind, hpa.Namespace, ref.Name),
+				KubernetesDoc:  apiDoc.GetApiDocV2("spec.scaleTargetRef"),
+				FieldPath:      "spec.scaleTargetRef",
+				RelatedObjects: []common.ObjectReference{{Kind: ref.Kind, Namespace: hpa.Namespace, Name: ref.Name}},
+				Remediation:    &common.Remediation{Text: "Point scaleTargetRef at an existing workload or delete the HPA"},
+			})
+		case err != nil && ctx.Err() != nil:
+			return nil, err
+		case err != nil:
+			// a target that cannot be read, e.g. a custom resource the analyzer may not get, does not stop the
+			// analysis of the other HPAs
+			failures = append(failures, common.Failure{
+				ID:             "HPA_TARGET_UNREADABLE",
+				Severity:       common.SeverityWarning,
+				Text:           fmt.Sprintf("Failed to read the %s %s/%s the HPA targets: %v", ref.Kind, hpa.Namespace, ref.Name, err),
+				FieldPath:      "spec.scaleTargetRef",
+				RelatedObjects: []common.ObjectReference{{Kind: ref.Kind, Namespace: hpa.Namespace, Name: ref.Name}},
+				Remediation:    &common.Remediation{Text: "Allow reading the scale target to check its resource requests"},
+			})
+		case supported:
+			failures = append(failures, missingUtilizationRequests(hpa, ref, podSpec)...)
+		}
+
+		if len(failures) > 0 {
+			preAnalysis[fmt.Sprintf("%s/%s", hpa.Namespace, hpa.Name)] = common.PreAnalysis{
+				HPA:            hpa,
+				FailureDetails: failures,
+			}
+		}
+	}
+
+	return k.preAnalysisResults(ctx, kind, preAnalysis, nil), nil
+}
+
+// scaleTargetPodSpec returns the pod spec of the built-in workload an HPA scales, false is returned for other kinds.
+func (k *Kubernetes) scaleTargetPodSpec(ctx context.Context, namespace string, ref autoscalingv2.CrossVersionObjectReference) (*v1.PodSpec, bool, error) {
+	switch ref.Kind {
+	case "Deployment":
+		deploy, err := k.clientset.AppsV1().Deployments(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, true, err
+		}
+		return &deploy.Spec.Template.Spec, true, nil
+	case "StatefulSet":
+		sts, err := k.clientset.AppsV1().StatefulSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, true, err
+		}
+		return &sts.Spec.Template.Spec, true, nil
+	case "ReplicaSet":
+		rs, err := k.clientset.AppsV1().ReplicaSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, true, err
+		}
+		return &rs.Spec.Template.Spec, true, nil
+	case "ReplicationController":
+		rc, err := k.clientset.CoreV1().ReplicationControllers(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, true, err
+		}
+		if rc.Spec.Template == nil {
+			return nil, false, nil
+		}
+		return &rc.Spec.Template.Spec, true, nil
+	}
+	return nil, false, nil
+}
+
+// missingUtilizationRequests flags containers without a request for a resource the HPA scales on by utilization,
+// the utilization is a percentage of the request and cannot be computed without one.
+func missingUtilizationRequests(hpa autoscalingv2.HorizontalPodAutoscaler, ref autoscalingv2.CrossVersionObjectReference, podSpec *v1.PodSpec) []common.Failure {
+	var failures []common.Failure
+	for _, metric := range hpa.Spec.Metrics {
+		var resource v1.ResourceName
+		var container string
+		switch {
+		case metric.Type == autoscalingv2.ResourceMetricSourceType && metric.Resource != nil && metric.Resource.Target.Type == autoscalingv2.UtilizationMetricType:
+			resource = metric.Resource.Name
+		case metric.Type == autoscalingv2.ContainerResourceMetricSourceType && metric.ContainerResource != nil && metric.ContainerResource.Target.Type == autoscalingv2.UtilizationMetricType:
+			resource = metric.ContainerResource.Name
+			container = metric.ContainerResource.Container
+		default:
+			continue
+		}
+
+		for _, c := range podSpec.Containers {
+			if container != "" && c.Name != container {
+				continue
+			}
+			if _, ok := c.Resources.Requests[resource]; !ok {
+				failures = append(failures, common.Failure{
+					ID:             "HPA_MISSING_RESOURCE_REQUEST",
+					Severity:       common.SeverityWarning,
+					Text:           fmt.Sprintf("Container %s of %s %s has no %s request, the HPA cannot compute its %s utilization", c.Name, ref.Kind, ref.Name, resource, resource),
+					RelatedObjects: []common.ObjectReference{{Kind: ref.Kind, Namespace: hpa.Namespace, Name: ref.Name}},
+					Remediation:    &common.Remediation{Text: fmt.Sprintf("Set a %s request on container %s of %s %s", resource, c.Name, ref.Kind, ref.Name)},
+				})
+			}
+		}
+	}
+	return failures
+}
@@ -0,0 +1,99 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+)
+
+func newTestHPA() *autoscalingv2.HorizontalPodAutoscaler {
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "api"},
+			MinReplicas:    ptr.To(int32(2)),
+			MaxReplicas:    10,
+			Metrics: []autoscalingv2.MetricSpec{{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricSource{
+					Name:   v1.ResourceCPU,
+					Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: ptr.To(int32(80))},
+				},
+			}},
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{CurrentReplicas: 3, DesiredReplicas: 3},
+	}
+}
+
+func TestAnalyzeHPA(t *testing.T) {
+	request := common.Request{Context: context.Background(), Namespace: "default"}
+
+	t.Run("Healthy hpa", func(t *testing.T) {
+		k := newTestKubernetes(fake.NewSimpleClientset(newTestHPA(), newHealthyDeployment()), nil)
+		result, err := k.AnalyzeHPA(request)
+		assert.NoError(t, err)
+		assert.Equal(t, "[]", result)
+	})
+
+	t.Run("Missing scale target", func(t *testing.T) {
+		k := newTestKubernetes(fake.NewSimpleClientset(newTestHPA()), nil)
+		result, err := k.AnalyzeHPA(request)
+		assert.NoError(t, err)
+		assert.Contains(t, result, "HPA targets the Deployment default/api which does not exist")
+	})
+
+	t.Run("Metrics failure, pinned at max and missing requests", func(t *testing.T) {
+		hpa := newTestHPA()
+		hpa.Status = autoscalingv2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: 10,
+			DesiredReplicas: 10,
+			Conditions: []autoscalingv2.HorizontalPodAutoscalerCondition{{
+				Type: autoscalingv2.ScalingActive, Status: v1.ConditionFalse,
+				Reason: "FailedGetResourceMetric", Message: "the HPA was unable to compute the replica count",
+			}},
+		}
+		deploy := newHealthyDeployment()
+		deploy.Spec.Template.Spec.Containers[0].Resources = v1.ResourceRequirements{}
+		k := newTestKubernetes(fake.NewSimpleClientset(hpa, deploy), nil)
+		result, err := k.AnalyzeHPA(request)
+		assert.NoError(t, err)
+		assert.Contains(t, result, "HPA is unable to compute metrics (FailedGetResourceMetric)")
+		assert.Contains(t, result, "HPA is pinned at its maximum of 10 replicas")
+		assert.Contains(t, result, "Container api of Deployment api has no cpu request")
+	})
+
+	t.Run("Unreadable scale target", func(t *testing.T) {
+		other := newTestHPA()
+		other.Name = "web"
+		clientset := fake.NewSimpleClientset(newTestHPA(), other)
+		clientset.PrependReactor("get", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, "api", errors.New("RBAC denied"))
+		})
+		k := newTestKubernetes(clientset, nil)
+		result, err := k.AnalyzeHPA(request)
+		assert.NoError(t, err, "a scale target that cannot be read should not fail the analyzer")
+		assert.Contains(t, result, "Failed to read the Deployment default/api the HPA targets")
+		assert.Contains(t, result, "default/web")
+	})
+
+	t.Run("Unsupported target kind", func(t *testing.T) {
+		hpa := newTestHPA()
+		hpa.Spec.ScaleTargetRef = autoscalingv2.CrossVersionObjectReference{APIVersion: "example.com/v1", Kind: "Worker", Name: "api"}
+		k := newTestKubernetes(fake.NewSimpleClientset(hpa), nil)
+		result, err := k.AnalyzeHPA(request)
+		assert.NoError(t, err)
+		assert.Equal(t, "[]", result)
+	})
+}
@@ -0,0 +1,157 @@
+package k8s
+
+import (
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var podsResource = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+// dependentResources hold the objects controllers create for their owners, they are searched for the objects a
+// delete cascades to.
+var dependentResources = []schema.GroupVersionResource{
+	{Group: "apps", Version: "v1", Resource: "replicasets"},
+	{Group: "apps", Version: "v1", Resource: "controllerrevisions"},
+	{Group: "batch", Version: "v1", Resource: "jobs"},
+	podsResource,
+}
+
+// DeleteImpact lists the objects ResourceDelete would delete for r and the owned objects the delete cascades to,
+// nothing cascades with the Orphan propagation policy. Dependents the user cannot list are not reported.
+func (k *Kubernetes) DeleteImpact(r common.Request, opts common.DeleteOptions) (common.DeleteImpact, error) {
+	var impact common.DeleteImpact
+	if err := validateDelete(r, opts); err != nil {
+		return impact, err
+	}
+	mapping, err := k.resolveKind(r.Kind, r.APIVersion)
+	if err != nil {
+		return impact, err
+	}
+	namespace := ""
+	if isNamespaced(mapping) {
+		namespace = utils.NamespaceOrDefault(r.Namespace)
+	}
+
+	client := k.dynamicClient.Resource(mapping.Resource).Namespace(namespace)
+	var objects []unstructured.Unstructured
+	if r.Name != "" {
+		obj, err := client.Get(r.Context, r.Name, metav1.GetOptions{})
+		if err != nil {
+			return impact, err
+		}
+		objects = []unstructured.Unstructured{*obj}
+	} else {
+		list, err := client.List(r.Context, metav1.ListOptions{LabelSelector: r.LabelSelector})
+		if err != nil {
+			return impact, err
+		}
+		objects = sortObjects(list.Items)
+	}
+
+	impact.Token = deleteToken(mapping, namespace, r.LabelSelector, opts, objects)
+	for _, obj := range objects {
+		impact.Objects = append(impact.Objects, objectReference(mapping.GroupVersionKind.Kind, obj))
+	}
+	switch {
+	case mapping.Resource == podsResource:
+		impact.Pods = len(objects)
+	case mapping.Resource.GroupResource() == schema.GroupResource{Resource: "namespaces"}:
+		// everything in a namespace is deleted with it
+		for _, obj := range objects {
+			pods, err := k.dynamicClient.Resource(podsResource).Namespace(obj.GetName()).List(r.Context, metav1.ListOptions{})
+			if err == nil {
+				impact.Pods += len(pods.Items)
+			}
+		}
+	case opts.PropagationPolicy != string(metav1.DeletePropagationOrphan) && namespace != "":
+		impact.Dependents, impact.Pods = k.dependents(r, namespace, objects)
+	}
+	return impact, nil
+}
+
+// dependents follows the owner references of the dependent resources down from owners and returns the owned objects
+// with the number of pods among them.
+func (k *Kubernetes) dependents(r common.Request, namespace string, owners []unstructured.Unstructured) ([]common.ObjectReference, int) {
+	type candidate struct {
+		gvr schema.GroupVersionResource
+		obj unstructured.Unstructured
+	}
+	var candidates []candidate
+	for _, gvr := range dependentResources {
+		list, err := k.dynamicClient.Resource(gvr).Namespace(namespace).List(r.Context, metav1.ListOptions{})
+		if err != nil {
+			continue
+		}
+		for _, obj := range list.Items {
+			candidates = append(candidates, candidate{gvr: gvr, obj: obj})
+		}
+	}
+
+	owned := map[types.UID]bool{}
+	for _, owner := range owners {
+		owned[owner.GetUID()] = true
+	}
+	var refs []common.ObjectReference
+	var pods int
+	// every pass adds the objects owned by the ones found in the previous pass, e.g. the pods of the ReplicaSets
+	// of a Deployment
+	for found := true; found; {
+		found = false
+		for _, c := range candidates {
+			if owned[c.obj.GetUID()] || !ownedBy(c.obj, owned) {
+				continue
+			}
+			owned[c.obj.GetUID()] = true
+			found = true
+			refs = append(refs, objectReference(c.obj.GetKind(), c.obj))
+			if c.gvr == podsResource {
+				pods++
+			}
+		}
+	}
+	return refs, pods
+}
+
+func ownedBy(obj unstructured.Unstructured, owners map[types.UID]bool) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if owners[ref.UID] {
+			return true
+		}
+	}
+	return false
+}
+
+func objectReference(kind string, obj unstructured.Unstructured) common.ObjectReference {
+	return common.ObjectReference{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+}
+
+// ScaleImpact reports the current replicas of the object ResourceScale would scale for r.
+func (k *Kubernetes) ScaleImpact(r common.Request) (common.ScaleImpact, error) {
+	mapping, err := k.resolveKind(r.Kind, r.APIVersion)
+	if err != nil {
+		return common.ScaleImpact{}, err
+	}
+	scale, err := k.dynamicClient.Resource(mapping.Resource).Namespace(utils.NamespaceOrDefault(r.Namespace)).Get(r.Context, r.Name, metav1.GetOptions{}, "scale")
+	if err != nil {
+		return common.ScaleImpact{}, err
+	}
+	current, _, err := unstructured.NestedInt64(scale.Object, "spec", "replicas")
+	if err != nil {
+		return common.ScaleImpact{}, err
+	}
+	pods, _, _ := unstructured.NestedInt64(scale.Object, "status", "replicas")
+	return common.ScaleImpact{
+		Kind:            mapping.GroupVersionKind.Kind,
+		CurrentReplicas: current,
+		Pods:            int(pods),
+	}, nil
+}
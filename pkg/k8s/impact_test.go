@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func newImpactDynamicClient() *dynamicfake.FakeDynamicClient {
+	listKinds := map[schema.GroupVersionResource]string{
+		{Group: "apps", Version: "v1", Resource: "deployments"}:         "DeploymentList",
+		{Group: "apps", Version: "v1", Resource: "replicasets"}:         "ReplicaSetList",
+		{Group: "apps", Version: "v1", Resource: "controllerrevisions"}: "ControllerRevisionList",
+		{Group: "batch", Version: "v1", Resource: "jobs"}:               "JobList",
+		podsResource: "PodList",
+	}
+	controller := func(apiVersion, kind, name, uid string) *metav1.OwnerReference {
+		return &metav1.OwnerReference{APIVersion: apiVersion, Kind: kind, Name: name, UID: k8stypes.UID(uid), Controller: ptr.To(true)}
+	}
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds,
+		newOwnedObject("apps/v1", "Deployment", "web", "deploy-uid", nil),
+		newOwnedObject("apps/v1", "ReplicaSet", "web-1", "rs-uid", controller("apps/v1", "Deployment", "web", "deploy-uid")),
+		newOwnedObject("v1", "Pod", "web-1-a", "pod-a-uid", controller("apps/v1", "ReplicaSet", "web-1", "rs-uid")),
+		newOwnedObject("v1", "Pod", "web-1-b", "pod-b-uid", controller("apps/v1", "ReplicaSet", "web-1", "rs-uid")),
+		newOwnedObject("v1", "Pod", "standalone", "pod-c-uid", nil),
+	)
+}
+
+func TestDeleteImpact(t *testing.T) {
+	k := newTestKubernetes(kubernetesfake.NewSimpleClientset(), newImpactDynamicClient())
+	useDiscovery(k, newKindDiscovery())
+	r := common.Request{Context: context.Background(), Kind: "deploy", Namespace: "default", Name: "web"}
+
+	t.Run("Cascades to the owned objects", func(t *testing.T) {
+		impact, err := k.DeleteImpact(r, common.DeleteOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, []common.ObjectReference{{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "default", Name: "web"}}, impact.Objects)
+		var dependents []string
+		for _, ref := range impact.Dependents {
+			dependents = append(dependents, ref.Kind+"/"+ref.Name)
+		}
+		assert.ElementsMatch(t, []string{"ReplicaSet/web-1", "Pod/web-1-a", "Pod/web-1-b"}, dependents)
+		assert.Equal(t, 2, impact.Pods)
+		assert.NotEmpty(t, impact.Token)
+	})
+
+	t.Run("Orphan does not cascade", func(t *testing.T) {
+		impact, err := k.DeleteImpact(r, common.DeleteOptions{PropagationPolicy: "Orphan"})
+		assert.NoError(t, err)
+		assert.Empty(t, impact.Dependents)
+		assert.Equal(t, 0, impact.Pods)
+	})
+
+	t.Run("Token depends on the delete options", func(t *testing.T) {
+		gracePeriod := int64(0)
+		tokens := map[string]bool{}
+		for _, opts := range []common.DeleteOptions{
+			{PropagationPolicy: "Orphan"},
+			{PropagationPolicy: "Background"},
+			{PropagationPolicy: "Foreground"},
+			{PropagationPolicy: "Background", GracePeriodSeconds: &gracePeriod},
+			{PropagationPolicy: "Background", UID: "deploy-uid"},
+		} {
+			impact, err := k.DeleteImpact(r, opts)
+			assert.NoError(t, err)
+			tokens[impact.Token] = true
+		}
+		assert.Len(t, tokens, 5)
+	})
+
+	t.Run("Deleting a pod", func(t *testing.T) {
+		impact, err := k.DeleteImpact(common.Request{Context: context.Background(), Kind: "po", Namespace: "default", Name: "standalone"}, common.DeleteOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, impact.Pods)
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		_, err := k.DeleteImpact(common.Request{Context: context.Background(), Kind: "deploy", Namespace: "default"}, common.DeleteOptions{})
+		assert.EqualError(t, err, "a name or a label selector is required")
+	})
+}
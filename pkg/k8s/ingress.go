@@ -1,16 +1,19 @@
 package k8s
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 
 	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
-	"github.com/wenhuwang/mcp-k8s-eye/pkg/utils"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func (k *Kubernetes) AnalyzeIngress(r common.Request) (string, error) {
+	return marshalResults(k.analyzeIngress(r.Context, r))
+}
+
+func (k *Kubernetes) analyzeIngress(ctx context.Context, r common.Request) ([]common.Result, error) {
 	kind := "Ingress"
 	apiDoc := K8sApiReference{
 		Kind: kind,
@@ -21,11 +24,9 @@ func (k *Kubernetes) AnalyzeIngress(r common.Request) (string, error) {
 		OpenapiSchema: k.openapiSchema,
 	}
 
-	ingresses, err := k.clientset.NetworkingV1().Ingresses(r.Namespace).List(r.Context, metav1.ListOptions{
-		LabelSelector: r.LabelSelector,
-	})
+	ingresses, err := k.clientset.NetworkingV1().Ingresses(r.Namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	var preAnalysis = map[string]common.PreAnalysis{}
@@ -39,8 +40,12 @@ func (k *Kubernetes) AnalyzeIngress(r common.Request) (string, error) {
 			if ingClassValue == "" {
 				doc := apiDoc.GetApiDocV2("spec.ingressClassName")
 				failures = append(failures, common.Failure{
+					ID:            "INGRESS_NO_CLASS",
+					Severity:      common.SeverityWarning,
 					Text:          "Ingress does not specify an ingress class",
 					KubernetesDoc: doc,
+					FieldPath:     "spec.ingressClassName",
+					Remediation:   &common.Remediation{Text: "Set spec.ingressClassName, or mark one IngressClass as the cluster default"},
 				})
 			} else {
 				ingressClassName = &ingClassValue
@@ -49,12 +54,17 @@ func (k *Kubernetes) AnalyzeIngress(r common.Request) (string, error) {
 
 		// check if ingressclass exists
 		if ingressClassName != nil {
-			_, err := k.clientset.NetworkingV1().IngressClasses().Get(r.Context, *ingressClassName, metav1.GetOptions{})
+			_, err := k.clientset.NetworkingV1().IngressClasses().Get(ctx, *ingressClassName, metav1.GetOptions{})
 			if err != nil {
 				doc := apiDoc.GetApiDocV2("spec.ingressClassName")
 				failures = append(failures, common.Failure{
-					Text:          fmt.Sprintf("Ingress uses the ingress class %s which does not exist", *ingressClassName),
-					KubernetesDoc: doc,
+					ID:             "INGRESS_CLASS_NOT_FOUND",
+					Severity:       common.SeverityCritical,
+					Text:           fmt.Sprintf("Ingress uses the ingress class %s which does not exist", *ingressClassName),
+					KubernetesDoc:  doc,
+					FieldPath:      "spec.ingressClassName",
+					RelatedObjects: []common.ObjectReference{{Kind: "IngressClass", Name: *ingressClassName}},
+					Remediation:    &common.Remediation{Text: "Use the name of an installed IngressClass, kubectl get ingressclass lists them"},
 				})
 			}
 		}
@@ -65,15 +75,20 @@ func (k *Kubernetes) AnalyzeIngress(r common.Request) (string, error) {
 				continue
 			}
 			for _, path := range rule.HTTP.Paths {
-				_, err := k.clientset.CoreV1().Services(ingress.Namespace).Get(r.Context, path.Backend.Service.Name, metav1.GetOptions{})
+				_, err := k.clientset.CoreV1().Services(ingress.Namespace).Get(ctx, path.Backend.Service.Name, metav1.GetOptions{})
 				if err != nil {
 					doc := apiDoc.GetApiDocV2("spec.rules.http.paths.backend.service")
 					failures = append(failures, common.Failure{
+						ID:       "INGRESS_SERVICE_NOT_FOUND",
+						Severity: common.SeverityCritical,
 						Text: fmt.Sprintf(
 							"Ingress uses the service %s/%s which does not exist",
 							ingress.Namespace, path.Backend.Service.Name,
 						),
-						KubernetesDoc: doc,
+						KubernetesDoc:  doc,
+						FieldPath:      "spec.rules.http.paths.backend.service",
+						RelatedObjects: []common.ObjectReference{{Kind: "Service", Namespace: ingress.Namespace, Name: path.Backend.Service.Name}},
+						Remediation:    &common.Remediation{Text: fmt.Sprintf("Create the service %s or fix the backend service name", path.Backend.Service.Name)},
 					})
 				}
 			}
@@ -81,15 +96,20 @@ func (k *Kubernetes) AnalyzeIngress(r common.Request) (string, error) {
 
 		// check if ingress use a secret that exists
 		for _, tls := range ingress.Spec.TLS {
-			_, err := k.clientset.CoreV1().Secrets(ingress.Namespace).Get(r.Context, tls.SecretName, metav1.GetOptions{})
+			_, err := k.clientset.CoreV1().Secrets(ingress.Namespace).Get(ctx, tls.SecretName, metav1.GetOptions{})
 			if err != nil {
 				doc := apiDoc.GetApiDocV2("spec.tls.secretName")
 				failures = append(failures, common.Failure{
+					ID:       "INGRESS_TLS_SECRET_NOT_FOUND",
+					Severity: common.SeverityCritical,
 					Text: fmt.Sprintf(
 						"Ingress uses the secret %s/%s which does not exist",
 						ingress.Namespace, tls.SecretName,
 					),
-					KubernetesDoc: doc,
+					KubernetesDoc:  doc,
+					FieldPath:      "spec.tls.secretName",
+					RelatedObjects: []common.ObjectReference{{Kind: "Secret", Namespace: ingress.Namespace, Name: tls.SecretName}},
+					Remediation:    &common.Remediation{Text: fmt.Sprintf("Create the TLS secret %s in namespace %s, e.g. with cert-manager", tls.SecretName, ingress.Namespace)},
 				})
 			}
 		}
@@ -102,23 +122,5 @@ func (k *Kubernetes) AnalyzeIngress(r common.Request) (string, error) {
 		}
 	}
 
-	results := make([]common.Result, 0)
-	for key, value := range preAnalysis {
-		analysis := common.Result{
-			Kind:  kind,
-			Name:  key,
-			Error: value.FailureDetails,
-		}
-		parent, found := utils.GetParent(k.clientset, value.Ingress.ObjectMeta)
-		if found {
-			analysis.ParentObject = parent
-		}
-		results = append(results, analysis)
-	}
-
-	jsonData, err := json.Marshal(results)
-	if err != nil {
-		return "", err
-	}
-	return string(jsonData), nil
+	return k.preAnalysisResults(ctx, kind, preAnalysis, func(p common.PreAnalysis) metav1.ObjectMeta { return p.Ingress.ObjectMeta }), nil
 }
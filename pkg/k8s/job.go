@@ -0,0 +1,144 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// AnalyzeJob analyzes the jobs and returns a list of failures.
+func (k *Kubernetes) AnalyzeJob(r common.Request) (string, error) {
+	return marshalResults(k.analyzeJob(r.Context, r))
+}
+
+func (k *Kubernetes) analyzeJob(ctx context.Context, r common.Request) ([]common.Result, error) {
+	kind := "Job"
+	apiDoc := K8sApiReference{
+		Kind: kind,
+		ApiVersion: schema.GroupVersion{
+			Group:   "batch",
+			Version: "v1",
+		},
+		OpenapiSchema: k.openapiSchema,
+	}
+
+	jobList, err := k.clientset.BatchV1().Jobs(r.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	podList, err := k.clientset.CoreV1().Pods(r.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var preAnalysis = map[string]common.PreAnalysis{}
+
+	for _, job := range jobList.Items {
+		var failures []common.Failure
+
+		if jobCondition(job.Status, batchv1.JobComplete) != nil {
+			continue
+		}
+
+		if cond := jobCondition(job.Status, batchv1.JobFailed); cond != nil {
+			switch cond.Reason {
+			case batchv1.JobReasonBackoffLimitExceeded:
+				backoffLimit := int32(6)
+				if job.Spec.BackoffLimit != nil {
+					backoffLimit = *job.Spec.BackoffLimit
+				}
+				failures = append(failures, common.Failure{
+					ID:            "JOB_BACKOFF_LIMIT_EXCEEDED",
+					Severity:      common.SeverityCritical,
+					Text:          fmt.Sprintf("Job has reached the backoff limit of %d retries: %s", backoffLimit, cond.Message),
+					KubernetesDoc: apiDoc.GetApiDocV2("spec.backoffLimit"),
+					FieldPath:     "spec.backoffLimit",
+					Remediation:   &common.Remediation{Text: "Fix the failures of the job pods and re-create the job, a failed job is not retried"},
+				})
+			case batchv1.JobReasonDeadlineExceeded:
+				var deadline int64
+				if job.Spec.ActiveDeadlineSeconds != nil {
+					deadline = *job.Spec.ActiveDeadlineSeconds
+				}
+				failures = append(failures, common.Failure{
+					ID:            "JOB_DEADLINE_EXCEEDED",
+					Severity:      common.SeverityCritical,
+					Text:          fmt.Sprintf("Job was active longer than its deadline of %ds: %s", deadline, cond.Message),
+					KubernetesDoc: apiDoc.GetApiDocV2("spec.activeDeadlineSeconds"),
+					FieldPath:     "spec.activeDeadlineSeconds",
+					Remediation:   &common.Remediation{Text: "Raise activeDeadlineSeconds or speed up the job, then re-create it"},
+				})
+			default:
+				failures = append(failures, common.Failure{
+					ID:        "JOB_FAILED",
+					Severity:  common.SeverityCritical,
+					Text:      fmt.Sprintf("Job failed (%s): %s", cond.Reason, cond.Message),
+					FieldPath: "status.conditions",
+				})
+			}
+		}
+
+		for _, pod := range podList.Items {
+			ref := metav1.GetControllerOf(&pod)
+			if ref == nil || ref.UID != job.UID {
+				continue
+			}
+			if pod.Status.Phase == v1.PodFailed {
+				failures = append(failures, common.Failure{
+					ID:             "JOB_POD_FAILED",
+					Severity:       common.SeverityWarning,
+					Text:           fmt.Sprintf("Pod %s/%s failed: %s", pod.Namespace, pod.Name, failedPodReason(pod)),
+					RelatedObjects: []common.ObjectReference{{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name}},
+					Remediation:    &common.Remediation{Text: fmt.Sprintf("Read the logs of pod %s to find why it failed", pod.Name)},
+				})
+				continue
+			}
+			failures = append(failures, ownedPodFailures(pod, k.analyzePodFailures(ctx, pod))...)
+		}
+
+		if len(failures) > 0 {
+			preAnalysis[fmt.Sprintf("%s/%s", job.Namespace, job.Name)] = common.PreAnalysis{
+				Job:            job,
+				FailureDetails: failures,
+			}
+		}
+	}
+
+	return k.preAnalysisResults(ctx, kind, preAnalysis, func(p common.PreAnalysis) metav1.ObjectMeta { return p.Job.ObjectMeta }), nil
+}
+
+func jobCondition(status batchv1.JobStatus, condType batchv1.JobConditionType) *batchv1.JobCondition {
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == condType && status.Conditions[i].Status == v1.ConditionTrue {
+			return &status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// failedPodReason describes why a pod failed, preferring the termination of its containers over the pod status.
+func failedPodReason(pod v1.Pod) string {
+	for _, status := range append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+		terminated := status.State.Terminated
+		if terminated == nil || terminated.ExitCode == 0 {
+			continue
+		}
+		reason := fmt.Sprintf("container %s exited with code %d", status.Name, terminated.ExitCode)
+		if terminated.Reason != "" {
+			reason = fmt.Sprintf("%s (%s)", reason, terminated.Reason)
+		}
+		if terminated.Message != "" {
+			reason = fmt.Sprintf("%s: %s", reason, terminated.Message)
+		}
+		return reason
+	}
+	if pod.Status.Reason != "" {
+		return fmt.Sprintf("%s: %s", pod.Status.Reason, pod.Status.Message)
+	}
+	return "unknown reason"
+}
@@ -0,0 +1,84 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func newTestJob(conditions ...batchv1.JobCondition) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "migrate",
+			Namespace: "default",
+			UID:       types.UID("migrate-uid"),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          ptr.To(int32(2)),
+			ActiveDeadlineSeconds: ptr.To(int64(300)),
+		},
+		Status: batchv1.JobStatus{Conditions: conditions},
+	}
+}
+
+func TestAnalyzeJob(t *testing.T) {
+	request := common.Request{Context: context.Background(), Namespace: "default"}
+
+	t.Run("Completed job", func(t *testing.T) {
+		k := newTestKubernetes(fake.NewSimpleClientset(newTestJob(
+			batchv1.JobCondition{Type: batchv1.JobComplete, Status: v1.ConditionTrue},
+		)), nil)
+		result, err := k.AnalyzeJob(request)
+		assert.NoError(t, err)
+		assert.Equal(t, "[]", result)
+	})
+
+	t.Run("Backoff limit exceeded with failed pod", func(t *testing.T) {
+		job := newTestJob(batchv1.JobCondition{
+			Type: batchv1.JobFailed, Status: v1.ConditionTrue,
+			Reason: batchv1.JobReasonBackoffLimitExceeded, Message: "Job has reached the specified backoff limit",
+		})
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "migrate-abcde",
+				Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "batch/v1", Kind: "Job", Name: job.Name, UID: job.UID, Controller: ptr.To(true)},
+				},
+			},
+			Status: v1.PodStatus{
+				Phase: v1.PodFailed,
+				ContainerStatuses: []v1.ContainerStatus{{
+					Name: "migrate",
+					State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+						ExitCode: 137,
+						Reason:   "OOMKilled",
+					}},
+				}},
+			},
+		}
+		k := newTestKubernetes(fake.NewSimpleClientset(job, pod), nil)
+		result, err := k.AnalyzeJob(request)
+		assert.NoError(t, err)
+		assert.Contains(t, result, "Job has reached the backoff limit of 2 retries")
+		assert.Contains(t, result, "Pod default/migrate-abcde failed: container migrate exited with code 137 (OOMKilled)")
+	})
+
+	t.Run("Deadline exceeded", func(t *testing.T) {
+		k := newTestKubernetes(fake.NewSimpleClientset(newTestJob(batchv1.JobCondition{
+			Type: batchv1.JobFailed, Status: v1.ConditionTrue,
+			Reason: batchv1.JobReasonDeadlineExceeded, Message: "Job was active longer than specified deadline",
+		})), nil)
+		result, err := k.AnalyzeJob(request)
+		assert.NoError(t, err)
+		assert.Contains(t, result, "Job was active longer than its deadline of 300s")
+	})
+}
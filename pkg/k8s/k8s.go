@@ -15,10 +15,14 @@ type Kubernetes struct {
 	config                      *rest.Config
 	clientset                   kubernetes.Interface
 	discoveryClient             discovery.DiscoveryInterface
+	cachedDiscoveryClient       discovery.CachedDiscoveryInterface
 	dynamicClient               dynamic.Interface
 	deferredDiscoveryRESTMapper *restmapper.DeferredDiscoveryRESTMapper
 	openapiSchema               *openapi_v2.Document
 	metricsClient               metricsclientset.Interface
+	policyRules                 []compiledPolicyRule
+	cache                       *informerCache
+	completions                 completionCache
 }
 
 // NewKubernetes creates a new Kubernetes client
@@ -43,12 +47,14 @@ func NewKubernetes() (*Kubernetes, error) {
 		return nil, err
 	}
 
+	cachedDiscoveryClient := memory.NewMemCacheClient(discoveryClient)
 	return &Kubernetes{
 		config:                      config,
 		clientset:                   clientset,
 		discoveryClient:             discoveryClient,
+		cachedDiscoveryClient:       cachedDiscoveryClient,
 		dynamicClient:               dynamicClient,
-		deferredDiscoveryRESTMapper: restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+		deferredDiscoveryRESTMapper: restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscoveryClient),
 		openapiSchema:               &openapi_v2.Document{},
 		metricsClient:               metricsClient,
 	}, nil
@@ -0,0 +1,137 @@
+package k8s
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// resolveKind maps the kind a user names to the resource the cluster serves it with. kind is matched case
+// insensitively against the kinds, plurals, singulars and short names the cluster serves, custom resources included,
+// and may be qualified with its group like certificates.cert-manager.io. apiVersion restricts the match to one group
+// version, without it the preferred version of each group is used.
+// A name served by several groups resolves to the core group like kubectl does, e.g. pods and not the pods of
+// metrics.k8s.io, other ambiguous names are an error listing the candidates.
+// The resources are read from the discovery cache shared with the REST mapper, it is refreshed when kind is not
+// found in it so the kinds served since, e.g. newly installed custom resources, are resolved too.
+func (k *Kubernetes) resolveKind(kind, apiVersion string) (*meta.RESTMapping, error) {
+	return k.mapKind(kind, apiVersion, true)
+}
+
+// mapKind resolves kind like resolveKind, the discovery cache is only refreshed on a miss when refresh is set.
+func (k *Kubernetes) mapKind(kind, apiVersion string, refresh bool) (*meta.RESTMapping, error) {
+	if kind == "" {
+		return nil, fmt.Errorf("kind is required")
+	}
+	name, group, qualified := strings.Cut(strings.ToLower(kind), ".")
+	var gv *schema.GroupVersion
+	if apiVersion != "" {
+		parsed, err := schema.ParseGroupVersion(apiVersion)
+		if err != nil {
+			return nil, err
+		}
+		if qualified && parsed.Group != group {
+			return nil, fmt.Errorf("kind %s does not belong to %s", kind, apiVersion)
+		}
+		gv = &parsed
+	}
+
+	candidates, discoveryErr, err := k.kindCandidates(name, group, qualified, gv)
+	if refresh && (err != nil || len(candidates) == 0) {
+		// the kind may be served since the resources were discovered, e.g. a custom resource installed since
+		k.cachedDiscoveryClient.Invalidate()
+		candidates, discoveryErr, err = k.kindCandidates(name, group, qualified, gv)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(candidates) {
+	case 0:
+		if discoveryErr != nil {
+			return nil, fmt.Errorf("the server doesn't have a resource type %q, some groups could not be discovered: %w", kind, discoveryErr)
+		}
+		return nil, fmt.Errorf("the server doesn't have a resource type %q", kind)
+	case 1:
+		return candidates[0], nil
+	}
+
+	var core []*meta.RESTMapping
+	for _, c := range candidates {
+		if c.Resource.Group == "" {
+			core = append(core, c)
+		}
+	}
+	if len(core) == 1 {
+		return core[0], nil
+	}
+	names := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		names = append(names, fmt.Sprintf("%s (%s)", c.Resource.GroupResource(), c.GroupVersionKind.GroupVersion()))
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("kind %q is ambiguous, it matches %s; qualify it with the group, e.g. %s, or pass the apiVersion",
+		kind, strings.Join(names, ", "), candidates[0].Resource.GroupResource())
+}
+
+// kindCandidates returns the resources of gv, or of the preferred version of each group, matching name, only those of
+// group when qualified. discoveryErr reports the groups that could not be discovered.
+func (k *Kubernetes) kindCandidates(name, group string, qualified bool, gv *schema.GroupVersion) (candidates []*meta.RESTMapping, discoveryErr error, err error) {
+	var lists []*metav1.APIResourceList
+	if gv != nil {
+		list, err := k.cachedDiscoveryClient.ServerResourcesForGroupVersion(gv.String())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to discover the resources of %s: %w", gv, err)
+		}
+		lists = []*metav1.APIResourceList{list}
+	} else {
+		lists, discoveryErr = k.cachedDiscoveryClient.ServerPreferredResources()
+		if discoveryErr != nil && !discovery.IsGroupDiscoveryFailedError(discoveryErr) {
+			return nil, nil, discoveryErr
+		}
+	}
+
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil || (qualified && gv.Group != group) {
+			continue
+		}
+		for _, resource := range list.APIResources {
+			// subresources such as pods/log are reachable through their resource
+			if strings.Contains(resource.Name, "/") || !matchesKind(resource, name) {
+				continue
+			}
+			candidates = append(candidates, restMappingFor(gv, resource))
+		}
+	}
+	return candidates, discoveryErr, nil
+}
+
+func matchesKind(resource metav1.APIResource, name string) bool {
+	return strings.ToLower(resource.Kind) == name ||
+		resource.Name == name ||
+		resource.SingularName == name ||
+		slices.Contains(resource.ShortNames, name)
+}
+
+func restMappingFor(gv schema.GroupVersion, resource metav1.APIResource) *meta.RESTMapping {
+	scope := meta.RESTScopeRoot
+	if resource.Namespaced {
+		scope = meta.RESTScopeNamespace
+	}
+	return &meta.RESTMapping{
+		Resource:         gv.WithResource(resource.Name),
+		GroupVersionKind: gv.WithKind(resource.Kind),
+		Scope:            scope,
+	}
+}
+
+func isNamespaced(mapping *meta.RESTMapping) bool {
+	return mapping.Scope.Name() == meta.RESTScopeNameNamespace
+}
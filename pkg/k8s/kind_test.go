@@ -0,0 +1,133 @@
+package k8s
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func newKindDiscovery() *fakeDiscovery {
+	groupVersion := func(gv string) metav1.GroupVersionForDiscovery {
+		v, _ := schema.ParseGroupVersion(gv)
+		return metav1.GroupVersionForDiscovery{GroupVersion: gv, Version: v.Version}
+	}
+	return &fakeDiscovery{
+		groupList: &metav1.APIGroupList{Groups: []metav1.APIGroup{
+			{Name: "", Versions: []metav1.GroupVersionForDiscovery{groupVersion("v1")}, PreferredVersion: groupVersion("v1")},
+			{Name: "apps", Versions: []metav1.GroupVersionForDiscovery{groupVersion("apps/v1")}, PreferredVersion: groupVersion("apps/v1")},
+			{Name: "metrics.k8s.io", Versions: []metav1.GroupVersionForDiscovery{groupVersion("metrics.k8s.io/v1beta1")}, PreferredVersion: groupVersion("metrics.k8s.io/v1beta1")},
+			{Name: "cert-manager.io", Versions: []metav1.GroupVersionForDiscovery{groupVersion("cert-manager.io/v1")}, PreferredVersion: groupVersion("cert-manager.io/v1")},
+			{Name: "networking.internal.knative.dev", Versions: []metav1.GroupVersionForDiscovery{groupVersion("networking.internal.knative.dev/v1alpha1")}, PreferredVersion: groupVersion("networking.internal.knative.dev/v1alpha1")},
+		}},
+		resourceMap: map[string]*resourceMapEntry{
+			"v1": {list: &metav1.APIResourceList{GroupVersion: "v1", APIResources: []metav1.APIResource{
+				{Name: "pods", SingularName: "pod", Namespaced: true, Kind: "Pod", ShortNames: []string{"po"}},
+				{Name: "pods/log", Namespaced: true, Kind: "Pod"},
+				{Name: "nodes", SingularName: "node", Kind: "Node", ShortNames: []string{"no"}},
+			}}},
+			"apps/v1": {list: &metav1.APIResourceList{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+				{Name: "deployments", SingularName: "deployment", Namespaced: true, Kind: "Deployment", ShortNames: []string{"deploy"}},
+			}}},
+			"metrics.k8s.io/v1beta1": {list: &metav1.APIResourceList{GroupVersion: "metrics.k8s.io/v1beta1", APIResources: []metav1.APIResource{
+				{Name: "pods", Namespaced: true, Kind: "PodMetrics"},
+			}}},
+			"cert-manager.io/v1": {list: &metav1.APIResourceList{GroupVersion: "cert-manager.io/v1", APIResources: []metav1.APIResource{
+				{Name: "certificates", SingularName: "certificate", Namespaced: true, Kind: "Certificate", ShortNames: []string{"cert", "certs"}},
+			}}},
+			"networking.internal.knative.dev/v1alpha1": {list: &metav1.APIResourceList{GroupVersion: "networking.internal.knative.dev/v1alpha1", APIResources: []metav1.APIResource{
+				{Name: "certificates", SingularName: "certificate", Namespaced: true, Kind: "Certificate", ShortNames: []string{"kcert"}},
+			}}},
+		},
+	}
+}
+
+func TestResolveKind(t *testing.T) {
+	k := &Kubernetes{}
+	useDiscovery(k, newKindDiscovery())
+
+	tests := []struct {
+		name       string
+		kind       string
+		apiVersion string
+		resource   schema.GroupVersionResource
+		gvkKind    string
+		namespaced bool
+	}{
+		{name: "kind", kind: "Deployment", resource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, gvkKind: "Deployment", namespaced: true},
+		{name: "lower case kind", kind: "deployment", resource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, gvkKind: "Deployment", namespaced: true},
+		{name: "short name", kind: "deploy", resource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, gvkKind: "Deployment", namespaced: true},
+		{name: "plural served by several groups prefers core", kind: "pods", resource: schema.GroupVersionResource{Version: "v1", Resource: "pods"}, gvkKind: "Pod", namespaced: true},
+		{name: "cluster scoped", kind: "no", resource: schema.GroupVersionResource{Version: "v1", Resource: "nodes"}, gvkKind: "Node"},
+		{name: "custom resource short name", kind: "cert", resource: schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}, gvkKind: "Certificate", namespaced: true},
+		{name: "qualified with the group", kind: "certificates.cert-manager.io", resource: schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}, gvkKind: "Certificate", namespaced: true},
+		{name: "explicit apiVersion", kind: "Certificate", apiVersion: "networking.internal.knative.dev/v1alpha1", resource: schema.GroupVersionResource{Group: "networking.internal.knative.dev", Version: "v1alpha1", Resource: "certificates"}, gvkKind: "Certificate", namespaced: true},
+		{name: "explicit apiVersion of the metrics group", kind: "pods", apiVersion: "metrics.k8s.io/v1beta1", resource: schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}, gvkKind: "PodMetrics", namespaced: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping, err := k.resolveKind(tt.kind, tt.apiVersion)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.resource, mapping.Resource)
+				assert.Equal(t, tt.gvkKind, mapping.GroupVersionKind.Kind)
+				assert.Equal(t, tt.namespaced, isNamespaced(mapping))
+			}
+		})
+	}
+
+	t.Run("Ambiguous kind lists the candidates", func(t *testing.T) {
+		_, err := k.resolveKind("certificate", "")
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), `kind "certificate" is ambiguous`)
+			assert.Contains(t, err.Error(), "certificates.cert-manager.io (cert-manager.io/v1)")
+			assert.Contains(t, err.Error(), "certificates.networking.internal.knative.dev (networking.internal.knative.dev/v1alpha1)")
+		}
+	})
+
+	t.Run("Unknown kind", func(t *testing.T) {
+		_, err := k.resolveKind("widgets", "")
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), `the server doesn't have a resource type "widgets"`)
+		}
+	})
+
+	t.Run("Group not matching the apiVersion", func(t *testing.T) {
+		_, err := k.resolveKind("certificates.cert-manager.io", "apps/v1")
+		assert.Error(t, err)
+	})
+}
+
+func TestResolveKindDiscoveryCache(t *testing.T) {
+	discovery := newKindDiscovery()
+	k := &Kubernetes{}
+	useDiscovery(k, discovery)
+
+	_, err := k.resolveKind("deploy", "")
+	assert.NoError(t, err)
+	// a cached kind is resolved without discovering the resources again
+	discovery.lock.Lock()
+	apps := discovery.resourceMap["apps/v1"]
+	discovery.resourceMap["apps/v1"] = &resourceMapEntry{err: errors.New("discovery should not be called")}
+	discovery.lock.Unlock()
+	_, err = k.resolveKind("deploy", "")
+	assert.NoError(t, err)
+
+	// a custom resource installed since the resources were discovered is resolved after a refresh
+	discovery.lock.Lock()
+	discovery.resourceMap["apps/v1"] = apps
+	discovery.groupList.Groups = append(discovery.groupList.Groups, metav1.APIGroup{
+		Name:             "example.com",
+		Versions:         []metav1.GroupVersionForDiscovery{{GroupVersion: "example.com/v1", Version: "v1"}},
+		PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "example.com/v1", Version: "v1"},
+	})
+	discovery.resourceMap["example.com/v1"] = &resourceMapEntry{list: &metav1.APIResourceList{GroupVersion: "example.com/v1", APIResources: []metav1.APIResource{
+		{Name: "widgets", SingularName: "widget", Namespaced: true, Kind: "Widget"},
+	}}}
+	discovery.lock.Unlock()
+	mapping, err := k.resolveKind("widget", "")
+	if assert.NoError(t, err) {
+		assert.Equal(t, schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}, mapping.Resource)
+	}
+}
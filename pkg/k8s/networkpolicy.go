@@ -1,7 +1,7 @@
 package k8s
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 
 	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
@@ -11,6 +11,10 @@ import (
 
 // AnalyzeNetworkPolicy analyzes the network policies and returns a list of failures.
 func (k *Kubernetes) AnalyzeNetworkPolicy(r common.Request) (string, error) {
+	return marshalResults(k.analyzeNetworkPolicy(r.Context, r))
+}
+
+func (k *Kubernetes) analyzeNetworkPolicy(ctx context.Context, r common.Request) ([]common.Result, error) {
 	kind := "NetworkPolicy"
 	apiDoc := K8sApiReference{
 		Kind: kind,
@@ -21,9 +25,9 @@ func (k *Kubernetes) AnalyzeNetworkPolicy(r common.Request) (string, error) {
 		OpenapiSchema: k.openapiSchema,
 	}
 
-	policyList, err := k.clientset.NetworkingV1().NetworkPolicies(r.Namespace).List(r.Context, metav1.ListOptions{})
+	policyList, err := k.clientset.NetworkingV1().NetworkPolicies(r.Namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	var preAnalysis = map[string]common.PreAnalysis{}
@@ -35,8 +39,11 @@ func (k *Kubernetes) AnalyzeNetworkPolicy(r common.Request) (string, error) {
 		if len(policy.Spec.PodSelector.MatchLabels) == 0 {
 			doc := apiDoc.GetApiDocV2("spec.podSelector")
 			failures = append(failures, common.Failure{
+				ID:            "NETWORKPOLICY_SELECTS_ALL_PODS",
+				Severity:      common.SeverityInfo,
 				Text:          fmt.Sprint("NetworkPolicy has empty pod selector, will select all pods"),
 				KubernetesDoc: doc,
+				FieldPath:     "spec.podSelector",
 			})
 
 			for _, policyType := range policy.Spec.PolicyTypes {
@@ -45,34 +52,46 @@ func (k *Kubernetes) AnalyzeNetworkPolicy(r common.Request) (string, error) {
 					if len(policy.Spec.Ingress) == 0 {
 						doc := apiDoc.GetApiDocV2("spec.ingress")
 						failures = append(failures, common.Failure{
+							ID:            "NETWORKPOLICY_DENY_ALL_INGRESS",
+							Severity:      common.SeverityWarning,
 							Text:          fmt.Sprint("NetworkPolicy will deny all ingress traffic"),
 							KubernetesDoc: doc,
+							FieldPath:     "spec.ingress",
+							Remediation:   &common.Remediation{Text: "Add ingress rules for the traffic the selected pods must accept, unless a default deny is intended"},
 						})
 					}
 				case "Egress":
 					if len(policy.Spec.Egress) == 0 {
 						doc := apiDoc.GetApiDocV2("spec.egress")
 						failures = append(failures, common.Failure{
+							ID:            "NETWORKPOLICY_DENY_ALL_EGRESS",
+							Severity:      common.SeverityWarning,
 							Text:          fmt.Sprint("NetworkPolicy will deny all egress traffic"),
 							KubernetesDoc: doc,
+							FieldPath:     "spec.egress",
+							Remediation:   &common.Remediation{Text: "Add egress rules for the traffic the selected pods must send, including DNS, unless a default deny is intended"},
 						})
 					}
 				}
 			}
 		} else {
-			podList, err := k.clientset.CoreV1().Pods(policy.Namespace).List(r.Context, metav1.ListOptions{
+			podList, err := k.clientset.CoreV1().Pods(policy.Namespace).List(ctx, metav1.ListOptions{
 				LabelSelector: metav1.FormatLabelSelector(&metav1.LabelSelector{
 					MatchLabels: policy.Spec.PodSelector.MatchLabels,
 				}),
 			})
 			if err != nil {
-				return "", err
+				return nil, err
 			}
 			if len(podList.Items) == 0 {
 				doc := apiDoc.GetApiDocV2("spec.podSelector")
 				failures = append(failures, common.Failure{
+					ID:            "NETWORKPOLICY_NO_MATCHING_PODS",
+					Severity:      common.SeverityInfo,
 					Text:          fmt.Sprint("NetworkPolicy has no matching pods"),
 					KubernetesDoc: doc,
+					FieldPath:     "spec.podSelector",
+					Remediation:   &common.Remediation{Text: "Fix the pod selector or delete the unused policy"},
 				})
 			}
 		}
@@ -84,19 +103,5 @@ func (k *Kubernetes) AnalyzeNetworkPolicy(r common.Request) (string, error) {
 		}
 	}
 
-	results := make([]common.Result, 0)
-	for key, value := range preAnalysis {
-		result := common.Result{
-			Kind:  kind,
-			Name:  key,
-			Error: value.FailureDetails,
-		}
-		results = append(results, result)
-	}
-
-	jsonData, err := json.Marshal(results)
-	if err != nil {
-		return "", err
-	}
-	return string(jsonData), nil
+	return k.preAnalysisResults(ctx, kind, preAnalysis, nil), nil
 }
@@ -2,28 +2,32 @@ package k8s
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
-	"github.com/wenhuwang/mcp-k8s-eye/pkg/utils"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func (k *Kubernetes) AnalyzeNode(ctx context.Context, name string) (string, error) {
+	return marshalResults(k.analyzeNode(ctx, common.Request{Name: name}))
+}
+
+func (k *Kubernetes) analyzeNode(ctx context.Context, r common.Request) ([]common.Result, error) {
 	kind := "Node"
 	nodes := make([]v1.Node, 0)
-	if name == "" {
-		nodeList, err := k.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if r.Name == "" {
+		nodeList, err := k.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{
+			LabelSelector: r.LabelSelector,
+		})
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 		nodes = nodeList.Items
 	} else {
-		node, err := k.clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+		node, err := k.clientset.CoreV1().Nodes().Get(ctx, r.Name, metav1.GetOptions{})
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 		nodes = append(nodes, *node)
 	}
@@ -31,16 +35,16 @@ func (k *Kubernetes) AnalyzeNode(ctx context.Context, name string) (string, erro
 
 	for _, node := range nodes {
 		var failures []common.Failure
-		for _, nodeCondition := range node.Status.Conditions {
+		for i, nodeCondition := range node.Status.Conditions {
 			switch nodeCondition.Type {
 			case v1.NodeReady:
 				if nodeCondition.Status == v1.ConditionTrue {
 					break
 				}
-				failures = addNodeConditionFailure(failures, node.Name, nodeCondition)
+				failures = addNodeConditionFailure(failures, node.Name, i, nodeCondition)
 			default:
 				if nodeCondition.Status != v1.ConditionFalse {
-					failures = addNodeConditionFailure(failures, node.Name, nodeCondition)
+					failures = addNodeConditionFailure(failures, node.Name, i, nodeCondition)
 				}
 			}
 		}
@@ -53,30 +57,33 @@ func (k *Kubernetes) AnalyzeNode(ctx context.Context, name string) (string, erro
 		}
 	}
 
-	results := make([]common.Result, 0)
-	for key, value := range preAnalysis {
-		var result = common.Result{
-			Kind:  kind,
-			Name:  key,
-			Error: value.FailureDetails,
-		}
-		parent, found := utils.GetParent(k.clientset, value.Node.ObjectMeta)
-		if found {
-			result.ParentObject = parent
-		}
-		results = append(results, result)
-	}
+	return k.preAnalysisResults(ctx, kind, preAnalysis, func(p common.PreAnalysis) metav1.ObjectMeta { return p.Node.ObjectMeta }), nil
+}
 
-	jsonData, err := json.Marshal(results)
-	if err != nil {
-		return "", err
-	}
-	return string(jsonData), nil
+// nodeConditionChecks maps the well known node conditions to the ID, severity and remediation of their failure.
+var nodeConditionChecks = map[v1.NodeConditionType]struct {
+	id          string
+	severity    string
+	remediation string
+}{
+	v1.NodeReady:              {"NODE_NOT_READY", common.SeverityCritical, "Check the kubelet and container runtime on the node, drain it if it does not recover"},
+	v1.NodeMemoryPressure:     {"NODE_MEMORY_PRESSURE", common.SeverityWarning, "Lower the memory requests scheduled on the node or add memory, the kubelet evicts pods under pressure"},
+	v1.NodeDiskPressure:       {"NODE_DISK_PRESSURE", common.SeverityWarning, "Free disk space on the node, e.g. unused images and container logs, or grow the disk"},
+	v1.NodePIDPressure:        {"NODE_PID_PRESSURE", common.SeverityWarning, "Find the pods creating too many processes or raise the pid limit of the node"},
+	v1.NodeNetworkUnavailable: {"NODE_NETWORK_UNAVAILABLE", common.SeverityCritical, "Check the network plugin pods running on the node"},
 }
 
-func addNodeConditionFailure(failures []common.Failure, nodeName string, nodeCondition v1.NodeCondition) []common.Failure {
-	failures = append(failures, common.Failure{
-		Text: fmt.Sprintf("%s condition type %s is %s, reason %s: %s", nodeName, nodeCondition.Type, nodeCondition.Status, nodeCondition.Reason, nodeCondition.Message),
-	})
-	return failures
+func addNodeConditionFailure(failures []common.Failure, nodeName string, index int, nodeCondition v1.NodeCondition) []common.Failure {
+	failure := common.Failure{
+		ID:        "NODE_CONDITION",
+		Severity:  common.SeverityWarning,
+		Text:      fmt.Sprintf("%s condition type %s is %s, reason %s: %s", nodeName, nodeCondition.Type, nodeCondition.Status, nodeCondition.Reason, nodeCondition.Message),
+		FieldPath: fmt.Sprintf("status.conditions[%d]", index),
+	}
+	if check, ok := nodeConditionChecks[nodeCondition.Type]; ok {
+		failure.ID = check.id
+		failure.Severity = check.severity
+		failure.Remediation = &common.Remediation{Text: check.remediation}
+	}
+	return append(failures, failure)
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
@@ -106,3 +107,17 @@ func TestAnalyzeNodeWithCondition(t *testing.T) {
 	count := strings.Count(result, "node1")
 	assert.Equal(t, 11, count)
 }
+
+func TestAnalyzeNodeWithLabelSelector(t *testing.T) {
+	notReady := v1.NodeStatus{Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionFalse}}}
+	clientset := fake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker1", Labels: map[string]string{"role": "worker"}}, Status: notReady},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "control1", Labels: map[string]string{"role": "control-plane"}}, Status: notReady},
+	)
+	k := newTestKubernetes(clientset, nil)
+	results, err := k.analyzeNode(context.Background(), common.Request{LabelSelector: "role=worker"})
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "worker1", results[0].Name)
+	}
+}
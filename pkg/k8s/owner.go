@@ -0,0 +1,136 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// maxOwnerDepth bounds an owner chain so a reference cycle cannot loop forever
+const maxOwnerDepth = 10
+
+// ownerResolver follows owner references of any kind, custom resources included, through the dynamic client and
+// so through the informer cache when it is enabled. The owners it fetched are kept, so results sharing owners, such
+// as the pods of one ReplicaSet, cost a single lookup per owner.
+type ownerResolver struct {
+	k      *Kubernetes
+	owners map[ownerKey]resolvedOwner
+}
+
+type ownerKey struct {
+	apiVersion, kind, namespace, name string
+	uid                               types.UID
+}
+
+type resolvedOwner struct {
+	ref common.ObjectReference
+	// found is false when the owner could not be fetched, it then ends the chain
+	found           bool
+	ownerReferences []metav1.OwnerReference
+}
+
+func (k *Kubernetes) newOwnerResolver() *ownerResolver {
+	return &ownerResolver{k: k, owners: map[ownerKey]resolvedOwner{}}
+}
+
+// setOwners sets the owner chain of a result and its parent object to the root owner.
+func (o *ownerResolver) setOwners(ctx context.Context, result *common.Result, namespace string, ownerReferences []metav1.OwnerReference) {
+	result.OwnerChain = o.chain(ctx, namespace, ownerReferences)
+	if n := len(result.OwnerChain); n > 0 {
+		result.ParentObject = fmt.Sprintf("%s/%s", result.OwnerChain[n-1].Kind, result.OwnerChain[n-1].Name)
+	}
+}
+
+// chain returns the owners of an object from its controller up to the root owner. An owner that cannot be fetched,
+// because it is gone or its kind is not served, is still listed but ends the chain.
+func (o *ownerResolver) chain(ctx context.Context, namespace string, ownerReferences []metav1.OwnerReference) []common.ObjectReference {
+	var chain []common.ObjectReference
+	seen := map[ownerKey]bool{}
+	for len(chain) < maxOwnerDepth {
+		ref, ok := controllerReference(ownerReferences)
+		if !ok {
+			break
+		}
+		key := ownerKey{apiVersion: ref.APIVersion, kind: ref.Kind, namespace: namespace, name: ref.Name, uid: ref.UID}
+		if seen[key] {
+			break
+		}
+		seen[key] = true
+
+		owner := o.lookup(ctx, key)
+		chain = append(chain, owner.ref)
+		if !owner.found {
+			break
+		}
+		namespace, ownerReferences = owner.ref.Namespace, owner.ownerReferences
+	}
+	return chain
+}
+
+// controllerReference returns the reference to the managing controller, or the first owner when none is marked as
+// the controller.
+func controllerReference(ownerReferences []metav1.OwnerReference) (metav1.OwnerReference, bool) {
+	for _, ref := range ownerReferences {
+		if ref.Controller != nil && *ref.Controller {
+			return ref, true
+		}
+	}
+	if len(ownerReferences) > 0 {
+		return ownerReferences[0], true
+	}
+	return metav1.OwnerReference{}, false
+}
+
+func (o *ownerResolver) lookup(ctx context.Context, key ownerKey) resolvedOwner {
+	if owner, ok := o.owners[key]; ok {
+		return owner
+	}
+	owner := resolvedOwner{ref: common.ObjectReference{
+		APIVersion: key.apiVersion,
+		Kind:       key.kind,
+		Namespace:  key.namespace,
+		Name:       key.name,
+	}}
+	if obj, err := o.fetch(ctx, key); err == nil {
+		owner.found = true
+		owner.ref.Namespace = obj.GetNamespace()
+		owner.ownerReferences = obj.GetOwnerReferences()
+	}
+	o.owners[key] = owner
+	return owner
+}
+
+func (o *ownerResolver) fetch(ctx context.Context, key ownerKey) (*unstructured.Unstructured, error) {
+	if o.k.dynamicClient == nil {
+		return nil, fmt.Errorf("no dynamic client")
+	}
+	gv, err := schema.ParseGroupVersion(key.apiVersion)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := o.k.deferredDiscoveryRESTMapper.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: key.kind}, gv.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj *unstructured.Unstructured
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		obj, err = o.k.dynamicClient.Resource(mapping.Resource).Namespace(key.namespace).Get(ctx, key.name, metav1.GetOptions{})
+	} else {
+		obj, err = o.k.dynamicClient.Resource(mapping.Resource).Get(ctx, key.name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	// an owner recreated under the same name is not the owner the reference points to
+	if key.uid != "" && obj.GetUID() != key.uid {
+		return nil, fmt.Errorf("%s %s has uid %s, the owner reference expects %s", key.kind, key.name, obj.GetUID(), key.uid)
+	}
+	return obj, nil
+}
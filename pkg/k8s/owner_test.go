@@ -0,0 +1,103 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery/cached/memory"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/utils/ptr"
+)
+
+func newOwnedObject(apiVersion, kind, name, uid string, owner *metav1.OwnerReference) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetUID(types.UID(uid))
+	if owner != nil {
+		obj.SetOwnerReferences([]metav1.OwnerReference{*owner})
+	}
+	return obj
+}
+
+func TestOwnerChain(t *testing.T) {
+	discovery := &fakeDiscovery{
+		groupList: &metav1.APIGroupList{Groups: []metav1.APIGroup{
+			{Name: "apps", Versions: []metav1.GroupVersionForDiscovery{{GroupVersion: "apps/v1", Version: "v1"}}},
+			{Name: "argoproj.io", Versions: []metav1.GroupVersionForDiscovery{{GroupVersion: "argoproj.io/v1alpha1", Version: "v1alpha1"}}},
+		}},
+		resourceMap: map[string]*resourceMapEntry{
+			"apps/v1": {list: &metav1.APIResourceList{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+				{Name: "replicasets", Namespaced: true, Kind: "ReplicaSet"},
+				{Name: "deployments", Namespaced: true, Kind: "Deployment"},
+			}}},
+			"argoproj.io/v1alpha1": {list: &metav1.APIResourceList{GroupVersion: "argoproj.io/v1alpha1", APIResources: []metav1.APIResource{
+				{Name: "rollouts", Namespaced: true, Kind: "Rollout"},
+			}}},
+		},
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+		newOwnedObject("argoproj.io/v1alpha1", "Rollout", "api", "rollout-uid", nil),
+		newOwnedObject("apps/v1", "Deployment", "api", "deploy-uid",
+			&metav1.OwnerReference{APIVersion: "argoproj.io/v1alpha1", Kind: "Rollout", Name: "api", UID: "rollout-uid", Controller: ptr.To(true)}),
+		newOwnedObject("apps/v1", "ReplicaSet", "api-1", "rs-uid",
+			&metav1.OwnerReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "api", UID: "deploy-uid", Controller: ptr.To(true)}),
+	)
+	cachedDiscovery := memory.NewMemCacheClient(discovery)
+	k := &Kubernetes{
+		clientset:                   kubernetesfake.NewSimpleClientset(),
+		dynamicClient:               dynamicClient,
+		discoveryClient:             discovery,
+		cachedDiscoveryClient:       cachedDiscovery,
+		deferredDiscoveryRESTMapper: restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscovery),
+	}
+
+	t.Run("Resolve the full chain of any kind", func(t *testing.T) {
+		var result common.Result
+		k.newOwnerResolver().setOwners(context.Background(), &result, "default", []metav1.OwnerReference{
+			{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "api-1", UID: "rs-uid", Controller: ptr.To(true)},
+		})
+
+		assert.Equal(t, []common.ObjectReference{
+			{APIVersion: "apps/v1", Kind: "ReplicaSet", Namespace: "default", Name: "api-1"},
+			{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "default", Name: "api"},
+			{APIVersion: "argoproj.io/v1alpha1", Kind: "Rollout", Namespace: "default", Name: "api"},
+		}, result.OwnerChain)
+		assert.Equal(t, "Rollout/api", result.ParentObject)
+	})
+
+	t.Run("Owners are fetched once per resolver", func(t *testing.T) {
+		dynamicClient.ClearActions()
+		owners := k.newOwnerResolver()
+		for i := 0; i < 3; i++ {
+			var result common.Result
+			owners.setOwners(context.Background(), &result, "default", []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "api-1", UID: "rs-uid"},
+			})
+			assert.Len(t, result.OwnerChain, 3)
+		}
+		assert.Len(t, dynamicClient.Actions(), 3, "each owner should be fetched once")
+	})
+
+	t.Run("A missing owner ends the chain", func(t *testing.T) {
+		var result common.Result
+		k.newOwnerResolver().setOwners(context.Background(), &result, "default", []metav1.OwnerReference{
+			{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "api-1", UID: "recreated-uid"},
+		})
+
+		assert.Equal(t, []common.ObjectReference{
+			{APIVersion: "apps/v1", Kind: "ReplicaSet", Namespace: "default", Name: "api-1"},
+		}, result.OwnerChain)
+		assert.Equal(t, "ReplicaSet/api-1", result.ParentObject)
+	})
+}
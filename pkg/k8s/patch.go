@@ -0,0 +1,196 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/utils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+var patchTypes = map[string]types.PatchType{
+	common.PatchTypeJSON:      types.JSONPatchType,
+	common.PatchTypeMerge:     types.MergePatchType,
+	common.PatchTypeStrategic: types.StrategicMergePatchType,
+	common.PatchTypeApply:     types.ApplyPatchType,
+}
+
+// ResourcePatch patches an object of any kind and returns a diff of the object before and after the patch. With
+// DryRun the patch is only validated and applied by the API server, the diff previews the change.
+// An apply patch creates the object when it does not exist.
+func (k *Kubernetes) ResourcePatch(r common.Request, opts common.PatchOptions) (string, error) {
+	patchType, ok := patchTypes[opts.Type]
+	if !ok {
+		return "", fmt.Errorf("unknown patch type %s, expected %s, %s, %s or %s", opts.Type,
+			common.PatchTypeJSON, common.PatchTypeMerge, common.PatchTypeStrategic, common.PatchTypeApply)
+	}
+	// JSON is valid YAML, so both are accepted for every patch type
+	data, err := yaml.YAMLToJSON([]byte(opts.Patch))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the patch: %w", err)
+	}
+	mapping, err := k.resolveKind(r.Kind, r.APIVersion)
+	if err != nil {
+		return "", err
+	}
+	namespace := ""
+	if isNamespaced(mapping) {
+		namespace = utils.NamespaceOrDefault(r.Namespace)
+	}
+
+	// the object is read from the API server, the diff shows the change from its current state
+	before, err := k.apiDynamicClient().Resource(mapping.Resource).Namespace(namespace).Get(r.Context, r.Name, metav1.GetOptions{})
+	if err != nil && !(apierrors.IsNotFound(err) && patchType == types.ApplyPatchType) {
+		return "", err
+	}
+	return k.patch(r.Context, mapping, namespace, r.Name, before, patchType, data, opts.DryRun, opts.Force)
+}
+
+// ResourceLabel adds, changes or removes labels of an object of any kind.
+func (k *Kubernetes) ResourceLabel(r common.Request, opts common.MetadataOptions) (string, error) {
+	return k.patchMetadata(r, "labels", opts)
+}
+
+// ResourceAnnotate adds, changes or removes annotations of an object of any kind.
+func (k *Kubernetes) ResourceAnnotate(r common.Request, opts common.MetadataOptions) (string, error) {
+	return k.patchMetadata(r, "annotations", opts)
+}
+
+// patchMetadata changes the labels or annotations with a merge patch. The patch carries the resourceVersion the
+// existing values were checked against, so a key set concurrently is not overwritten without Overwrite.
+func (k *Kubernetes) patchMetadata(r common.Request, field string, opts common.MetadataOptions) (string, error) {
+	if len(opts.Set) == 0 && len(opts.Remove) == 0 {
+		return "", fmt.Errorf("no %s to set or remove", field)
+	}
+	mapping, err := k.resolveKind(r.Kind, r.APIVersion)
+	if err != nil {
+		return "", err
+	}
+	namespace := ""
+	if isNamespaced(mapping) {
+		namespace = utils.NamespaceOrDefault(r.Namespace)
+	}
+
+	// the resourceVersion of a cached copy may be stale and make the patch conflict, the object is read from the API
+	// server
+	before, err := k.apiDynamicClient().Resource(mapping.Resource).Namespace(namespace).Get(r.Context, r.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	existing, _, err := unstructured.NestedStringMap(before.Object, "metadata", field)
+	if err != nil {
+		return "", err
+	}
+
+	changes := map[string]interface{}{}
+	keys := make([]string, 0, len(opts.Set))
+	for key := range opts.Set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := opts.Set[key]
+		if current, ok := existing[key]; ok && current != value && !opts.Overwrite {
+			return "", fmt.Errorf("%s %s already has the value %q, set overwrite to change it", strings.TrimSuffix(field, "s"), key, current)
+		}
+		changes[key] = value
+	}
+	for _, key := range opts.Remove {
+		if _, ok := opts.Set[key]; ok {
+			return "", fmt.Errorf("%s %s is both set and removed", strings.TrimSuffix(field, "s"), key)
+		}
+		if _, ok := existing[key]; ok {
+			changes[key] = nil
+		}
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			field:             changes,
+			"resourceVersion": before.GetResourceVersion(),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return k.patch(r.Context, mapping, namespace, r.Name, before, types.MergePatchType, patch, opts.DryRun, false)
+}
+
+func (k *Kubernetes) patch(ctx context.Context, mapping *meta.RESTMapping, namespace, name string, before *unstructured.Unstructured,
+	patchType types.PatchType, data []byte, dryRun, force bool) (string, error) {
+	patchOptions := metav1.PatchOptions{FieldManager: common.ProjectName}
+	if dryRun {
+		patchOptions.DryRun = []string{metav1.DryRunAll}
+	}
+	if patchType == types.ApplyPatchType {
+		patchOptions.Force = &force
+	}
+	after, err := k.dynamicClient.Resource(mapping.Resource).Namespace(namespace).Patch(ctx, name, patchType, data, patchOptions)
+	if apierrors.IsUnsupportedMediaType(err) && patchType == types.StrategicMergePatchType {
+		return "", fmt.Errorf("%s does not support strategic merge patches, custom resources only support json, merge and apply patches: %w", mapping.GroupVersionKind.Kind, err)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	object := name
+	if namespace != "" {
+		object = namespace + "/" + name
+	}
+	msg := fmt.Sprintf("%s %s patched", mapping.GroupVersionKind.Kind, object)
+	if dryRun {
+		msg += " (dry run)"
+	}
+	diff, err := objectDiff(before, after)
+	if err != nil {
+		return "", err
+	}
+	if diff == "" {
+		return msg + ", no changes", nil
+	}
+	return fmt.Sprintf("%s:\n%s", msg, diff), nil
+}
+
+// objectDiff returns a unified diff of two versions of an object, before is nil when the object was created. The
+// managed fields and the resourceVersion change on every write and are left out.
+func objectDiff(before, after *unstructured.Unstructured) (string, error) {
+	beforeYAML, err := diffYAML(before)
+	if err != nil {
+		return "", err
+	}
+	afterYAML, err := diffYAML(after)
+	if err != nil {
+		return "", err
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(beforeYAML),
+		B:        difflib.SplitLines(afterYAML),
+		FromFile: "current",
+		ToFile:   "patched",
+		Context:  3,
+	})
+}
+
+func diffYAML(obj *unstructured.Unstructured) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+	obj = obj.DeepCopy()
+	unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
+	unstructured.RemoveNestedField(obj.Object, "metadata", "resourceVersion")
+	data, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
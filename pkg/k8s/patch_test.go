@@ -0,0 +1,138 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newPatchDynamicClient() *dynamicfake.FakeDynamicClient {
+	deploy := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"namespace": "default",
+				"name":      "web",
+				"labels":    map[string]interface{}{"app": "web", "tier": "frontend"},
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(2),
+			},
+		},
+	}
+	return dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), deploy)
+}
+
+func getPatchDeployment(t *testing.T, client *dynamicfake.FakeDynamicClient) *unstructured.Unstructured {
+	gvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	obj, err := client.Resource(gvr).Namespace("default").Get(context.Background(), "web", metav1.GetOptions{})
+	assert.NoError(t, err)
+	return obj
+}
+
+func TestResourcePatch(t *testing.T) {
+	r := common.Request{Context: context.Background(), Kind: "deploy", Namespace: "default", Name: "web"}
+
+	t.Run("Merge patch in YAML", func(t *testing.T) {
+		dynamicClient := newPatchDynamicClient()
+		k := newTestKubernetes(fake.NewSimpleClientset(), dynamicClient)
+		res, err := k.ResourcePatch(r, common.PatchOptions{Type: common.PatchTypeMerge, Patch: "spec:\n  replicas: 3\n"})
+		assert.NoError(t, err)
+		assert.Contains(t, res, "Deployment default/web patched:")
+		assert.Contains(t, res, "-  replicas: 2\n+  replicas: 3")
+		replicas, _, _ := unstructured.NestedInt64(getPatchDeployment(t, dynamicClient).Object, "spec", "replicas")
+		assert.Equal(t, int64(3), replicas)
+	})
+
+	t.Run("JSON patch", func(t *testing.T) {
+		dynamicClient := newPatchDynamicClient()
+		k := newTestKubernetes(fake.NewSimpleClientset(), dynamicClient)
+		res, err := k.ResourcePatch(r, common.PatchOptions{Type: common.PatchTypeJSON, Patch: `[{"op": "remove", "path": "/metadata/labels/tier"}]`})
+		assert.NoError(t, err)
+		assert.Contains(t, res, "-    tier: frontend")
+		assert.NotContains(t, getPatchDeployment(t, dynamicClient).GetLabels(), "tier")
+	})
+
+	t.Run("Dry run", func(t *testing.T) {
+		k := newTestKubernetes(fake.NewSimpleClientset(), newPatchDynamicClient())
+		res, err := k.ResourcePatch(r, common.PatchOptions{Type: common.PatchTypeMerge, Patch: `{"spec": {"replicas": 3}}`, DryRun: true})
+		assert.NoError(t, err)
+		assert.Contains(t, res, "Deployment default/web patched (dry run):")
+		assert.Contains(t, res, "+  replicas: 3")
+	})
+
+	t.Run("Patch without changes", func(t *testing.T) {
+		k := newTestKubernetes(fake.NewSimpleClientset(), newPatchDynamicClient())
+		res, err := k.ResourcePatch(r, common.PatchOptions{Type: common.PatchTypeMerge, Patch: `{"spec": {"replicas": 2}}`})
+		assert.NoError(t, err)
+		assert.Equal(t, "Deployment default/web patched, no changes", res)
+	})
+
+	t.Run("Unknown patch type", func(t *testing.T) {
+		k := newTestKubernetes(fake.NewSimpleClientset(), newPatchDynamicClient())
+		_, err := k.ResourcePatch(r, common.PatchOptions{Type: "replace", Patch: `{}`})
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "unknown patch type replace")
+		}
+	})
+}
+
+func TestResourceLabel(t *testing.T) {
+	r := common.Request{Context: context.Background(), Kind: "Deployment", Namespace: "default", Name: "web"}
+
+	t.Run("Add and remove labels", func(t *testing.T) {
+		dynamicClient := newPatchDynamicClient()
+		k := newTestKubernetes(fake.NewSimpleClientset(), dynamicClient)
+		res, err := k.ResourceLabel(r, common.MetadataOptions{Set: map[string]string{"team": "platform"}, Remove: []string{"tier", "missing"}})
+		assert.NoError(t, err)
+		assert.Contains(t, res, "+    team: platform")
+		assert.Contains(t, res, "-    tier: frontend")
+		assert.Equal(t, map[string]string{"app": "web", "team": "platform"}, getPatchDeployment(t, dynamicClient).GetLabels())
+	})
+
+	t.Run("Existing label needs overwrite", func(t *testing.T) {
+		dynamicClient := newPatchDynamicClient()
+		k := newTestKubernetes(fake.NewSimpleClientset(), dynamicClient)
+		_, err := k.ResourceLabel(r, common.MetadataOptions{Set: map[string]string{"tier": "backend"}})
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), `label tier already has the value "frontend", set overwrite to change it`)
+		}
+
+		_, err = k.ResourceLabel(r, common.MetadataOptions{Set: map[string]string{"tier": "backend"}, Overwrite: true})
+		assert.NoError(t, err)
+		assert.Equal(t, "backend", getPatchDeployment(t, dynamicClient).GetLabels()["tier"])
+	})
+
+	t.Run("Reads the object from the API server with the cache enabled", func(t *testing.T) {
+		dynamicClient := newPatchDynamicClient()
+		k := newTestKubernetes(fake.NewSimpleClientset(), dynamicClient)
+		k.EnableCache()
+		_, err := k.ResourceLabel(r, common.MetadataOptions{Set: map[string]string{"team": "platform"}})
+		assert.NoError(t, err)
+		var gets int
+		for _, action := range dynamicClient.Actions() {
+			if action.GetVerb() == "get" && action.GetResource().Resource == "deployments" {
+				gets++
+			}
+		}
+		assert.Equal(t, 1, gets)
+		assert.Empty(t, k.CacheStatus(), "no informer should be started")
+	})
+
+	t.Run("Annotate", func(t *testing.T) {
+		dynamicClient := newPatchDynamicClient()
+		k := newTestKubernetes(fake.NewSimpleClientset(), dynamicClient)
+		_, err := k.ResourceAnnotate(r, common.MetadataOptions{Set: map[string]string{"note": "owned by team a, see runbook"}})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"note": "owned by team a, see runbook"}, getPatchDeployment(t, dynamicClient).GetAnnotations())
+	})
+}
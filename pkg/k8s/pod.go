@@ -3,8 +3,8 @@ package k8s
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
@@ -34,6 +34,22 @@ func (k *Kubernetes) PodLogs(ctx context.Context, namespace, name string) (strin
 
 // PodExec executes a command in a pod and returns the output.
 func (k *Kubernetes) PodExec(ctx context.Context, namespace, name, command string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	err := k.execStream(ctx, namespace, name, "", strings.Split(command, " "), nil, &stdout, &stderr)
+	if err != nil {
+		return "", err
+	}
+
+	if stderr.Len() > 0 {
+		return "", fmt.Errorf("%s", stderr.String())
+	}
+
+	return stdout.String(), nil
+}
+
+// execStream executes a command in a pod container and streams its stdin, stdout and stderr.
+// An empty container selects the default container of the pod.
+func (k *Kubernetes) execStream(ctx context.Context, namespace, name, container string, command []string, stdin io.Reader, stdout, stderr io.Writer) error {
 	req := k.clientset.CoreV1().RESTClient().Post().
 		Resource("pods").
 		Name(name).
@@ -41,66 +57,41 @@ func (k *Kubernetes) PodExec(ctx context.Context, namespace, name, command strin
 		SubResource("exec")
 
 	req.VersionedParams(&v1.PodExecOptions{
-		Command: strings.Split(command, " "),
-		Stdin:   false,
-		Stdout:  true,
-		Stderr:  true,
-		TTY:     false,
+		Container: container,
+		Command:   command,
+		Stdin:     stdin != nil,
+		Stdout:    stdout != nil,
+		Stderr:    stderr != nil,
+		TTY:       false,
 	}, metav1.ParameterCodec)
 
 	exec, err := remotecommand.NewSPDYExecutor(k.config, "POST", req.URL())
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	var stdout, stderr bytes.Buffer
-	err = exec.StreamWithContext(ctx, remotecommand.StreamOptions{
-		Stdout: &stdout,
-		Stderr: &stderr,
+	return exec.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: stderr,
 	})
-	if err != nil {
-		return "", err
-	}
-
-	if stderr.Len() > 0 {
-		return "", fmt.Errorf(stderr.String())
-	}
-
-	return stdout.String(), nil
 }
 
 // AnalyzePods analyzes the pods and returns a list of failures.
 func (k *Kubernetes) AnalyzePod(ctx context.Context, namespace string) (string, error) {
-	podList, err := k.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	return marshalResults(k.analyzePod(ctx, common.Request{Namespace: namespace}))
+}
+
+func (k *Kubernetes) analyzePod(ctx context.Context, r common.Request) ([]common.Result, error) {
+	podList, err := k.clientset.CoreV1().Pods(r.Namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	var preAnalysis = map[string]common.PreAnalysis{}
 
 	for _, pod := range podList.Items {
-		var failures []common.Failure
-
-		// Check for pending pods
-		if pod.Status.Phase == "Pending" {
-			// Check through container status to check for crashes
-			for _, containerStatus := range pod.Status.Conditions {
-				if containerStatus.Type == v1.PodScheduled && containerStatus.Reason == "Unschedulable" {
-					if containerStatus.Message != "" {
-						failures = append(failures, common.Failure{
-							Text: containerStatus.Message,
-						})
-					}
-				}
-			}
-		}
-
-		// Check for errors in the init containers.
-		failures = append(failures, k.analyzeContainerStatusFailures(pod.Status.InitContainerStatuses, pod.Name, pod.Namespace, string(pod.Status.Phase))...)
-
-		// Check for errors in containers.
-		failures = append(failures, k.analyzeContainerStatusFailures(pod.Status.ContainerStatuses, pod.Name, pod.Namespace, string(pod.Status.Phase))...)
-
+		failures := k.analyzePodFailures(ctx, pod)
 		if len(failures) > 0 {
 			preAnalysis[fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)] = common.PreAnalysis{
 				Pod:            pod,
@@ -109,67 +100,130 @@ func (k *Kubernetes) AnalyzePod(ctx context.Context, namespace string) (string,
 		}
 	}
 
-	results := make([]common.Result, 0)
-	for key, value := range preAnalysis {
-		result := common.Result{
-			Kind:  "Pod",
-			Name:  key,
-			Error: value.FailureDetails,
-		}
-		parent, found := utils.GetParent(k.clientset, value.Pod.ObjectMeta)
-		if found {
-			result.ParentObject = parent
+	return k.preAnalysisResults(ctx, "Pod", preAnalysis, func(p common.PreAnalysis) metav1.ObjectMeta { return p.Pod.ObjectMeta }), nil
+}
+
+// analyzePodFailures returns the scheduling and container failures of a pod.
+func (k *Kubernetes) analyzePodFailures(ctx context.Context, pod v1.Pod) []common.Failure {
+	var failures []common.Failure
+
+	// Check for pending pods
+	if pod.Status.Phase == "Pending" {
+		// Check through container status to check for crashes
+		for i, containerStatus := range pod.Status.Conditions {
+			if containerStatus.Type == v1.PodScheduled && containerStatus.Reason == "Unschedulable" {
+				if containerStatus.Message != "" {
+					failures = append(failures, common.Failure{
+						ID:        "POD_UNSCHEDULABLE",
+						Severity:  common.SeverityCritical,
+						Text:      containerStatus.Message,
+						FieldPath: fmt.Sprintf("status.conditions[%d]", i),
+						Remediation: &common.Remediation{
+							Text: "Free or add node capacity, or relax the resource requests, node selector, affinity and tolerations of the pod so a node matches",
+						},
+					})
+				}
+			}
 		}
-		results = append(results, result)
 	}
 
-	jsonData, err := json.Marshal(results)
-	if err != nil {
-		return "", err
+	// Check for errors in the init containers.
+	failures = append(failures, k.analyzeContainerStatusFailures(ctx, pod.Status.InitContainerStatuses, "status.initContainerStatuses", pod.Name, pod.Namespace, string(pod.Status.Phase))...)
+
+	// Check for errors in containers.
+	failures = append(failures, k.analyzeContainerStatusFailures(ctx, pod.Status.ContainerStatuses, "status.containerStatuses", pod.Name, pod.Namespace, string(pod.Status.Phase))...)
+
+	return failures
+}
+
+// ownedPodFailures rolls the failures of a pod up to its owner, the text names the pod and the field path is
+// dropped because it points into the pod.
+func ownedPodFailures(pod v1.Pod, failures []common.Failure) []common.Failure {
+	for i := range failures {
+		failures[i].Text = fmt.Sprintf("Pod %s/%s: %s", pod.Namespace, pod.Name, failures[i].Text)
+		failures[i].FieldPath = ""
+		failures[i].RelatedObjects = append(failures[i].RelatedObjects, common.ObjectReference{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name})
 	}
-	return string(jsonData), nil
+	return failures
 }
 
-// analyzeContainerStatusFailures analyzes the container statuses and returns a list of failures.
-func (k *Kubernetes) analyzeContainerStatusFailures(statuses []v1.ContainerStatus, name string, namespace string, statusPhase string) []common.Failure {
+// analyzeContainerStatusFailures analyzes the container statuses found at field and returns a list of failures.
+func (k *Kubernetes) analyzeContainerStatusFailures(ctx context.Context, statuses []v1.ContainerStatus, field string, name string, namespace string, statusPhase string) []common.Failure {
 	var failures []common.Failure
 
 	// Check through container status to check for crashes or unready
-	for _, containerStatus := range statuses {
+	for i, containerStatus := range statuses {
+		fieldPath := fmt.Sprintf("%s[%d]", field, i)
 		if containerStatus.State.Waiting != nil {
 			if containerStatus.State.Waiting.Reason == "ContainerCreating" && statusPhase == "Pending" {
 				// This represents a container that is still being created or blocked due to conditions such as OOMKilled
 				// parse the event log and append details
-				evt, err := utils.FetchLatestEvent(k.clientset, namespace, name)
+				evt, err := utils.FetchLatestEvent(ctx, k.clientset, namespace, name)
 				if err != nil || evt == nil {
 					continue
 				}
 				if utils.IsEvtErrorReason(evt.Reason) && evt.Message != "" {
 					failures = append(failures, common.Failure{
-						Text: evt.Message,
+						ID:        "POD_CONTAINER_CREATE_FAILED",
+						Severity:  common.SeverityCritical,
+						Text:      evt.Message,
+						FieldPath: fieldPath + ".state.waiting",
+						Remediation: &common.Remediation{
+							Text: fmt.Sprintf("Fix the %s reported by the event, usually a missing volume, secret or configmap, or a failing network or storage plugin", evt.Reason),
+						},
 					})
 				}
 			} else if containerStatus.State.Waiting.Reason == "CrashLoopBackOff" && containerStatus.LastTerminationState.Terminated != nil {
 				// This represents container that is in CrashLoopBackOff state due to conditions such as OOMKilled
+				remediation := fmt.Sprintf("Read the logs of the previous instance of container %s to find why it exits", containerStatus.Name)
+				if containerStatus.LastTerminationState.Terminated.Reason == "OOMKilled" {
+					remediation = fmt.Sprintf("Raise the memory limit of container %s or lower its memory usage", containerStatus.Name)
+				}
 				failures = append(failures, common.Failure{
-					Text: fmt.Sprintf("the last termination reason is %s container=%s pod=%s", containerStatus.LastTerminationState.Terminated.Reason, containerStatus.Name, name),
+					ID:          "POD_CRASHLOOP",
+					Severity:    common.SeverityCritical,
+					Text:        fmt.Sprintf("the last termination reason is %s container=%s pod=%s", containerStatus.LastTerminationState.Terminated.Reason, containerStatus.Name, name),
+					FieldPath:   fieldPath + ".lastState.terminated",
+					Remediation: &common.Remediation{Text: remediation},
 				})
 			} else if utils.IsErrorReason(containerStatus.State.Waiting.Reason) && containerStatus.State.Waiting.Message != "" {
-				failures = append(failures, common.Failure{
-					Text: containerStatus.State.Waiting.Message,
-				})
+				failure := common.Failure{
+					ID:        "POD_CONTAINER_WAITING",
+					Severity:  common.SeverityCritical,
+					Text:      containerStatus.State.Waiting.Message,
+					FieldPath: fieldPath + ".state.waiting",
+				}
+				switch containerStatus.State.Waiting.Reason {
+				case "ImagePullBackOff", "ErrImagePull", "InvalidImageName":
+					failure.ID = "POD_IMAGE_PULL"
+					failure.Remediation = &common.Remediation{
+						Text: fmt.Sprintf("Check that the image %s exists and that the pod has the imagePullSecrets to pull it", containerStatus.Image),
+					}
+				case "CreateContainerConfigError":
+					failure.ID = "POD_CONTAINER_CONFIG"
+					failure.Remediation = &common.Remediation{
+						Text: "Create the secrets and configmaps the container references, or fix the keys it reads from them",
+					}
+				}
+				failures = append(failures, failure)
 			}
 		} else {
 			// when pod is Running but its ReadinessProbe fails
 			if !containerStatus.Ready && statusPhase == "Running" {
 				// parse the event log and append details
-				evt, err := utils.FetchLatestEvent(k.clientset, namespace, name)
+				evt, err := utils.FetchLatestEvent(ctx, k.clientset, namespace, name)
 				if err != nil || evt == nil {
 					continue
 				}
 				if evt.Reason == "Unhealthy" && evt.Message != "" {
 					failures = append(failures, common.Failure{
-						Text: evt.Message,
+						ID:        "POD_PROBE_FAILED",
+						Severity:  common.SeverityWarning,
+						Text:      evt.Message,
+						FieldPath: fieldPath + ".ready",
+						Remediation: &common.Remediation{
+							Text: fmt.Sprintf("Check that the probes of container %s match the port, path and startup time of the application", containerStatus.Name),
+						},
 					})
 				}
 			}
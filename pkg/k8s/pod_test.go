@@ -5,8 +5,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -21,11 +23,18 @@ func newTestKubernetes(clientset kubernetes.Interface, dynamicClient dynamic.Int
 		clientset:                   clientset,
 		dynamicClient:               dynamicClient,
 		discoveryClient:             newTestDiscoveryClient(),
+		cachedDiscoveryClient:       memory.NewMemCacheClient(newTestDiscoveryClient()),
 		deferredDiscoveryRESTMapper: restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(newTestDiscoveryClient())),
 		config:                      &rest.Config{},
 	}
 }
 
+// useDiscovery makes k discover the resources served by d
+func useDiscovery(k *Kubernetes, d discovery.DiscoveryInterface) {
+	k.discoveryClient = d
+	k.cachedDiscoveryClient = memory.NewMemCacheClient(d)
+}
+
 // setupNormalPodsClientset creates a fake clientset with pod data
 func newNormalPodsClientset() *fake.Clientset {
 	// Create a mock pod to use in tests
@@ -161,12 +170,16 @@ func TestAnalyzeContainerStatusFailures(t *testing.T) {
 		}
 
 		// Execute
-		failures := k.analyzeContainerStatusFailures(containerStatuses, "test-pod", "test-namespace", "Running")
+		failures := k.analyzeContainerStatusFailures(context.Background(), containerStatuses, "status.containerStatuses", "test-pod", "test-namespace", "Running")
 
 		// Verify
 		assert.NotEmpty(t, failures, "Should detect failures")
 		assert.Contains(t, failures[0].Text, "OOMKilled", "Should identify OOMKilled as termination reason")
 		assert.Contains(t, failures[0].Text, "crash-container", "Should mention the container name")
+		assert.Equal(t, "POD_CRASHLOOP", failures[0].ID)
+		assert.Equal(t, common.SeverityCritical, failures[0].Severity)
+		assert.Equal(t, "status.containerStatuses[0].lastState.terminated", failures[0].FieldPath)
+		assert.Equal(t, "Raise the memory limit of container crash-container or lower its memory usage", failures[0].Remediation.Text)
 	})
 
 	t.Run("Analyze container with waiting error", func(t *testing.T) {
@@ -189,10 +202,11 @@ func TestAnalyzeContainerStatusFailures(t *testing.T) {
 		}
 
 		// Execute
-		failures := k.analyzeContainerStatusFailures(containerStatuses, "test-pod", "test-namespace", "Pending")
+		failures := k.analyzeContainerStatusFailures(context.Background(), containerStatuses, "status.containerStatuses", "test-pod", "test-namespace", "Pending")
 
 		// Verify
 		assert.NotEmpty(t, failures, "Should detect failures")
 		assert.Equal(t, "Back-off pulling image error", failures[0].Text, "Should contain the error message")
+		assert.Equal(t, "POD_IMAGE_PULL", failures[0].ID)
 	})
 }
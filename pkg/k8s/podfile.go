@@ -0,0 +1,197 @@
+package k8s
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+)
+
+const (
+	podFileReadLimit  = 1 << 20
+	podFileWriteLimit = 1 << 20
+)
+
+// PodFileRead reads a file from a pod container by streaming it through tar like kubectl cp does.
+// The container must ship a tar binary.
+func (k *Kubernetes) PodFileRead(r common.Request, opts common.PodFileOptions) (string, error) {
+	if opts.Path == "" {
+		return "", fmt.Errorf("path is required")
+	}
+	if opts.Offset < 0 || opts.Length < 0 {
+		return "", fmt.Errorf("offset and length must not be negative")
+	}
+
+	ctx, cancel := context.WithCancel(r.Context)
+	defer cancel()
+
+	pr, pw := io.Pipe()
+	var stderr bytes.Buffer
+	errCh := make(chan error, 1)
+	go func() {
+		err := k.execStream(ctx, r.Namespace, r.Name, opts.Container, []string{"tar", "chf", "-", opts.Path}, nil, pw, &stderr)
+		pw.CloseWithError(err)
+		errCh <- err
+	}()
+
+	content, err := readTarEntry(pr, opts.Offset, opts.Length, podFileReadLimit)
+	// stop streaming the rest of the archive once the requested range has been read
+	cancel()
+	pr.Close()
+	execErr := <-errCh
+	if err != nil {
+		return "", podFileReadError(err, execErr, stderr.String())
+	}
+
+	content.Path = opts.Path
+	jsonData, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonData), nil
+}
+
+// PodFileWrite writes a file into a pod container by extracting a single file tar archive with the tar binary of the container.
+func (k *Kubernetes) PodFileWrite(r common.Request, opts common.PodFileOptions) (string, error) {
+	if opts.Path == "" || strings.HasSuffix(opts.Path, "/") {
+		return "", fmt.Errorf("path must be a file path")
+	}
+
+	data := []byte(opts.Content)
+	if opts.Encoding == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(opts.Content)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode base64 content: %v", err)
+		}
+		data = decoded
+	}
+	if len(data) > podFileWriteLimit {
+		return "", fmt.Errorf("content is %d bytes, the limit is %d bytes", len(data), podFileWriteLimit)
+	}
+
+	archive, err := newSingleFileTar(path.Base(opts.Path), data)
+	if err != nil {
+		return "", err
+	}
+
+	var stderr bytes.Buffer
+	err = k.execStream(r.Context, r.Namespace, r.Name, opts.Container, []string{"tar", "xmf", "-", "-C", path.Dir(opts.Path)}, bytes.NewReader(archive), nil, &stderr)
+	if err != nil {
+		return "", err
+	}
+	if stderr.Len() > 0 {
+		return "", fmt.Errorf("%s", strings.TrimSpace(stderr.String()))
+	}
+
+	return fmt.Sprintf("File %s written to pod %s/%s (%d bytes)", opts.Path, r.Namespace, r.Name, len(data)), nil
+}
+
+// errNoTarEntry is returned by readTarEntry when the stream ends before the file, tar failed to archive it
+var errNoTarEntry = errors.New("file not found")
+
+// podFileReadError picks the error explaining why a file could not be read. The error of the entry read from the
+// archive is the relevant one, the output and exit status of tar only explain a stream that ended early. The notice
+// GNU tar prints for absolute paths is not an error.
+func podFileReadError(readErr, execErr error, stderr string) error {
+	if !errors.Is(readErr, errNoTarEntry) && !errors.Is(readErr, io.ErrUnexpectedEOF) {
+		return readErr
+	}
+	var lines []string
+	for _, line := range strings.Split(stderr, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.Contains(line, "Removing leading") {
+			lines = append(lines, line)
+		}
+	}
+	switch {
+	case len(lines) > 0:
+		return errors.New(strings.Join(lines, "\n"))
+	case execErr != nil:
+		return execErr
+	}
+	return readErr
+}
+
+// readTarEntry reads a byte range of the first file in a tar stream, at most limit bytes are returned.
+func readTarEntry(r io.Reader, offset, length, limit int64) (*common.PodFileContent, error) {
+	tr := tar.NewReader(r)
+	hdr, err := tr.Next()
+	if errors.Is(err, io.EOF) {
+		return nil, errNoTarEntry
+	}
+	if err != nil {
+		return nil, err
+	}
+	if hdr.Typeflag == tar.TypeDir {
+		return nil, fmt.Errorf("%s is a directory", hdr.Name)
+	}
+	if hdr.Typeflag != tar.TypeReg {
+		return nil, fmt.Errorf("%s is not a regular file", hdr.Name)
+	}
+	if offset > hdr.Size {
+		return nil, fmt.Errorf("offset %d is beyond the file size %d", offset, hdr.Size)
+	}
+
+	remaining := hdr.Size - offset
+	if length == 0 || length > remaining {
+		length = remaining
+	}
+	truncated := false
+	if length > limit {
+		length = limit
+		truncated = true
+	}
+
+	if _, err := io.CopyN(io.Discard, tr, offset); err != nil {
+		return nil, err
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(tr, data); err != nil {
+		return nil, err
+	}
+
+	content := &common.PodFileContent{
+		Size:      hdr.Size,
+		Offset:    offset,
+		Length:    length,
+		Truncated: truncated,
+	}
+	if utf8.Valid(data) {
+		content.Encoding = "text"
+		content.Content = string(data)
+	} else {
+		content.Encoding = "base64"
+		content.Content = base64.StdEncoding.EncodeToString(data)
+	}
+	return content, nil
+}
+
+// newSingleFileTar returns a tar archive holding a single regular file.
+func newSingleFileTar(name string, data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
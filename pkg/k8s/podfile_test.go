@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadTarEntry(t *testing.T) {
+	archive, err := newSingleFileTar("app.conf", []byte("listen 8080\nworkers 4\n"))
+	assert.NoError(t, err)
+
+	t.Run("Read whole file", func(t *testing.T) {
+		content, err := readTarEntry(bytes.NewReader(archive), 0, 0, podFileReadLimit)
+		assert.NoError(t, err)
+		assert.Equal(t, "text", content.Encoding)
+		assert.Equal(t, "listen 8080\nworkers 4\n", content.Content)
+		assert.Equal(t, int64(22), content.Size)
+		assert.False(t, content.Truncated)
+	})
+
+	t.Run("Read byte range", func(t *testing.T) {
+		content, err := readTarEntry(bytes.NewReader(archive), 7, 4, podFileReadLimit)
+		assert.NoError(t, err)
+		assert.Equal(t, "8080", content.Content)
+		assert.Equal(t, int64(4), content.Length)
+	})
+
+	t.Run("Truncate at the size limit", func(t *testing.T) {
+		content, err := readTarEntry(bytes.NewReader(archive), 0, 0, 6)
+		assert.NoError(t, err)
+		assert.Equal(t, "listen", content.Content)
+		assert.True(t, content.Truncated)
+	})
+
+	t.Run("Offset beyond file size", func(t *testing.T) {
+		_, err := readTarEntry(bytes.NewReader(archive), 100, 0, podFileReadLimit)
+		assert.ErrorContains(t, err, "beyond the file size")
+	})
+
+	t.Run("Binary content is base64 encoded", func(t *testing.T) {
+		binary, err := newSingleFileTar("heap.bin", []byte{0xff, 0xfe, 0x00})
+		assert.NoError(t, err)
+		content, err := readTarEntry(bytes.NewReader(binary), 0, 0, podFileReadLimit)
+		assert.NoError(t, err)
+		assert.Equal(t, "base64", content.Encoding)
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte{0xff, 0xfe, 0x00}), content.Content)
+	})
+
+	t.Run("Directory is rejected", func(t *testing.T) {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		assert.NoError(t, tw.WriteHeader(&tar.Header{Name: "etc/", Typeflag: tar.TypeDir, Mode: 0755}))
+		assert.NoError(t, tw.Close())
+		_, err := readTarEntry(&buf, 0, 0, podFileReadLimit)
+		assert.ErrorContains(t, err, "is a directory")
+	})
+}
+
+func TestPodFileReadError(t *testing.T) {
+	notice := "tar: Removing leading `/' from member names\n"
+	execErr := errors.New("command terminated with exit code 2")
+	tests := []struct {
+		name    string
+		readErr error
+		execErr error
+		stderr  string
+		want    string
+	}{
+		{name: "Entry error wins over the tar notice", readErr: fmt.Errorf("offset 100 is beyond the file size 11"), stderr: notice, want: "offset 100 is beyond the file size 11"},
+		{name: "Missing file explained by tar", readErr: errNoTarEntry, execErr: execErr, stderr: notice + "tar: /etc/missing: No such file or directory\n", want: "tar: /etc/missing: No such file or directory"},
+		{name: "Missing file without output", readErr: errNoTarEntry, execErr: execErr, stderr: notice, want: execErr.Error()},
+		{name: "Stream ended early", readErr: io.ErrUnexpectedEOF, stderr: notice},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.want
+			if want == "" {
+				want = tt.readErr.Error()
+			}
+			assert.EqualError(t, podFileReadError(tt.readErr, tt.execErr, tt.stderr), want)
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// policyCostLimit bounds the work a single rule evaluation may do, so a rule iterating over large lists cannot
+// stall an analysis.
+const policyCostLimit = 1000000
+
+type compiledPolicyRule struct {
+	common.PolicyRule
+	gvk            schema.GroupVersionKind
+	program        cel.Program
+	messageProgram cel.Program
+}
+
+// policyAnalyzer evaluates the rules loaded with LoadPolicyRules, it reports nothing when no rules are loaded.
+type policyAnalyzer struct {
+	k *Kubernetes
+}
+
+func init() {
+	RegisterAnalyzer("policy", func(k *Kubernetes) Analyzer {
+		return policyAnalyzer{k: k}
+	})
+}
+
+func (a policyAnalyzer) Name() string { return "policy" }
+func (a policyAnalyzer) Description() string {
+	return "analyze objects of any kind, custom resources included, against the custom rules of the rules file"
+}
+func (a policyAnalyzer) Namespaced() bool { return true }
+
+func (a policyAnalyzer) Kinds() []string {
+	var kinds []string
+	for _, rule := range a.k.policyRules {
+		if !slices.Contains(kinds, rule.Kind) {
+			kinds = append(kinds, rule.Kind)
+		}
+	}
+	return kinds
+}
+
+func (a policyAnalyzer) Analyze(ctx context.Context, r common.Request) ([]common.Result, error) {
+	return a.k.analyzePolicy(ctx, r)
+}
+
+// LoadPolicyRules reads and compiles the rules file at path for the policy analyzer. Any invalid rule fails the
+// whole file, so a broken rules file is noticed at startup instead of during an analysis.
+func (k *Kubernetes) LoadPolicyRules(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var rulesFile common.PolicyRules
+	if err := yaml.UnmarshalStrict(data, &rulesFile); err != nil {
+		return fmt.Errorf("failed to parse rules file %s: %w", path, err)
+	}
+	rules, err := compilePolicyRules(rulesFile.Rules)
+	if err != nil {
+		return fmt.Errorf("invalid rules file %s: %w", path, err)
+	}
+	k.policyRules = rules
+	return nil
+}
+
+func compilePolicyRules(rules []common.PolicyRule) ([]compiledPolicyRule, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("object", cel.DynType),
+		ext.Strings(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	compiled := make([]compiledPolicyRule, 0, len(rules))
+	ids := map[string]bool{}
+	for i, rule := range rules {
+		switch {
+		case rule.ID == "":
+			return nil, fmt.Errorf("rule #%d has no id", i)
+		case ids[rule.ID]:
+			return nil, fmt.Errorf("rule %s is defined twice", rule.ID)
+		case rule.APIVersion == "" || rule.Kind == "":
+			return nil, fmt.Errorf("rule %s has no apiVersion or kind", rule.ID)
+		case rule.Severity != "" && severityRank[rule.Severity] == 0:
+			return nil, fmt.Errorf("rule %s has unknown severity %s, expected %s, %s or %s", rule.ID, rule.Severity, common.SeverityCritical, common.SeverityWarning, common.SeverityInfo)
+		}
+		ids[rule.ID] = true
+
+		gv, err := schema.ParseGroupVersion(rule.APIVersion)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.ID, err)
+		}
+		c := compiledPolicyRule{PolicyRule: rule, gvk: gv.WithKind(rule.Kind)}
+		if c.program, err = compilePolicyExpression(env, rule.Expression, cel.BoolType); err != nil {
+			return nil, fmt.Errorf("rule %s expression: %w", rule.ID, err)
+		}
+		if rule.MessageExpression != "" {
+			if c.messageProgram, err = compilePolicyExpression(env, rule.MessageExpression, cel.StringType); err != nil {
+				return nil, fmt.Errorf("rule %s messageExpression: %w", rule.ID, err)
+			}
+		}
+		compiled = append(compiled, c)
+	}
+	return compiled, nil
+}
+
+// compilePolicyExpression compiles a CEL expression that must return outputType, expressions whose type is only
+// known at runtime, such as a field of the object, are checked when they are evaluated.
+func compilePolicyExpression(env *cel.Env, expression string, outputType *cel.Type) (cel.Program, error) {
+	if expression == "" {
+		return nil, fmt.Errorf("expression is empty")
+	}
+	ast, issues := env.Compile(expression)
+	if issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if t := ast.OutputType(); !t.IsExactType(outputType) && !t.IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("expression must return %s, got %s", outputType, t)
+	}
+	return env.Program(ast, cel.CostLimit(policyCostLimit), cel.InterruptCheckFrequency(100))
+}
+
+// AnalyzePolicy evaluates the loaded policy rules and returns the objects that violate them
+func (k *Kubernetes) AnalyzePolicy(r common.Request) (string, error) {
+	return marshalResults(k.analyzePolicy(r.Context, r))
+}
+
+func (k *Kubernetes) analyzePolicy(ctx context.Context, r common.Request) ([]common.Result, error) {
+	var results []common.Result
+	// an object violating several rules is reported once with a failure per rule
+	index := map[string]int{}
+	owners := k.newOwnerResolver()
+	for _, rule := range k.policyRules {
+		if r.Namespace != "" && len(rule.Namespaces) > 0 && !slices.Contains(rule.Namespaces, r.Namespace) {
+			continue
+		}
+		mapping, err := k.deferredDiscoveryRESTMapper.RESTMapping(rule.gvk.GroupKind(), rule.gvk.Version)
+		if meta.IsNoMatchError(err) {
+			// the kind, usually a custom resource, is not served by this cluster
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.ID, err)
+		}
+
+		var list *unstructured.UnstructuredList
+		listOptions := metav1.ListOptions{}
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			list, err = k.dynamicClient.Resource(mapping.Resource).Namespace(r.Namespace).List(ctx, listOptions)
+		} else {
+			// cluster-scoped objects belong to no namespace, they are evaluated when analyzing all namespaces and
+			// once per cluster scan
+			if r.Namespace != "" && !inClusterScan(ctx) {
+				continue
+			}
+			var first bool
+			list, first, err = clusterList(ctx, "policy/"+rule.ID, func() (*unstructured.UnstructuredList, error) {
+				return k.dynamicClient.Resource(mapping.Resource).List(ctx, listOptions)
+			})
+			if err == nil && !first {
+				continue
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.ID, err)
+		}
+
+		for _, obj := range list.Items {
+			if obj.GetNamespace() != "" && len(rule.Namespaces) > 0 && !slices.Contains(rule.Namespaces, obj.GetNamespace()) {
+				continue
+			}
+			failure, violated := rule.evaluate(ctx, obj.Object)
+			if !violated {
+				continue
+			}
+
+			name := obj.GetName()
+			if obj.GetNamespace() != "" {
+				name = fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName())
+			}
+			key := rule.Kind + "/" + name
+			if i, ok := index[key]; ok {
+				results[i].Error = append(results[i].Error, failure)
+				continue
+			}
+			result := common.Result{
+				Kind:  rule.Kind,
+				Name:  name,
+				Error: []common.Failure{failure},
+			}
+			owners.setOwners(ctx, &result, obj.GetNamespace(), obj.GetOwnerReferences())
+			index[key] = len(results)
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// evaluate returns the failure the rule reports for object and whether the object violates the rule, a rule that
+// cannot be evaluated against the object is reported as violated so the error is not lost.
+func (rule compiledPolicyRule) evaluate(ctx context.Context, object map[string]interface{}) (common.Failure, bool) {
+	failure := common.Failure{
+		ID:        rule.ID,
+		Severity:  rule.Severity,
+		FieldPath: rule.FieldPath,
+	}
+	if rule.Remediation != "" {
+		failure.Remediation = &common.Remediation{Text: rule.Remediation}
+	}
+
+	activation := map[string]interface{}{"object": object}
+	out, _, err := rule.program.ContextEval(ctx, activation)
+	if err != nil {
+		failure.Text = fmt.Sprintf("Rule %s could not be evaluated: %v", rule.ID, err)
+		return failure, true
+	}
+	passed, ok := out.Value().(bool)
+	if !ok {
+		failure.Text = fmt.Sprintf("Rule %s returned %v instead of a bool", rule.ID, out.Value())
+		return failure, true
+	}
+	if passed {
+		return failure, false
+	}
+
+	failure.Text = rule.Message
+	if rule.messageProgram != nil {
+		if out, _, err := rule.messageProgram.ContextEval(ctx, activation); err == nil {
+			if message, ok := out.Value().(string); ok && message != "" {
+				failure.Text = message
+			}
+		}
+	}
+	if failure.Text == "" {
+		failure.Text = fmt.Sprintf("Violates rule %s: %s", rule.ID, rule.Expression)
+	}
+	return failure, true
+}
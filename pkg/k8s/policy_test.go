@@ -0,0 +1,192 @@
+package k8s
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery/cached/memory"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/restmapper"
+)
+
+const testPolicyRules = `
+rules:
+- id: DEPLOYMENT_TEAM_LABEL
+  apiVersion: apps/v1
+  kind: Deployment
+  expression: "has(object.metadata.labels) && 'team' in object.metadata.labels"
+  message: Deployment has no team label
+  fieldPath: metadata.labels
+  remediation: Add a team label naming the owning team
+- id: NO_LATEST_IMAGE
+  apiVersion: apps/v1
+  kind: Deployment
+  severity: critical
+  expression: "object.spec.template.spec.containers.all(c, !c.image.endsWith(':latest'))"
+  messageExpression: "'Deployment ' + object.metadata.name + ' runs a :latest image'"
+- id: WIDGET_OWNER
+  apiVersion: example.com/v1
+  kind: Widget
+  expression: "has(object.spec.owner)"
+`
+
+func newPolicyDeployment(name string, labels map[string]interface{}, image string) *unstructured.Unstructured {
+	metadata := map[string]interface{}{
+		"namespace": "test-namespace",
+		"name":      name,
+	}
+	if labels != nil {
+		metadata["labels"] = labels
+	}
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   metadata,
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "app", "image": image},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func writePolicyRules(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestAnalyzePolicy(t *testing.T) {
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+		newPolicyDeployment("compliant", map[string]interface{}{"team": "platform"}, "nginx:1.27"),
+		newPolicyDeployment("unlabeled", nil, "nginx:1.27"),
+		newPolicyDeployment("latest", nil, "nginx:latest"),
+	)
+	k := newTestKubernetes(kubernetesfake.NewSimpleClientset(), dynamicClient)
+	assert.NoError(t, k.LoadPolicyRules(writePolicyRules(t, testPolicyRules)))
+
+	results, err := k.analyzePolicy(context.Background(), common.Request{Namespace: "test-namespace"})
+
+	assert.NoError(t, err)
+	byName := map[string]common.Result{}
+	for _, result := range results {
+		byName[result.Name] = result
+	}
+	assert.Len(t, byName, 2, "the compliant deployment and the unserved Widget kind should not be reported")
+
+	unlabeled := byName["test-namespace/unlabeled"]
+	assert.Equal(t, "Deployment", unlabeled.Kind)
+	if assert.Len(t, unlabeled.Error, 1) {
+		assert.Equal(t, "DEPLOYMENT_TEAM_LABEL", unlabeled.Error[0].ID)
+		assert.Equal(t, "Deployment has no team label", unlabeled.Error[0].Text)
+		assert.Equal(t, "metadata.labels", unlabeled.Error[0].FieldPath)
+		assert.Equal(t, "Add a team label naming the owning team", unlabeled.Error[0].Remediation.Text)
+	}
+
+	latest := byName["test-namespace/latest"]
+	if assert.Len(t, latest.Error, 2) {
+		assert.Equal(t, "NO_LATEST_IMAGE", latest.Error[1].ID)
+		assert.Equal(t, common.SeverityCritical, latest.Error[1].Severity)
+		assert.Equal(t, "Deployment latest runs a :latest image", latest.Error[1].Text)
+	}
+}
+
+func TestAnalyzePolicyClusterScoped(t *testing.T) {
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Node",
+			"metadata":   map[string]interface{}{"name": "node-1"},
+		},
+	})
+	k := newTestKubernetes(kubernetesfake.NewSimpleClientset(), dynamicClient)
+	k.deferredDiscoveryRESTMapper = restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(newKindDiscovery()))
+	assert.NoError(t, k.LoadPolicyRules(writePolicyRules(t, `
+rules:
+- id: NODE_ZONE_LABEL
+  apiVersion: v1
+  kind: Node
+  expression: "has(object.metadata.labels) && 'topology.kubernetes.io/zone' in object.metadata.labels"
+  message: Node has no zone label
+`)))
+
+	results, err := k.analyzePolicy(context.Background(), common.Request{})
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "node-1", results[0].Name)
+	}
+
+	results, err = k.analyzePolicy(context.Background(), common.Request{Namespace: "shop"})
+	assert.NoError(t, err)
+	assert.Empty(t, results, "cluster-scoped objects should not be reported when analyzing a namespace")
+
+	// a cluster scan evaluates the cluster-scoped rules with the first namespace it analyzes
+	ctx := withScanState(context.Background())
+	results, err = k.analyzePolicy(ctx, common.Request{Namespace: "shop"})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	results, err = k.analyzePolicy(ctx, common.Request{Namespace: "staging"})
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestLoadPolicyRulesInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+		err   string
+	}{
+		{
+			name:  "missing id",
+			rules: "rules:\n- apiVersion: v1\n  kind: Pod\n  expression: 'true'\n",
+			err:   "rule #0 has no id",
+		},
+		{
+			name:  "duplicate id",
+			rules: "rules:\n- id: A\n  apiVersion: v1\n  kind: Pod\n  expression: 'true'\n- id: A\n  apiVersion: v1\n  kind: Pod\n  expression: 'true'\n",
+			err:   "rule A is defined twice",
+		},
+		{
+			name:  "unknown severity",
+			rules: "rules:\n- id: A\n  apiVersion: v1\n  kind: Pod\n  severity: fatal\n  expression: 'true'\n",
+			err:   "unknown severity fatal",
+		},
+		{
+			name:  "expression not returning a bool",
+			rules: "rules:\n- id: A\n  apiVersion: v1\n  kind: Pod\n  expression: \"'yes'\"\n",
+			err:   "expression must return bool",
+		},
+		{
+			name:  "syntax error",
+			rules: "rules:\n- id: A\n  apiVersion: v1\n  kind: Pod\n  expression: 'object.metadata.'\n",
+			err:   "rule A expression",
+		},
+		{
+			name:  "unknown field",
+			rules: "rules:\n- id: A\n  apiVersion: v1\n  kind: Pod\n  expr: 'true'\n",
+			err:   "failed to parse rules file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := newTestKubernetes(kubernetesfake.NewSimpleClientset(), nil)
+			err := k.LoadPolicyRules(writePolicyRules(t, tt.rules))
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package k8s
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+const (
+	defaultProbeTimeout = 10 * time.Second
+	probeBodyLimit      = 4096
+	// tcpProbeGrace is how long a forwarded connection must stay open before the port is considered reachable
+	tcpProbeGrace = 500 * time.Millisecond
+)
+
+// PodProbe port-forwards to a pod port and issues an HTTP(S) or TCP check against it.
+func (k *Kubernetes) PodProbe(r common.Request, opts common.ProbeOptions) (string, error) {
+	if opts.Port == "" {
+		return "", fmt.Errorf("port is required to probe a pod")
+	}
+	pod, err := k.clientset.CoreV1().Pods(r.Namespace).Get(r.Context, r.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	port, err := resolveContainerPort(pod, intstr.Parse(opts.Port))
+	if err != nil {
+		return "", err
+	}
+
+	result := k.probe(r.Context, pod, port, opts)
+	result.Target = fmt.Sprintf("Pod/%s/%s:%s", pod.Namespace, pod.Name, opts.Port)
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonData), nil
+}
+
+// ServiceProbe picks a ready pod backing the service port and probes it through a port-forward.
+func (k *Kubernetes) ServiceProbe(r common.Request, opts common.ProbeOptions) (string, error) {
+	svc, err := k.clientset.CoreV1().Services(r.Namespace).Get(r.Context, r.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	pod, port, err := k.serviceBackend(r.Context, svc, opts.Port)
+	if err != nil {
+		return "", err
+	}
+
+	result := k.probe(r.Context, pod, port, opts)
+	result.Target = fmt.Sprintf("Service/%s/%s", svc.Namespace, svc.Name)
+	if opts.Port != "" {
+		result.Target += ":" + opts.Port
+	}
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonData), nil
+}
+
+// serviceBackend returns the first ready pod behind the service port together with the resolved target port.
+func (k *Kubernetes) serviceBackend(ctx context.Context, svc *v1.Service, port string) (*v1.Pod, int32, error) {
+	var svcPort *v1.ServicePort
+	for i, p := range svc.Spec.Ports {
+		if (port == "" && len(svc.Spec.Ports) == 1) || p.Name == port || strconv.Itoa(int(p.Port)) == port {
+			svcPort = &svc.Spec.Ports[i]
+			break
+		}
+	}
+	if svcPort == nil {
+		return nil, 0, fmt.Errorf("service %s/%s has no port %q", svc.Namespace, svc.Name, port)
+	}
+
+	ep, err := k.clientset.CoreV1().Endpoints(svc.Namespace).Get(ctx, svc.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, subset := range ep.Subsets {
+		// endpoint ports carry the target port already resolved against the pods of this subset
+		var targetPort int32
+		for _, p := range subset.Ports {
+			if p.Name == svcPort.Name {
+				targetPort = p.Port
+				break
+			}
+		}
+		if targetPort == 0 {
+			continue
+		}
+		for _, addr := range subset.Addresses {
+			if addr.TargetRef == nil || addr.TargetRef.Kind != "Pod" {
+				continue
+			}
+			pod, err := k.clientset.CoreV1().Pods(svc.Namespace).Get(ctx, addr.TargetRef.Name, metav1.GetOptions{})
+			if err != nil {
+				continue
+			}
+			return pod, targetPort, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("service %s/%s has no ready pod endpoints for port %d", svc.Namespace, svc.Name, svcPort.Port)
+}
+
+// resolveContainerPort resolves a port number or a named container port of the pod.
+func resolveContainerPort(pod *v1.Pod, port intstr.IntOrString) (int32, error) {
+	if port.Type == intstr.Int {
+		return port.IntVal, nil
+	}
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Name == port.StrVal {
+				return p.ContainerPort, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("pod %s/%s has no container port named %s", pod.Namespace, pod.Name, port.StrVal)
+}
+
+// probe runs the check over a temporary port-forward, the latency excludes the port-forward setup.
+func (k *Kubernetes) probe(ctx context.Context, pod *v1.Pod, port int32, opts common.ProbeOptions) *common.ProbeResult {
+	scheme := strings.ToLower(opts.Scheme)
+	if scheme == "" {
+		scheme = "http"
+	}
+	result := &common.ProbeResult{
+		Pod:    fmt.Sprintf("%s/%s", pod.Namespace, pod.Name),
+		Scheme: scheme,
+		Port:   port,
+	}
+	if pod.Status.Phase != v1.PodRunning {
+		result.Error = fmt.Sprintf("pod is in %s phase", pod.Status.Phase)
+		return result
+	}
+
+	timeout := defaultProbeTimeout
+	if opts.Timeout > 0 {
+		timeout = time.Duration(opts.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	localPort, stop, err := k.portForward(ctx, pod.Namespace, pod.Name, port)
+	if err != nil {
+		result.Error = fmt.Sprintf("port-forward failed: %v", err)
+		return result
+	}
+	defer stop()
+
+	addr := fmt.Sprintf("127.0.0.1:%d", localPort)
+	start := time.Now()
+	switch scheme {
+	case "tcp":
+		err = probeTCP(ctx, addr)
+	case "http", "https":
+		err = probeHTTP(ctx, result, scheme, addr, opts.Path)
+	default:
+		err = fmt.Errorf("unsupported probe scheme %s", opts.Scheme)
+	}
+	result.Latency = time.Since(start).String()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Success = scheme == "tcp" || result.StatusCode < http.StatusBadRequest
+	return result
+}
+
+// portForward forwards a random local port to the pod port, the returned func stops the forwarding.
+func (k *Kubernetes) portForward(ctx context.Context, namespace, name string, port int32) (uint16, func(), error) {
+	transport, upgrader, err := spdy.RoundTripperFor(k.config)
+	if err != nil {
+		return 0, nil, err
+	}
+	req := k.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(name).
+		SubResource("portforward")
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+
+	stopCh := make(chan struct{})
+	readyCh := make(chan struct{})
+	fw, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"}, []string{fmt.Sprintf("0:%d", port)}, stopCh, readyCh, io.Discard, io.Discard)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- fw.ForwardPorts()
+	}()
+	select {
+	case <-readyCh:
+	case err := <-errCh:
+		return 0, nil, err
+	case <-ctx.Done():
+		close(stopCh)
+		return 0, nil, ctx.Err()
+	}
+
+	ports, err := fw.GetPorts()
+	if err != nil || len(ports) == 0 {
+		close(stopCh)
+		return 0, nil, fmt.Errorf("failed to get forwarded port: %v", err)
+	}
+	return ports[0].Local, func() { close(stopCh) }, nil
+}
+
+func probeHTTP(ctx context.Context, result *common.ProbeResult, scheme, addr, path string) error {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			// pods rarely serve certificates valid for 127.0.0.1, kubelet https probes skip verification as well
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+			DisableKeepAlives: true,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s%s", scheme, addr, path), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+	result.Status = resp.Status
+	result.Headers = make(map[string]string, len(resp.Header))
+	for k, v := range resp.Header {
+		result.Headers[k] = strings.Join(v, ", ")
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, probeBodyLimit+1))
+	if err != nil {
+		return err
+	}
+	if len(body) > probeBodyLimit {
+		body = body[:probeBodyLimit]
+		result.Truncated = true
+	}
+	result.Body = string(body)
+	return nil
+}
+
+// probeTCP treats a forwarded connection that is not closed by the remote side within a short grace period as open.
+func probeTCP(ctx context.Context, addr string) error {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetReadDeadline(time.Now().Add(tcpProbeGrace)); err != nil {
+		return err
+	}
+	_, err = conn.Read(make([]byte, 1))
+	var netErr net.Error
+	if err == nil || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return nil
+	}
+	return fmt.Errorf("connection closed by the remote side, the port is likely not listening: %v", err)
+}
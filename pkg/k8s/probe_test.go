@@ -0,0 +1,103 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newProbePod() *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-0",
+			Namespace: "default",
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name: "web",
+					Ports: []v1.ContainerPort{
+						{Name: "http", ContainerPort: 8080},
+					},
+				},
+			},
+		},
+		Status: v1.PodStatus{
+			Phase: v1.PodRunning,
+		},
+	}
+}
+
+func TestResolveContainerPort(t *testing.T) {
+	pod := newProbePod()
+
+	port, err := resolveContainerPort(pod, intstr.Parse("9090"))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(9090), port)
+
+	port, err = resolveContainerPort(pod, intstr.Parse("http"))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(8080), port)
+
+	_, err = resolveContainerPort(pod, intstr.Parse("metrics"))
+	assert.ErrorContains(t, err, "no container port named metrics")
+}
+
+func TestServiceBackend(t *testing.T) {
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{
+				{Name: "http", Port: 80, TargetPort: intstr.FromString("http")},
+			},
+		},
+	}
+	ep := &v1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: "default",
+		},
+		Subsets: []v1.EndpointSubset{
+			{
+				Addresses: []v1.EndpointAddress{
+					{IP: "10.0.0.1", TargetRef: &v1.ObjectReference{Kind: "Pod", Name: "web-0", Namespace: "default"}},
+				},
+				Ports: []v1.EndpointPort{
+					{Name: "http", Port: 8080},
+				},
+			},
+		},
+	}
+
+	t.Run("Resolve ready pod and target port", func(t *testing.T) {
+		k := newTestKubernetes(fake.NewSimpleClientset(svc, ep, newProbePod()), nil)
+		for _, port := range []string{"", "80", "http"} {
+			pod, targetPort, err := k.serviceBackend(context.Background(), svc, port)
+			assert.NoError(t, err)
+			assert.Equal(t, "web-0", pod.Name)
+			assert.Equal(t, int32(8080), targetPort)
+		}
+	})
+
+	t.Run("Unknown service port", func(t *testing.T) {
+		k := newTestKubernetes(fake.NewSimpleClientset(svc, ep, newProbePod()), nil)
+		_, _, err := k.serviceBackend(context.Background(), svc, "443")
+		assert.ErrorContains(t, err, `has no port "443"`)
+	})
+
+	t.Run("No ready endpoints", func(t *testing.T) {
+		emptyEp := ep.DeepCopy()
+		emptyEp.Subsets = nil
+		k := newTestKubernetes(fake.NewSimpleClientset(svc, emptyEp), nil)
+		_, _, err := k.serviceBackend(context.Background(), svc, "http")
+		assert.ErrorContains(t, err, "has no ready pod endpoints")
+	})
+}
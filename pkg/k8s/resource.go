@@ -12,30 +12,25 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/kubectl/pkg/describe"
 	metricsv1beta1api "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 )
 
-func (k *Kubernetes) ResourceList(ctx context.Context, kind, namespace string) (string, error) {
-	kind = utils.Capitalize(kind)
-	gv := utils.GetGroupVersionForKind(kind)
-	gvk := gv.WithKind(kind)
-	gvr, err := k.gvrFor(gvk)
+// ResourceList lists the objects of a kind in a namespace, or in all namespaces when the namespace is empty. The kind
+// is resolved through discovery, see resolveKind.
+func (k *Kubernetes) ResourceList(r common.Request) (string, error) {
+	mapping, err := k.resolveKind(r.Kind, r.APIVersion)
 	if err != nil {
 		return "", err
 	}
 
-	isNamespaced, err := k.isNamespaced(gvk)
-	if err != nil {
-		return "", err
-	}
-	if isNamespaced {
-		namespace = utils.NamespaceOrDefault(namespace)
+	namespace := ""
+	if isNamespaced(mapping) {
+		namespace = utils.NamespaceOrDefault(r.Namespace)
 	}
 
-	resources, err := k.dynamicClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	resources, err := k.dynamicClient.Resource(mapping.Resource).Namespace(namespace).List(r.Context, metav1.ListOptions{})
 	if err != nil {
 		return "", err
 	}
@@ -43,25 +38,18 @@ func (k *Kubernetes) ResourceList(ctx context.Context, kind, namespace string) (
 	return utils.Marshal(resources.Items)
 }
 
-func (k *Kubernetes) ResourceGet(ctx context.Context, kind, namespace, name string) (string, error) {
-	kind = utils.Capitalize(kind)
-	gv := utils.GetGroupVersionForKind(kind)
-	gvk := gv.WithKind(kind)
-	gvr, err := k.gvrFor(gvk)
-	if err != nil {
-		return "", err
-	}
-
-	isNamespaced, err := k.isNamespaced(gvk)
+func (k *Kubernetes) ResourceGet(r common.Request) (string, error) {
+	mapping, err := k.resolveKind(r.Kind, r.APIVersion)
 	if err != nil {
 		return "", err
 	}
 
-	if isNamespaced {
-		namespace = utils.NamespaceOrDefault(namespace)
+	namespace := ""
+	if isNamespaced(mapping) {
+		namespace = utils.NamespaceOrDefault(r.Namespace)
 	}
 
-	resource, err := k.dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	resource, err := k.dynamicClient.Resource(mapping.Resource).Namespace(namespace).Get(r.Context, r.Name, metav1.GetOptions{})
 	if err != nil {
 		return "", err
 	}
@@ -69,59 +57,68 @@ func (k *Kubernetes) ResourceGet(ctx context.Context, kind, namespace, name stri
 	return utils.Marshal(resource)
 }
 
-func (k *Kubernetes) ResourceDelete(ctx context.Context, kind, namespace, name string) (string, error) {
-	kind = utils.Capitalize(kind)
-	gv := utils.GetGroupVersionForKind(kind)
-	gvk := gv.WithKind(kind)
-	gvr, err := k.gvrFor(gvk)
-	if err != nil {
-		return "", err
-	}
-
-	isNamespaced, err := k.isNamespaced(gvk)
+func (k *Kubernetes) ResourceCreateOrUpdate(ctx context.Context, resource string) (string, error) {
+	unstructuredObjects, err := parseManifests(resource)
 	if err != nil {
 		return "", err
 	}
 
-	if isNamespaced {
-		namespace = utils.NamespaceOrDefault(namespace)
-	}
+	return k.resourceCreateOrUpdate(ctx, unstructuredObjects)
+}
 
-	err = k.dynamicClient.Resource(gvr).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+// ManifestObjects lists the objects of the YAML documents ResourceCreateOrUpdate would apply, namespaced objects
+// without a namespace are reported in the default namespace they are applied to.
+func (k *Kubernetes) ManifestObjects(resource string) ([]common.ObjectReference, error) {
+	unstructuredObjects, err := parseManifests(resource)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-
-	return fmt.Sprintf("Resource %s/%s deleted successfully", namespace, name), nil
+	refs := make([]common.ObjectReference, 0, len(unstructuredObjects))
+	for _, obj := range unstructuredObjects {
+		if obj == nil {
+			continue
+		}
+		gvk := obj.GroupVersionKind()
+		namespace := obj.GetNamespace()
+		if mapping, err := k.deferredDiscoveryRESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version); err == nil && isNamespaced(mapping) {
+			namespace = utils.NamespaceOrDefault(namespace)
+		}
+		refs = append(refs, common.ObjectReference{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Namespace:  namespace,
+			Name:       obj.GetName(),
+		})
+	}
+	return refs, nil
 }
 
-func (k *Kubernetes) ResourceCreateOrUpdate(ctx context.Context, resource string) (string, error) {
+func parseManifests(resource string) ([]*unstructured.Unstructured, error) {
 	separator := regexp.MustCompile(`\r?\n---\r?\n`)
 	resources := separator.Split(resource, -1)
 	var unstructuredObjects []*unstructured.Unstructured
 	for _, r := range resources {
 		var obj *unstructured.Unstructured
 		if err := yaml.NewYAMLToJSONDecoder(strings.NewReader(r)).Decode(&obj); err != nil {
-			return "", err
+			return nil, err
 		}
 		unstructuredObjects = append(unstructuredObjects, obj)
 	}
-
-	return k.resourceCreateOrUpdate(ctx, unstructuredObjects)
+	return unstructuredObjects, nil
 }
 
 func (k *Kubernetes) resourceCreateOrUpdate(ctx context.Context, resources []*unstructured.Unstructured) (string, error) {
 	for _, obj := range resources {
 		gvk := obj.GroupVersionKind()
-		gvr, err := k.gvrFor(gvk)
+		mapping, err := k.deferredDiscoveryRESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
 		if err != nil {
 			return "", err
 		}
-		namespace := obj.GetNamespace()
-		if isNamespaced, err := k.isNamespaced(gvk); err == nil && isNamespaced {
-			namespace = utils.NamespaceOrDefault(namespace)
+		namespace := ""
+		if isNamespaced(mapping) {
+			namespace = utils.NamespaceOrDefault(obj.GetNamespace())
 		}
-		_, err = k.dynamicClient.Resource(gvr).Namespace(namespace).Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{
+		_, err = k.dynamicClient.Resource(mapping.Resource).Namespace(namespace).Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{
 			FieldManager: common.ProjectName,
 		})
 		if err != nil {
@@ -136,10 +133,6 @@ func (k *Kubernetes) resourceCreateOrUpdate(ctx context.Context, resources []*un
 }
 
 func (k *Kubernetes) ResourceDescribe(r common.Request) (string, error) {
-	kind := utils.Capitalize(r.Kind)
-	gv := utils.GetGroupVersionForKind(kind)
-	gvk := gv.WithKind(kind)
-
 	describer := func(mapping *meta.RESTMapping) (describe.ResourceDescriber, error) {
 		// try to get a describer
 		if describer, ok := describe.DescriberFor(mapping.GroupVersionKind.GroupKind(), k.config); ok {
@@ -153,44 +146,24 @@ func (k *Kubernetes) ResourceDescribe(r common.Request) (string, error) {
 		return nil, fmt.Errorf("no description has been implemented for %s", mapping.GroupVersionKind.String())
 	}
 
-	mapping, err := k.deferredDiscoveryRESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	mapping, err := k.resolveKind(r.Kind, r.APIVersion)
 	if err != nil {
 		return "", err
 	}
+	namespace := ""
+	if isNamespaced(mapping) {
+		namespace = utils.NamespaceOrDefault(r.Namespace)
+	}
 
 	d, err := describer(mapping)
 	if err != nil {
 		return "", err
 	}
-	return d.Describe(r.Namespace, r.Name, describe.DescriberSettings{
+	return d.Describe(namespace, r.Name, describe.DescriberSettings{
 		ShowEvents: true,
 	})
 }
 
-func (k *Kubernetes) gvrFor(gvk schema.GroupVersionKind) (schema.GroupVersionResource, error) {
-	mapping, err := k.deferredDiscoveryRESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
-	if err != nil {
-		return schema.GroupVersionResource{}, err
-	}
-
-	return mapping.Resource, nil
-}
-
-func (k *Kubernetes) isNamespaced(gvk schema.GroupVersionKind) (bool, error) {
-	apiResourceList, err := k.discoveryClient.ServerResourcesForGroupVersion(gvk.GroupVersion().String())
-	if err != nil {
-		return false, err
-	}
-
-	for _, apiResource := range apiResourceList.APIResources {
-		if apiResource.Name == gvk.Kind {
-			return apiResource.Namespaced, nil
-		}
-	}
-
-	return false, nil
-}
-
 func (k *Kubernetes) WorkloadResourceUsage(r common.Request) (string, error) {
 	var workloadMetrics = make(map[string][]metricsv1beta1api.PodMetrics, 0)
 	switch r.Kind {
@@ -7,10 +7,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
 	discoveryfake "k8s.io/client-go/discovery/fake"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	kubernetesfake "k8s.io/client-go/kubernetes/fake"
@@ -39,6 +41,10 @@ func (c *fakeDiscovery) ServerResourcesForGroupVersion(groupVersion string) (*me
 	return nil, errors.New("doesn't exist")
 }
 
+func (c *fakeDiscovery) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	return discovery.ServerPreferredResources(c)
+}
+
 func (c *fakeDiscovery) ServerGroups() (*metav1.APIGroupList, error) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -103,13 +109,22 @@ func TestResourceList(t *testing.T) {
 		k := newTestKubernetes(clientset, newDynamicClient())
 
 		// Execute
-		result, err := k.ResourceList(context.Background(), "Deployment", "test-namespace")
+		result, err := k.ResourceList(common.Request{Context: context.Background(), Kind: "Deployment", Namespace: "test-namespace"})
 
 		// Verify
 		assert.NoError(t, err, "Should not return an error")
 		assert.Contains(t, result, "test-deployment", "Result should contain the deployment name")
 		assert.Contains(t, result, "test-namespace", "Result should contain the namespace")
 	})
+
+	t.Run("Empty namespace lists the default namespace", func(t *testing.T) {
+		k := newTestKubernetes(kubernetesfake.NewSimpleClientset(), newDynamicClient())
+
+		result, err := k.ResourceList(common.Request{Context: context.Background(), Kind: "Deployment"})
+
+		assert.NoError(t, err, "Should not return an error")
+		assert.NotContains(t, result, "test-deployment", "Result should not contain the deployments of other namespaces")
+	})
 }
 
 func TestResourceGet(t *testing.T) {
@@ -119,7 +134,7 @@ func TestResourceGet(t *testing.T) {
 		k := newTestKubernetes(clientset, newDynamicClient())
 
 		// Execute
-		result, err := k.ResourceGet(context.Background(), "Deployment", "test-namespace", "test-deployment")
+		result, err := k.ResourceGet(common.Request{Context: context.Background(), Kind: "Deployment", Namespace: "test-namespace", Name: "test-deployment"})
 
 		// Verify
 		assert.NoError(t, err, "Should not return an error")
@@ -133,7 +148,7 @@ func TestResourceGet(t *testing.T) {
 		k := newTestKubernetes(clientset, newDynamicClient())
 
 		// Execute
-		result, err := k.ResourceGet(context.Background(), "Deployment", "test-namespace", "non-existent-deployment")
+		result, err := k.ResourceGet(common.Request{Context: context.Background(), Kind: "Deployment", Namespace: "test-namespace", Name: "non-existent-deployment"})
 
 		// Verify
 		assert.Error(t, err, "Should return an error")
@@ -149,14 +164,14 @@ func TestResourceDelete(t *testing.T) {
 		k := newTestKubernetes(clientset, newDynamicClient())
 
 		// Execute
-		result, err := k.ResourceDelete(context.Background(), "Deployment", "test-namespace", "test-deployment")
+		result, err := k.ResourceDelete(common.Request{Context: context.Background(), Kind: "Deployment", Namespace: "test-namespace", Name: "test-deployment"}, common.DeleteOptions{})
 
 		// Verify
 		assert.NoError(t, err, "Should not return an error")
 		assert.Contains(t, result, "deleted successfully", "Result should indicate success")
 
 		// Verify the pod was actually deleted
-		_, err = k.ResourceGet(context.Background(), "Deployment", "test-namespace", "test-deployment")
+		_, err = k.ResourceGet(common.Request{Context: context.Background(), Kind: "Deployment", Namespace: "test-namespace", Name: "test-deployment"})
 		assert.Error(t, err, "Deployment should be deleted")
 		assert.Contains(t, err.Error(), "not found", "Error should indicate the deployment wasn't found")
 	})
@@ -167,7 +182,7 @@ func TestResourceDelete(t *testing.T) {
 		k := newTestKubernetes(clientset, newDynamicClient())
 
 		// Execute
-		result, err := k.ResourceDelete(context.Background(), "Deployment", "test-namespace", "non-existent-deployment")
+		result, err := k.ResourceDelete(common.Request{Context: context.Background(), Kind: "Deployment", Namespace: "test-namespace", Name: "non-existent-deployment"}, common.DeleteOptions{})
 
 		// Verify
 		assert.Error(t, err, "Should return an error")
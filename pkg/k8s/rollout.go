@@ -0,0 +1,485 @@
+package k8s
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kubectl/pkg/describe"
+	"k8s.io/kubectl/pkg/polymorphichelpers"
+	deploymentutil "k8s.io/kubectl/pkg/util/deployment"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	restartedAtAnnotation      = "kubectl.kubernetes.io/restartedAt"
+	defaultRolloutWaitTimeout  = 5 * time.Minute
+	rolloutStatusPollInterval  = 2 * time.Second
+	rolloutHistoryTemplateHash = "pod-template-hash"
+	changeCauseAnnotation      = "kubernetes.io/change-cause"
+)
+
+// RolloutStatus returns the rollout status of a Deployment, StatefulSet or DaemonSet.
+// With wait it blocks until the rollout is done or the timeout expires.
+func (k *Kubernetes) RolloutStatus(r common.Request, revision int64, waitDone bool, timeout time.Duration) (string, error) {
+	gk, err := rolloutGroupKind(r.Kind)
+	if err != nil {
+		return "", err
+	}
+	viewer, err := polymorphichelpers.StatusViewerFor(gk)
+	if err != nil {
+		return "", err
+	}
+
+	status := func(ctx context.Context) (string, bool, error) {
+		obj, err := k.getRolloutObject(ctx, r.Kind, r.Namespace, r.Name)
+		if err != nil {
+			return "", false, err
+		}
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return "", false, err
+		}
+		return viewer.Status(&unstructured.Unstructured{Object: content}, revision)
+	}
+
+	if !waitDone {
+		msg, _, err := status(r.Context)
+		return strings.TrimSpace(msg), err
+	}
+
+	if timeout <= 0 {
+		timeout = defaultRolloutWaitTimeout
+	}
+	var msg string
+	var polls int
+	err = wait.PollUntilContextTimeout(r.Context, rolloutStatusPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		m, done, err := status(ctx)
+		if err != nil {
+			return false, err
+		}
+		// the status is only reported when it changes, the total is not known
+		if polls++; r.Progress != nil && !done && m != msg {
+			r.Progress(polls, 0, strings.TrimSpace(m))
+		}
+		msg = m
+		return done, nil
+	})
+	if wait.Interrupted(err) && r.Context.Err() == nil {
+		return "", fmt.Errorf("timed out waiting for the rollout of %s %s/%s: %s", r.Kind, r.Namespace, r.Name, strings.TrimSpace(msg))
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(msg), nil
+}
+
+// RolloutHistory lists the revisions with their change-cause followed by the pod template diff between consecutive revisions.
+// A non-zero revision shows the pod template of that revision and its diff against the previous one.
+func (k *Kubernetes) RolloutHistory(r common.Request, revision int64) (string, error) {
+	obj, err := k.getRolloutObject(r.Context, r.Kind, r.Namespace, r.Name)
+	if err != nil {
+		return "", err
+	}
+	history, err := k.rolloutRevisions(r.Context, obj)
+	if err != nil {
+		return "", err
+	}
+	if len(history) == 0 {
+		return "No rollout history found.", nil
+	}
+	revisions := make([]int64, 0, len(history))
+	for rev := range history {
+		revisions = append(revisions, rev)
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i] < revisions[j] })
+
+	var sb strings.Builder
+	if revision > 0 {
+		rev, ok := history[revision]
+		if !ok {
+			return "", fmt.Errorf("unable to find the specified revision")
+		}
+		describe.DescribePodTemplate(&rev.template, describe.NewPrefixWriter(&sb))
+	} else {
+		w := tabwriter.NewWriter(&sb, 0, 8, 2, ' ', 0)
+		fmt.Fprintf(w, "REVISION\tCHANGE-CAUSE\n")
+		for _, rev := range revisions {
+			fmt.Fprintf(w, "%d\t%s\n", rev, cmp.Or(history[rev].changeCause, "<none>"))
+		}
+		_ = w.Flush()
+	}
+
+	for i := 1; i < len(revisions); i++ {
+		if revision > 0 && revisions[i] != revision {
+			continue
+		}
+		diff, err := podTemplateDiff(history[revisions[i-1]].template, history[revisions[i]].template, revisions[i-1], revisions[i])
+		if err != nil {
+			return "", err
+		}
+		if diff == "" {
+			diff = "pod template unchanged\n"
+		}
+		fmt.Fprintf(&sb, "\nRevision %d -> %d:\n%s", revisions[i-1], revisions[i], diff)
+	}
+	return sb.String(), nil
+}
+
+// RolloutUndo rolls back to the given revision, a zero revision rolls back to the previous one.
+func (k *Kubernetes) RolloutUndo(r common.Request, toRevision int64) (string, error) {
+	if toRevision < 0 {
+		return "", fmt.Errorf("unable to find specified revision %d in history", toRevision)
+	}
+	obj, err := k.getRolloutObject(r.Context, r.Kind, r.Namespace, r.Name)
+	if err != nil {
+		return "", err
+	}
+	if deploy, ok := obj.(*appsv1.Deployment); ok && deploy.Spec.Paused {
+		return "", fmt.Errorf("you cannot rollback a paused deployment; resume it first with rollout resume and try again")
+	}
+	history, err := k.rolloutRevisions(r.Context, obj)
+	if err != nil {
+		return "", err
+	}
+
+	target, ok := history[toRevision]
+	if toRevision == 0 {
+		// the previous revision is the second newest one
+		var latest, previous int64 = -1, -1
+		for rev := range history {
+			if rev > latest {
+				latest, previous = rev, latest
+			} else if rev > previous {
+				previous = rev
+			}
+		}
+		if previous < 0 {
+			return "", fmt.Errorf("no last revision to roll back to")
+		}
+		target, ok = history[previous], true
+	}
+	if !ok {
+		return "", fmt.Errorf("unable to find specified revision %d in history", toRevision)
+	}
+
+	current, _ := rolloutTemplate(obj)
+	if equalIgnoreHash(target.template, current) {
+		return fmt.Sprintf("%s %s/%s skipped rollback (current template already matches revision %d)", r.Kind, r.Namespace, r.Name, target.number), nil
+	}
+
+	opts := metav1.PatchOptions{FieldManager: common.ProjectName}
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		patch, err := deploymentRollbackPatch(o, target)
+		if err == nil {
+			_, err = k.clientset.AppsV1().Deployments(r.Namespace).Patch(r.Context, r.Name, types.JSONPatchType, patch, opts)
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed restoring revision %d: %w", target.number, err)
+		}
+	case *appsv1.StatefulSet:
+		// the controller revisions hold a strategic merge patch restoring the pod template
+		if _, err := k.clientset.AppsV1().StatefulSets(r.Namespace).Patch(r.Context, r.Name, types.StrategicMergePatchType, target.patch, opts); err != nil {
+			return "", fmt.Errorf("failed restoring revision %d: %w", target.number, err)
+		}
+	case *appsv1.DaemonSet:
+		if _, err := k.clientset.AppsV1().DaemonSets(r.Namespace).Patch(r.Context, r.Name, types.StrategicMergePatchType, target.patch, opts); err != nil {
+			return "", fmt.Errorf("failed restoring revision %d: %w", target.number, err)
+		}
+	}
+	return fmt.Sprintf("%s %s/%s rolled back to revision %d", r.Kind, r.Namespace, r.Name, target.number), nil
+}
+
+// rolloutRevision is a revision of a Deployment, StatefulSet or DaemonSet. The pod template of a Deployment revision
+// is held by a ReplicaSet, the one of the other kinds by a ControllerRevision holding the patch restoring it.
+type rolloutRevision struct {
+	number      int64
+	changeCause string
+	template    corev1.PodTemplateSpec
+	annotations map[string]string
+	patch       []byte
+}
+
+// rolloutRevisions returns the revisions of a workload by number, they are read with the request context rather than
+// through the kubectl history helpers which do not take one.
+func (k *Kubernetes) rolloutRevisions(ctx context.Context, obj runtime.Object) (map[int64]rolloutRevision, error) {
+	owner, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	var labelSelector *metav1.LabelSelector
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		labelSelector = o.Spec.Selector
+	case *appsv1.StatefulSet:
+		labelSelector = o.Spec.Selector
+	case *appsv1.DaemonSet:
+		labelSelector = o.Spec.Selector
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector of %s: %w", owner.GetName(), err)
+	}
+	listOptions := metav1.ListOptions{LabelSelector: selector.String()}
+	revisions := map[int64]rolloutRevision{}
+
+	if _, ok := obj.(*appsv1.Deployment); ok {
+		rsList, err := k.clientset.AppsV1().ReplicaSets(owner.GetNamespace()).List(ctx, listOptions)
+		if err != nil {
+			return nil, err
+		}
+		for i := range rsList.Items {
+			rs := &rsList.Items[i]
+			if !metav1.IsControlledBy(rs, owner) {
+				continue
+			}
+			number, err := deploymentutil.Revision(rs)
+			if err != nil {
+				continue
+			}
+			revisions[number] = rolloutRevision{
+				number:      number,
+				changeCause: rs.Annotations[changeCauseAnnotation],
+				template:    rs.Spec.Template,
+				annotations: rs.Annotations,
+			}
+		}
+		return revisions, nil
+	}
+
+	crList, err := k.clientset.AppsV1().ControllerRevisions(owner.GetNamespace()).List(ctx, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	for i := range crList.Items {
+		cr := &crList.Items[i]
+		if !metav1.IsControlledBy(cr, owner) {
+			continue
+		}
+		template, err := applyControllerRevision(obj, cr)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse controller revision %s: %w", cr.Name, err)
+		}
+		revisions[cr.Revision] = rolloutRevision{
+			number:      cr.Revision,
+			changeCause: cr.Annotations[changeCauseAnnotation],
+			template:    template,
+			patch:       cr.Data.Raw,
+		}
+	}
+	return revisions, nil
+}
+
+// applyControllerRevision returns the pod template of a StatefulSet or DaemonSet restored by a controller revision.
+func applyControllerRevision(obj runtime.Object, cr *appsv1.ControllerRevision) (corev1.PodTemplateSpec, error) {
+	original, err := json.Marshal(obj)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+	patched, err := strategicpatch.StrategicMergePatch(original, cr.Data.Raw, obj)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+	restored := obj.DeepCopyObject()
+	if err := json.Unmarshal(patched, restored); err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+	return rolloutTemplate(restored)
+}
+
+func rolloutTemplate(obj runtime.Object) (corev1.PodTemplateSpec, error) {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return o.Spec.Template, nil
+	case *appsv1.StatefulSet:
+		return o.Spec.Template, nil
+	case *appsv1.DaemonSet:
+		return o.Spec.Template, nil
+	}
+	return corev1.PodTemplateSpec{}, fmt.Errorf("unexpected rollout object %T", obj)
+}
+
+// equalIgnoreHash reports whether two pod templates are equal ignoring the pod-template-hash label ReplicaSets add.
+func equalIgnoreHash(a, b corev1.PodTemplateSpec) bool {
+	a, b = *a.DeepCopy(), *b.DeepCopy()
+	delete(a.Labels, rolloutHistoryTemplateHash)
+	delete(b.Labels, rolloutHistoryTemplateHash)
+	return apiequality.Semantic.DeepEqual(a, b)
+}
+
+// rollbackSkippedAnnotations are kept from the Deployment rather than copied from the ReplicaSet of the revision
+// rolled back to, like kubectl does.
+var rollbackSkippedAnnotations = map[string]bool{
+	corev1.LastAppliedConfigAnnotation:       true,
+	deploymentutil.RevisionAnnotation:        true,
+	deploymentutil.RevisionHistoryAnnotation: true,
+	deploymentutil.DesiredReplicasAnnotation: true,
+	deploymentutil.MaxReplicasAnnotation:     true,
+	appsv1.DeprecatedRollbackTo:              true,
+}
+
+// deploymentRollbackPatch returns the JSON patch replacing the pod template and the annotations of a Deployment with
+// the ones of a revision.
+func deploymentRollbackPatch(deploy *appsv1.Deployment, rev rolloutRevision) ([]byte, error) {
+	template := *rev.template.DeepCopy()
+	delete(template.Labels, rolloutHistoryTemplateHash)
+	annotations := map[string]string{}
+	for key := range rollbackSkippedAnnotations {
+		if value, ok := deploy.Annotations[key]; ok {
+			annotations[key] = value
+		}
+	}
+	for key, value := range rev.annotations {
+		if !rollbackSkippedAnnotations[key] {
+			annotations[key] = value
+		}
+	}
+	return json.Marshal([]interface{}{
+		map[string]interface{}{"op": "replace", "path": "/spec/template", "value": template},
+		map[string]interface{}{"op": "replace", "path": "/metadata/annotations", "value": annotations},
+	})
+}
+
+// RolloutRestart triggers a rolling restart by stamping the restartedAt annotation on the pod template, like kubectl does.
+func (k *Kubernetes) RolloutRestart(r common.Request) (string, error) {
+	obj, err := k.getRolloutObject(r.Context, r.Kind, r.Namespace, r.Name)
+	if err != nil {
+		return "", err
+	}
+	if deploy, ok := obj.(*appsv1.Deployment); ok && deploy.Spec.Paused {
+		return "", fmt.Errorf("can't restart paused deployment (run rollout resume first)")
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`, restartedAtAnnotation, time.Now().Format(time.RFC3339))
+	if err := k.patchRolloutObject(r.Context, r.Kind, r.Namespace, r.Name, []byte(patch)); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s/%s restarted", r.Kind, r.Namespace, r.Name), nil
+}
+
+// RolloutPause pauses a Deployment rollout, other kinds do not support pausing.
+func (k *Kubernetes) RolloutPause(r common.Request) (string, error) {
+	return k.setDeploymentPaused(r, true)
+}
+
+// RolloutResume resumes a paused Deployment rollout.
+func (k *Kubernetes) RolloutResume(r common.Request) (string, error) {
+	return k.setDeploymentPaused(r, false)
+}
+
+func (k *Kubernetes) setDeploymentPaused(r common.Request, paused bool) (string, error) {
+	if r.Kind != "Deployment" {
+		return "", fmt.Errorf("%s does not support pausing, only Deployment does", r.Kind)
+	}
+	deploy, err := k.clientset.AppsV1().Deployments(r.Namespace).Get(r.Context, r.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	action := "paused"
+	if !paused {
+		action = "resumed"
+	}
+	if deploy.Spec.Paused == paused {
+		return fmt.Sprintf("Deployment %s/%s is already %s", r.Namespace, r.Name, action), nil
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"paused":%t}}`, paused)
+	if err := k.patchRolloutObject(r.Context, r.Kind, r.Namespace, r.Name, []byte(patch)); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Deployment %s/%s %s", r.Namespace, r.Name, action), nil
+}
+
+func rolloutGroupKind(kind string) (schema.GroupKind, error) {
+	switch kind {
+	case "Deployment", "StatefulSet", "DaemonSet":
+		return appsv1.SchemeGroupVersion.WithKind(kind).GroupKind(), nil
+	}
+	return schema.GroupKind{}, fmt.Errorf("rollout is not supported for kind %s, expected Deployment, StatefulSet or DaemonSet", kind)
+}
+
+func (k *Kubernetes) getRolloutObject(ctx context.Context, kind, namespace, name string) (runtime.Object, error) {
+	var obj runtime.Object
+	var err error
+	switch kind {
+	case "Deployment":
+		obj, err = k.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	case "StatefulSet":
+		obj, err = k.clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	case "DaemonSet":
+		obj, err = k.clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	default:
+		_, err = rolloutGroupKind(kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// typed clients leave the TypeMeta empty, the kubectl helpers rely on it
+	obj.GetObjectKind().SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind(kind))
+	return obj, nil
+}
+
+func (k *Kubernetes) patchRolloutObject(ctx context.Context, kind, namespace, name string, patch []byte) error {
+	opts := metav1.PatchOptions{FieldManager: common.ProjectName}
+	var err error
+	switch kind {
+	case "Deployment":
+		_, err = k.clientset.AppsV1().Deployments(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, opts)
+	case "StatefulSet":
+		_, err = k.clientset.AppsV1().StatefulSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, opts)
+	case "DaemonSet":
+		_, err = k.clientset.AppsV1().DaemonSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, opts)
+	default:
+		_, err = rolloutGroupKind(kind)
+	}
+	return err
+}
+
+// podTemplateDiff returns a unified diff of the pod templates of two revisions.
+func podTemplateDiff(from, to corev1.PodTemplateSpec, fromRevision, toRevision int64) (string, error) {
+	fromYAML, err := podTemplateYAML(from)
+	if err != nil {
+		return "", err
+	}
+	toYAML, err := podTemplateYAML(to)
+	if err != nil {
+		return "", err
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(fromYAML),
+		B:        difflib.SplitLines(toYAML),
+		FromFile: fmt.Sprintf("revision %d", fromRevision),
+		ToFile:   fmt.Sprintf("revision %d", toRevision),
+		Context:  3,
+	})
+}
+
+func podTemplateYAML(template corev1.PodTemplateSpec) (string, error) {
+	template = *template.DeepCopy()
+	// the hash label differs for every ReplicaSet and only adds noise to the diff
+	delete(template.Labels, rolloutHistoryTemplateHash)
+	data, err := yaml.Marshal(template)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
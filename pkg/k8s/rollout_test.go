@@ -0,0 +1,253 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func newRolloutDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "web",
+			Namespace:  "default",
+			UID:        types.UID("web-uid"),
+			Generation: 2,
+			Annotations: map[string]string{
+				"deployment.kubernetes.io/revision": "2",
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(int32(1)),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Template: newRolloutPodTemplate("nginx:1.27"),
+		},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			Replicas:           1,
+			UpdatedReplicas:    1,
+			AvailableReplicas:  1,
+		},
+	}
+}
+
+func newRolloutPodTemplate(image string) v1.PodTemplateSpec {
+	return v1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: "web", Image: image}},
+		},
+	}
+}
+
+func newRolloutReplicaSet(name, revision, changeCause, image string) *appsv1.ReplicaSet {
+	template := newRolloutPodTemplate(image)
+	template.Labels["pod-template-hash"] = name
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID(name),
+			Labels:    map[string]string{"app": "web"},
+			Annotations: map[string]string{
+				"deployment.kubernetes.io/revision": revision,
+				"kubernetes.io/change-cause":        changeCause,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: types.UID("web-uid"), Controller: ptr.To(true)},
+			},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Replicas: ptr.To(int32(0)),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Template: template,
+		},
+	}
+}
+
+func TestRolloutStatus(t *testing.T) {
+	r := common.Request{Context: context.Background(), Kind: "Deployment", Namespace: "default", Name: "web"}
+
+	t.Run("Rolled out deployment", func(t *testing.T) {
+		k := newTestKubernetes(fake.NewSimpleClientset(newRolloutDeployment()), nil)
+		res, err := k.RolloutStatus(r, 0, false, 0)
+		assert.NoError(t, err)
+		assert.Contains(t, res, `deployment "web" successfully rolled out`)
+	})
+
+	t.Run("Rollout in progress", func(t *testing.T) {
+		deploy := newRolloutDeployment()
+		deploy.Status.UpdatedReplicas = 0
+		k := newTestKubernetes(fake.NewSimpleClientset(deploy), nil)
+		res, err := k.RolloutStatus(r, 0, false, 0)
+		assert.NoError(t, err)
+		assert.Contains(t, res, "Waiting for deployment")
+	})
+
+	t.Run("Unsupported kind", func(t *testing.T) {
+		k := newTestKubernetes(fake.NewSimpleClientset(), nil)
+		_, err := k.RolloutStatus(common.Request{Context: context.Background(), Kind: "Pod"}, 0, false, 0)
+		assert.ErrorContains(t, err, "rollout is not supported for kind Pod")
+	})
+}
+
+func TestRolloutHistory(t *testing.T) {
+	k := newTestKubernetes(fake.NewSimpleClientset(
+		newRolloutDeployment(),
+		newRolloutReplicaSet("web-6d4cf56db6", "1", "initial release", "nginx:1.26"),
+		newRolloutReplicaSet("web-7b8f9c5d44", "2", "bump nginx", "nginx:1.27"),
+	), nil)
+
+	res, err := k.RolloutHistory(common.Request{Context: context.Background(), Kind: "Deployment", Namespace: "default", Name: "web"}, 0)
+	assert.NoError(t, err)
+	assert.Contains(t, res, "initial release")
+	assert.Contains(t, res, "bump nginx")
+	assert.Contains(t, res, "Revision 1 -> 2:")
+	assert.Contains(t, res, "-  - image: nginx:1.26")
+	assert.Contains(t, res, "+  - image: nginx:1.27")
+	assert.NotContains(t, res, "pod-template-hash")
+}
+
+func newRolloutControllerRevision(t *testing.T, name string, revision int64, image string) *appsv1.ControllerRevision {
+	template, err := json.Marshal(newRolloutPodTemplate(image))
+	assert.NoError(t, err)
+	return &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{"app": "web"},
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "web", UID: types.UID("web-uid"), Controller: ptr.To(true)},
+			},
+		},
+		Data:     runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"spec":{"template":%s}}`, strings.Replace(string(template), "{", `{"$patch":"replace",`, 1)))},
+		Revision: revision,
+	}
+}
+
+func TestRolloutHistoryStatefulSet(t *testing.T) {
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: types.UID("web-uid")},
+		Spec: appsv1.StatefulSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Template: newRolloutPodTemplate("nginx:1.27"),
+		},
+	}
+	k := newTestKubernetes(fake.NewSimpleClientset(sts,
+		newRolloutControllerRevision(t, "web-1", 1, "nginx:1.26"),
+		newRolloutControllerRevision(t, "web-2", 2, "nginx:1.27"),
+	), nil)
+	r := common.Request{Context: context.Background(), Kind: "StatefulSet", Namespace: "default", Name: "web"}
+
+	res, err := k.RolloutHistory(r, 2)
+	assert.NoError(t, err)
+	assert.Contains(t, res, "nginx:1.27")
+	assert.Contains(t, res, "Revision 1 -> 2:")
+	assert.Contains(t, res, "-  - image: nginx:1.26")
+
+	res, err = k.RolloutUndo(r, 0)
+	assert.NoError(t, err)
+	assert.Contains(t, res, "rolled back to revision 1")
+	sts, err = k.clientset.AppsV1().StatefulSets("default").Get(context.Background(), "web", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "nginx:1.26", sts.Spec.Template.Spec.Containers[0].Image)
+}
+
+func TestRolloutUndo(t *testing.T) {
+	r := common.Request{Context: context.Background(), Kind: "Deployment", Namespace: "default", Name: "web"}
+	newClientset := func() *fake.Clientset {
+		return fake.NewSimpleClientset(
+			newRolloutDeployment(),
+			newRolloutReplicaSet("web-6d4cf56db6", "1", "initial release", "nginx:1.26"),
+			newRolloutReplicaSet("web-7b8f9c5d44", "2", "bump nginx", "nginx:1.27"),
+		)
+	}
+
+	t.Run("Previous revision", func(t *testing.T) {
+		clientset := newClientset()
+		k := newTestKubernetes(clientset, nil)
+		res, err := k.RolloutUndo(r, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, "Deployment default/web rolled back to revision 1", res)
+
+		deploy, err := clientset.AppsV1().Deployments("default").Get(context.Background(), "web", metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, "nginx:1.26", deploy.Spec.Template.Spec.Containers[0].Image)
+		assert.NotContains(t, deploy.Spec.Template.Labels, "pod-template-hash")
+		assert.Equal(t, "initial release", deploy.Annotations["kubernetes.io/change-cause"])
+		assert.Equal(t, "2", deploy.Annotations["deployment.kubernetes.io/revision"], "the revision annotation is kept from the deployment")
+	})
+
+	t.Run("Current revision is skipped", func(t *testing.T) {
+		k := newTestKubernetes(newClientset(), nil)
+		res, err := k.RolloutUndo(r, 2)
+		assert.NoError(t, err)
+		assert.Contains(t, res, "skipped rollback (current template already matches revision 2)")
+	})
+
+	t.Run("Unknown revision", func(t *testing.T) {
+		k := newTestKubernetes(newClientset(), nil)
+		_, err := k.RolloutUndo(r, 5)
+		assert.ErrorContains(t, err, "unable to find specified revision 5 in history")
+	})
+}
+
+func TestRolloutRestart(t *testing.T) {
+	r := common.Request{Context: context.Background(), Kind: "Deployment", Namespace: "default", Name: "web"}
+
+	t.Run("Stamp restartedAt annotation", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(newRolloutDeployment())
+		k := newTestKubernetes(clientset, nil)
+		_, err := k.RolloutRestart(r)
+		assert.NoError(t, err)
+
+		deploy, err := clientset.AppsV1().Deployments("default").Get(context.Background(), "web", metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, deploy.Spec.Template.Annotations[restartedAtAnnotation])
+	})
+
+	t.Run("Paused deployment", func(t *testing.T) {
+		deploy := newRolloutDeployment()
+		deploy.Spec.Paused = true
+		k := newTestKubernetes(fake.NewSimpleClientset(deploy), nil)
+		_, err := k.RolloutRestart(r)
+		assert.ErrorContains(t, err, "can't restart paused deployment")
+	})
+}
+
+func TestRolloutPauseResume(t *testing.T) {
+	r := common.Request{Context: context.Background(), Kind: "Deployment", Namespace: "default", Name: "web"}
+	clientset := fake.NewSimpleClientset(newRolloutDeployment())
+	k := newTestKubernetes(clientset, nil)
+
+	_, err := k.RolloutPause(r)
+	assert.NoError(t, err)
+	deploy, err := clientset.AppsV1().Deployments("default").Get(context.Background(), "web", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.True(t, deploy.Spec.Paused)
+
+	res, err := k.RolloutPause(r)
+	assert.NoError(t, err)
+	assert.Contains(t, res, "already paused")
+
+	_, err = k.RolloutResume(r)
+	assert.NoError(t, err)
+	deploy, err = clientset.AppsV1().Deployments("default").Get(context.Background(), "web", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.False(t, deploy.Spec.Paused)
+
+	_, err = k.RolloutPause(common.Request{Context: context.Background(), Kind: "StatefulSet", Namespace: "default", Name: "db"})
+	assert.ErrorContains(t, err, "StatefulSet does not support pausing")
+}
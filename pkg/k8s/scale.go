@@ -0,0 +1,130 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	defaultScaleWaitTimeout = 5 * time.Minute
+	scalePollInterval       = 2 * time.Second
+)
+
+// readyReplicasKinds report readyReplicas in their status, other kinds are considered ready once the scale status catches up.
+var readyReplicasKinds = map[string]bool{
+	"Deployment":            true,
+	"StatefulSet":           true,
+	"ReplicaSet":            true,
+	"ReplicationController": true,
+}
+
+// ResourceScale scales any resource that exposes the scale subresource.
+// Without a precondition the replicas are set with a merge patch, with one the scale is updated
+// against the resourceVersion the precondition was checked on so concurrent changes are rejected.
+func (k *Kubernetes) ResourceScale(r common.Request, opts common.ScaleOptions) (string, error) {
+	if opts.Replicas < 0 {
+		return "", fmt.Errorf("replicas must not be negative")
+	}
+	mapping, err := k.resolveKind(r.Kind, r.APIVersion)
+	if err != nil {
+		return "", err
+	}
+	gvk := mapping.GroupVersionKind
+	namespace := utils.NamespaceOrDefault(r.Namespace)
+	client := k.dynamicClient.Resource(mapping.Resource).Namespace(namespace)
+
+	scale, err := client.Get(r.Context, r.Name, metav1.GetOptions{}, "scale")
+	if err != nil {
+		return "", err
+	}
+	current, _, err := unstructured.NestedInt64(scale.Object, "spec", "replicas")
+	if err != nil {
+		return "", err
+	}
+
+	if opts.CurrentReplicas != nil {
+		if current != int64(*opts.CurrentReplicas) {
+			return "", fmt.Errorf("expected %d current replicas, found %d", *opts.CurrentReplicas, current)
+		}
+		if err := unstructured.SetNestedField(scale.Object, int64(opts.Replicas), "spec", "replicas"); err != nil {
+			return "", err
+		}
+		_, err = client.Update(r.Context, scale, metav1.UpdateOptions{FieldManager: common.ProjectName}, "scale")
+	} else {
+		patch := fmt.Sprintf(`{"spec":{"replicas":%d}}`, opts.Replicas)
+		_, err = client.Patch(r.Context, r.Name, types.MergePatchType, []byte(patch), metav1.PatchOptions{FieldManager: common.ProjectName}, "scale")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	msg := fmt.Sprintf("%s %s/%s scaled from %d to %d replicas", gvk.Kind, namespace, r.Name, current, opts.Replicas)
+	if !opts.Wait {
+		return msg, nil
+	}
+
+	timeout := defaultScaleWaitTimeout
+	if opts.Timeout > 0 {
+		timeout = time.Duration(opts.Timeout) * time.Second
+	}
+	var reported int64
+	err = wait.PollUntilContextTimeout(r.Context, scalePollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		done, ready, err := scaleReady(ctx, client, gvk.Kind, r.Name, int64(opts.Replicas))
+		if err == nil && r.Progress != nil && ready > reported && opts.Replicas > 0 {
+			reported = ready
+			r.Progress(int(ready), int(opts.Replicas), fmt.Sprintf("%d/%d replicas ready", ready, opts.Replicas))
+		}
+		return done, err
+	})
+	if wait.Interrupted(err) && r.Context.Err() == nil {
+		return "", fmt.Errorf("%s, but timed out waiting for the replicas to be ready", msg)
+	}
+	if err != nil {
+		return "", err
+	}
+	return msg + ", all replicas are ready", nil
+}
+
+// scaleReady reports whether the controller has observed the new spec and the requested replicas are ready, along
+// with the number of ready replicas.
+func scaleReady(ctx context.Context, client dynamic.ResourceInterface, kind, name string, replicas int64) (bool, int64, error) {
+	if !readyReplicasKinds[kind] {
+		scale, err := client.Get(ctx, name, metav1.GetOptions{}, "scale")
+		if err != nil {
+			return false, 0, err
+		}
+		current, _, err := unstructured.NestedInt64(scale.Object, "status", "replicas")
+		return current == replicas, current, err
+	}
+
+	obj, err := client.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, 0, err
+	}
+	observed, found, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if err != nil {
+		return false, 0, err
+	}
+	if found && observed < obj.GetGeneration() {
+		return false, 0, nil
+	}
+	// zero counts are omitted from the status
+	current, _, err := unstructured.NestedInt64(obj.Object, "status", "replicas")
+	if err != nil {
+		return false, 0, err
+	}
+	ready, _, err := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+	if err != nil {
+		return false, 0, err
+	}
+	return current == replicas && ready == replicas, ready, nil
+}
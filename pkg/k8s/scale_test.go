@@ -0,0 +1,114 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func newScaleDynamicClient() *dynamicfake.FakeDynamicClient {
+	deploy := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"namespace": "default",
+				"name":      "web",
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(2),
+			},
+		},
+	}
+	return dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), deploy)
+}
+
+func scaleReplicas(t *testing.T, client *dynamicfake.FakeDynamicClient) int64 {
+	gvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	obj, err := client.Resource(gvr).Namespace("default").Get(context.Background(), "web", metav1.GetOptions{})
+	assert.NoError(t, err)
+	replicas, _, err := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	assert.NoError(t, err)
+	return replicas
+}
+
+func TestResourceScale(t *testing.T) {
+	r := common.Request{Context: context.Background(), Kind: "deployment", Namespace: "default", Name: "web"}
+
+	t.Run("Scale without precondition", func(t *testing.T) {
+		dynamicClient := newScaleDynamicClient()
+		k := newTestKubernetes(fake.NewSimpleClientset(), dynamicClient)
+		res, err := k.ResourceScale(r, common.ScaleOptions{Replicas: 5})
+		assert.NoError(t, err)
+		assert.Equal(t, "Deployment default/web scaled from 2 to 5 replicas", res)
+		assert.Equal(t, int64(5), scaleReplicas(t, dynamicClient))
+	})
+
+	t.Run("Scale with matching precondition", func(t *testing.T) {
+		dynamicClient := newScaleDynamicClient()
+		k := newTestKubernetes(fake.NewSimpleClientset(), dynamicClient)
+		_, err := k.ResourceScale(r, common.ScaleOptions{Replicas: 0, CurrentReplicas: ptr.To(int32(2))})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), scaleReplicas(t, dynamicClient))
+	})
+
+	t.Run("Reject mismatched precondition", func(t *testing.T) {
+		dynamicClient := newScaleDynamicClient()
+		k := newTestKubernetes(fake.NewSimpleClientset(), dynamicClient)
+		_, err := k.ResourceScale(r, common.ScaleOptions{Replicas: 3, CurrentReplicas: ptr.To(int32(1))})
+		assert.ErrorContains(t, err, "expected 1 current replicas, found 2")
+		assert.Equal(t, int64(2), scaleReplicas(t, dynamicClient))
+	})
+
+	t.Run("Reject negative replicas", func(t *testing.T) {
+		k := newTestKubernetes(fake.NewSimpleClientset(), newScaleDynamicClient())
+		_, err := k.ResourceScale(r, common.ScaleOptions{Replicas: -1})
+		assert.ErrorContains(t, err, "must not be negative")
+	})
+}
+
+func TestScaleReady(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	newClient := func(status map[string]interface{}) *dynamicfake.FakeDynamicClient {
+		return dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"namespace":  "default",
+					"name":       "web",
+					"generation": int64(3),
+				},
+				"status": status,
+			},
+		})
+	}
+
+	cases := []struct {
+		name   string
+		status map[string]interface{}
+		ready  bool
+	}{
+		{"Ready", map[string]interface{}{"observedGeneration": int64(3), "replicas": int64(2), "readyReplicas": int64(2)}, true},
+		{"Generation not observed", map[string]interface{}{"observedGeneration": int64(2), "replicas": int64(2), "readyReplicas": int64(2)}, false},
+		{"Replicas not ready", map[string]interface{}{"observedGeneration": int64(3), "replicas": int64(2), "readyReplicas": int64(1)}, false},
+		{"Old replicas still terminating", map[string]interface{}{"observedGeneration": int64(3), "replicas": int64(3), "readyReplicas": int64(2)}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := newClient(c.status).Resource(gvr).Namespace("default")
+			ready, _, err := scaleReady(context.Background(), client, "Deployment", "web", 2)
+			assert.NoError(t, err)
+			assert.Equal(t, c.ready, ready)
+		})
+	}
+}
@@ -2,11 +2,9 @@ package k8s
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
-	"github.com/wenhuwang/mcp-k8s-eye/pkg/utils"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
@@ -14,6 +12,10 @@ import (
 
 // AnalyzeServices analyzes the services and returns a list of failures.
 func (k *Kubernetes) AnalyzeService(ctx context.Context, namespace string) (string, error) {
+	return marshalResults(k.analyzeService(ctx, common.Request{Namespace: namespace}))
+}
+
+func (k *Kubernetes) analyzeService(ctx context.Context, r common.Request) ([]common.Result, error) {
 	kind := "Service"
 	apiDoc := K8sApiReference{
 		Kind: kind,
@@ -24,9 +26,9 @@ func (k *Kubernetes) AnalyzeService(ctx context.Context, namespace string) (stri
 		OpenapiSchema: k.openapiSchema,
 	}
 
-	epList, err := k.clientset.CoreV1().Endpoints(namespace).List(ctx, metav1.ListOptions{})
+	epList, err := k.clientset.CoreV1().Endpoints(r.Namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	var preAnalysis = map[string]common.PreAnalysis{}
@@ -40,14 +42,18 @@ func (k *Kubernetes) AnalyzeService(ctx context.Context, namespace string) (stri
 			}
 			svc, err := k.clientset.CoreV1().Services(ep.Namespace).Get(ctx, ep.Name, metav1.GetOptions{})
 			if err != nil {
-				return "", err
+				return nil, err
 			}
 
 			for k, v := range svc.Spec.Selector {
 				doc := apiDoc.GetApiDocV2("spec.selector")
 				failures = append(failures, common.Failure{
+					ID:            "SERVICE_NO_ENDPOINTS",
+					Severity:      common.SeverityCritical,
 					Text:          fmt.Sprintf("Service has no endpoints, unexpected label: %s=%s", k, v),
 					KubernetesDoc: doc,
+					FieldPath:     "spec.selector",
+					Remediation:   &common.Remediation{Text: fmt.Sprintf("Make the selector match the labels of the pods backing the service, no running pod has the label %s=%s", k, v)},
 				})
 			}
 
@@ -68,8 +74,12 @@ func (k *Kubernetes) AnalyzeService(ctx context.Context, namespace string) (stri
 			if count > 0 {
 				doc := apiDoc.GetApiDocV2("subsets.notReadyAddresses")
 				failures = append(failures, common.Failure{
+					ID:            "SERVICE_ENDPOINTS_NOT_READY",
+					Severity:      common.SeverityWarning,
 					Text:          fmt.Sprintf("Service has not ready endpoints, pods: %s, unexpected: %d", pods, count),
 					KubernetesDoc: doc,
+					FieldPath:     "subsets.notReadyAddresses",
+					Remediation:   &common.Remediation{Text: "Analyze the not ready pods, they do not receive traffic until their readiness probe passes"},
 				})
 			}
 		}
@@ -79,13 +89,15 @@ func (k *Kubernetes) AnalyzeService(ctx context.Context, namespace string) (stri
 			FieldSelector: "involvedObject.name=" + ep.Name,
 		})
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
 		for _, event := range events.Items {
 			if event.Type != "Normal" {
 				failures = append(failures, common.Failure{
-					Text: fmt.Sprintf("Service %s/%s has event: %s", ep.Namespace, ep.Name, event.Message),
+					ID:       "SERVICE_WARNING_EVENT",
+					Severity: common.SeverityWarning,
+					Text:     fmt.Sprintf("Service %s/%s has event: %s", ep.Namespace, ep.Name, event.Message),
 				})
 			}
 		}
@@ -97,24 +109,5 @@ func (k *Kubernetes) AnalyzeService(ctx context.Context, namespace string) (stri
 		}
 	}
 
-	var results []common.Result
-	for key, value := range preAnalysis {
-		analysis := common.Result{
-			Kind:  kind,
-			Name:  key,
-			Error: value.FailureDetails,
-		}
-
-		parent, found := utils.GetParent(k.clientset, value.Endpoint.ObjectMeta)
-		if found {
-			analysis.ParentObject = parent
-		}
-		results = append(results, analysis)
-	}
-
-	jsonData, err := json.Marshal(results)
-	if err != nil {
-		return "", err
-	}
-	return string(jsonData), nil
+	return k.preAnalysisResults(ctx, kind, preAnalysis, func(p common.PreAnalysis) metav1.ObjectMeta { return p.Endpoint.ObjectMeta }), nil
 }
@@ -1,7 +1,7 @@
 package k8s
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 
 	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
@@ -14,6 +14,10 @@ import (
 )
 
 func (k *Kubernetes) AnalyzeStatefulSet(r common.Request) (string, error) {
+	return marshalResults(k.analyzeStatefulSet(r.Context, r))
+}
+
+func (k *Kubernetes) analyzeStatefulSet(ctx context.Context, r common.Request) ([]common.Result, error) {
 	kind := "StatefulSet"
 	apiDoc := K8sApiReference{
 		Kind: kind,
@@ -24,9 +28,9 @@ func (k *Kubernetes) AnalyzeStatefulSet(r common.Request) (string, error) {
 		OpenapiSchema: k.openapiSchema,
 	}
 
-	stsList, err := k.clientset.AppsV1().StatefulSets(r.Namespace).List(r.Context, metav1.ListOptions{})
+	stsList, err := k.clientset.AppsV1().StatefulSets(r.Namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	var preAnalysis = map[string]common.PreAnalysis{}
 	for _, sts := range stsList.Items {
@@ -34,56 +38,87 @@ func (k *Kubernetes) AnalyzeStatefulSet(r common.Request) (string, error) {
 
 		svcName := sts.Spec.ServiceName
 		if svcName != "" {
-			_, err := k.clientset.CoreV1().Services(sts.Namespace).Get(r.Context, svcName, metav1.GetOptions{})
+			_, err := k.clientset.CoreV1().Services(sts.Namespace).Get(ctx, svcName, metav1.GetOptions{})
 			if err != nil {
 				doc := apiDoc.GetApiDocV2("spec.serviceName")
 				failures = append(failures, common.Failure{
+					ID:       "STATEFULSET_SERVICE_NOT_FOUND",
+					Severity: common.SeverityWarning,
 					Text: fmt.Sprintf(
 						"StatefulSet uses the service %s/%s which does not exist",
 						sts.Namespace, svcName,
 					),
-					KubernetesDoc: doc,
+					KubernetesDoc:  doc,
+					FieldPath:      "spec.serviceName",
+					RelatedObjects: []common.ObjectReference{{Kind: "Service", Namespace: sts.Namespace, Name: svcName}},
+					Remediation:    &common.Remediation{Text: fmt.Sprintf("Create the headless service %s, the pods get their stable DNS names from it", svcName)},
 				})
 			}
 		}
 
-		if len(sts.Spec.VolumeClaimTemplates) > 0 {
-			for _, volumeClaimTemplate := range sts.Spec.VolumeClaimTemplates {
-				_, err := k.clientset.CoreV1().PersistentVolumeClaims(sts.Namespace).Get(r.Context, volumeClaimTemplate.Name, metav1.GetOptions{})
+		// the controller names the claims of each replica <template>-<statefulset>-<ordinal>, the ordinals start at
+		// spec.ordinals.start
+		replicas := int32(1)
+		if sts.Spec.Replicas != nil {
+			replicas = *sts.Spec.Replicas
+		}
+		start := int32(0)
+		if sts.Spec.Ordinals != nil {
+			start = sts.Spec.Ordinals.Start
+		}
+		for _, volumeClaimTemplate := range sts.Spec.VolumeClaimTemplates {
+			for i := start; i < start+replicas; i++ {
+				pvcName := fmt.Sprintf("%s-%s-%d", volumeClaimTemplate.Name, sts.Name, i)
+				_, err := k.clientset.CoreV1().PersistentVolumeClaims(sts.Namespace).Get(ctx, pvcName, metav1.GetOptions{})
 				if err != nil {
 					doc := apiDoc.GetApiDocV2("spec.volumeClaimTemplates")
 					failures = append(failures, common.Failure{
+						ID:       "STATEFULSET_PVC_NOT_FOUND",
+						Severity: common.SeverityWarning,
 						Text: fmt.Sprintf("StatefulSet uses the pvc %s/%s which does not exist",
-							sts.Namespace, volumeClaimTemplate.Name,
+							sts.Namespace, pvcName,
 						),
-						KubernetesDoc: doc,
+						KubernetesDoc:  doc,
+						FieldPath:      "spec.volumeClaimTemplates",
+						RelatedObjects: []common.ObjectReference{{Kind: "PersistentVolumeClaim", Namespace: sts.Namespace, Name: pvcName}},
+						Remediation:    &common.Remediation{Text: "Analyze the storage of the namespace, the claim is created with the pod and its provisioning may have failed"},
 					})
 				}
 			}
 		}
 
 		if sts.Spec.Replicas != nil && *(sts.Spec.Replicas) != sts.Status.AvailableReplicas {
-			for i := int32(0); i < *(sts.Spec.Replicas); i++ {
+			for i := start; i < start+*(sts.Spec.Replicas); i++ {
 				podName := sts.Name + "-" + fmt.Sprint(i)
-				pod, err := k.clientset.CoreV1().Pods(sts.Namespace).Get(r.Context, podName, metav1.GetOptions{})
+				pod, err := k.clientset.CoreV1().Pods(sts.Namespace).Get(ctx, podName, metav1.GetOptions{})
 				if err != nil {
-					if errors.IsNotFound(err) && i == 0 {
-						evt, err := utils.FetchLatestEvent(k.clientset, sts.Namespace, sts.Name)
+					if errors.IsNotFound(err) && i == start {
+						evt, err := utils.FetchLatestEvent(ctx, k.clientset, sts.Namespace, sts.Name)
 						if err != nil || evt == nil || evt.Type == "Normal" {
 							failures = append(failures, common.Failure{
-								Text: fmt.Sprintf("StatefulSet has %d replicas, but only 0 pods are running", *(sts.Spec.Replicas)),
+								ID:        "STATEFULSET_NO_PODS",
+								Severity:  common.SeverityCritical,
+								Text:      fmt.Sprintf("StatefulSet has %d replicas, but only 0 pods are running", *(sts.Spec.Replicas)),
+								FieldPath: "status.availableReplicas",
 							})
 							break
 						}
 						failures = append(failures, common.Failure{
-							Text: evt.Message,
+							ID:          "STATEFULSET_POD_CREATE_FAILED",
+							Severity:    common.SeverityCritical,
+							Text:        evt.Message,
+							Remediation: &common.Remediation{Text: fmt.Sprintf("Fix the %s reported by the statefulset controller", evt.Reason)},
 						})
 					}
 					break
 				}
 				if pod.Status.Phase != corev1.PodRunning {
 					failures = append(failures, common.Failure{
-						Text: fmt.Sprintf("StatefulSet pod %s/%s is not in Running state", sts.Namespace, podName),
+						ID:             "STATEFULSET_POD_NOT_RUNNING",
+						Severity:       common.SeverityWarning,
+						Text:           fmt.Sprintf("StatefulSet pod %s/%s is not in Running state", sts.Namespace, podName),
+						RelatedObjects: []common.ObjectReference{{Kind: "Pod", Namespace: sts.Namespace, Name: podName}},
+						Remediation:    &common.Remediation{Text: "Analyze the pod, an ordered statefulset does not start the next pod until this one is running"},
 					})
 					break
 				}
@@ -97,23 +132,5 @@ func (k *Kubernetes) AnalyzeStatefulSet(r common.Request) (string, error) {
 		}
 	}
 
-	results := make([]common.Result, 0)
-	for key, value := range preAnalysis {
-		analysis := common.Result{
-			Kind:  kind,
-			Name:  key,
-			Error: value.FailureDetails,
-		}
-		parent, found := utils.GetParent(k.clientset, value.StatefulSet.ObjectMeta)
-		if found {
-			analysis.ParentObject = parent
-		}
-		results = append(results, analysis)
-	}
-
-	jsonData, err := json.Marshal(results)
-	if err != nil {
-		return "", err
-	}
-	return string(jsonData), nil
+	return k.preAnalysisResults(ctx, kind, preAnalysis, func(p common.PreAnalysis) metav1.ObjectMeta { return p.StatefulSet.ObjectMeta }), nil
 }
@@ -83,7 +83,45 @@ func TestAnalyzeStatefulSetWithoutPVC(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	assert.Contains(t, result, "StatefulSet uses the pvc default/test-pvc which does not exist")
+	assert.Contains(t, result, "StatefulSet uses the pvc default/test-pvc-test-0 which does not exist")
+}
+
+func TestAnalyzeStatefulSetWithOrdinalsStart(t *testing.T) {
+	replicas := int32(2)
+	clientset := fake.NewSimpleClientset(
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "default",
+			},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: &replicas,
+				Ordinals: &appsv1.StatefulSetOrdinals{Start: 5},
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "data",
+						},
+					},
+				},
+			},
+			Status: appsv1.StatefulSetStatus{
+				AvailableReplicas: 2,
+			},
+		},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-test-5", Namespace: "default"}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-test-6", Namespace: "default"}},
+	)
+	k := newTestKubernetes(clientset, nil)
+	request := common.Request{
+		Context:   context.Background(),
+		Namespace: "default",
+	}
+	result, err := k.AnalyzeStatefulSet(request)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, "[]", result)
 }
 
 func TestAnalyzeStatefulSetNamespaceFiltering(t *testing.T) {
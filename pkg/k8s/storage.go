@@ -0,0 +1,423 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/utils"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+	// pvcUsageThreshold is the used fraction of a volume from which a PVC is reported as near capacity
+	pvcUsageThreshold = 0.9
+)
+
+// volumeUsage is the usage of a PVC backed volume as reported by the kubelet summary API.
+type volumeUsage struct {
+	CapacityBytes uint64
+	UsedBytes     uint64
+}
+
+// kubeletSummary is the subset of the kubelet /stats/summary response needed for volume usage.
+type kubeletSummary struct {
+	Pods []struct {
+		Volumes []struct {
+			CapacityBytes *uint64 `json:"capacityBytes"`
+			UsedBytes     *uint64 `json:"usedBytes"`
+			PVCRef        *struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"pvcRef"`
+		} `json:"volume"`
+	} `json:"pods"`
+}
+
+// AnalyzeStorage analyzes the PersistentVolumeClaims of a namespace together with the PersistentVolumes,
+// StorageClasses and VolumeAttachments backing them, and returns a list of failures.
+func (k *Kubernetes) AnalyzeStorage(r common.Request) (string, error) {
+	return marshalResults(k.analyzeStorage(r.Context, r))
+}
+
+func (k *Kubernetes) analyzeStorage(ctx context.Context, r common.Request) ([]common.Result, error) {
+	apiDoc := K8sApiReference{
+		Kind: "PersistentVolumeClaim",
+		ApiVersion: schema.GroupVersion{
+			Group:   "",
+			Version: "v1",
+		},
+		OpenapiSchema: k.openapiSchema,
+	}
+
+	pvcList, err := k.clientset.CoreV1().PersistentVolumeClaims(r.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// the cluster-scoped objects are read once per cluster scan
+	pvList, firstPVList, err := clusterList(ctx, "persistentvolumes", func() (*v1.PersistentVolumeList, error) {
+		return k.clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, err
+	}
+	scList, _, err := clusterList(ctx, "storageclasses", func() (*storagev1.StorageClassList, error) {
+		return k.clientset.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, err
+	}
+	vaList, _, err := clusterList(ctx, "volumeattachments", func() (*storagev1.VolumeAttachmentList, error) {
+		return k.clientset.StorageV1().VolumeAttachments().List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, err
+	}
+	podList, err := k.clientset.CoreV1().Pods(r.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	storageClasses := map[string]storagev1.StorageClass{}
+	var defaultClasses []string
+	for _, sc := range scList.Items {
+		storageClasses[sc.Name] = sc
+		if sc.Annotations[defaultStorageClassAnnotation] == "true" {
+			defaultClasses = append(defaultClasses, sc.Name)
+		}
+	}
+	pvs := map[string]v1.PersistentVolume{}
+	for _, pv := range pvList.Items {
+		pvs[pv.Name] = pv
+	}
+	// pods using each claim, keyed by namespace/name
+	claimPods := map[string][]v1.Pod{}
+	for _, pod := range podList.Items {
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil {
+				key := fmt.Sprintf("%s/%s", pod.Namespace, volume.PersistentVolumeClaim.ClaimName)
+				claimPods[key] = append(claimPods[key], pod)
+			}
+		}
+	}
+	usage := k.pvcUsage(ctx, claimPods)
+
+	results := make([]common.Result, 0)
+
+	for _, pvc := range pvcList.Items {
+		var failures []common.Failure
+		key := fmt.Sprintf("%s/%s", pvc.Namespace, pvc.Name)
+
+		switch pvc.Status.Phase {
+		case v1.ClaimPending:
+			text := "PVC is Pending"
+			evt, err := utils.FetchLatestEvent(ctx, k.clientset, pvc.Namespace, pvc.Name)
+			if err == nil && evt != nil && evt.Message != "" {
+				text = fmt.Sprintf("PVC is Pending, %s: %s", evt.Reason, evt.Message)
+			}
+			failures = append(failures, common.Failure{
+				ID:          "PVC_PENDING",
+				Severity:    common.SeverityCritical,
+				Text:        text,
+				FieldPath:   "status.phase",
+				Remediation: &common.Remediation{Text: "Fix the provisioning error of the event, or create a PersistentVolume matching the claim when volumes are provisioned statically"},
+			})
+		case v1.ClaimLost:
+			failures = append(failures, common.Failure{
+				ID:             "PVC_LOST",
+				Severity:       common.SeverityCritical,
+				Text:           fmt.Sprintf("PVC lost its PersistentVolume %s", pvc.Spec.VolumeName),
+				FieldPath:      "status.phase",
+				RelatedObjects: []common.ObjectReference{{Kind: "PersistentVolume", Name: pvc.Spec.VolumeName}},
+				Remediation:    &common.Remediation{Text: "Restore the PersistentVolume from a backup, or re-create the claim to provision a new volume"},
+			})
+		}
+
+		if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != "" {
+			if _, ok := storageClasses[*pvc.Spec.StorageClassName]; !ok {
+				failures = append(failures, common.Failure{
+					ID:             "PVC_STORAGECLASS_NOT_FOUND",
+					Severity:       common.SeverityCritical,
+					Text:           fmt.Sprintf("PVC uses the StorageClass %s which does not exist", *pvc.Spec.StorageClassName),
+					KubernetesDoc:  apiDoc.GetApiDocV2("spec.storageClassName"),
+					FieldPath:      "spec.storageClassName",
+					RelatedObjects: []common.ObjectReference{{Kind: "StorageClass", Name: *pvc.Spec.StorageClassName}},
+					Remediation:    &common.Remediation{Text: "Re-create the claim with an existing StorageClass, the class of a claim cannot be changed"},
+				})
+			}
+		} else if pvc.Spec.StorageClassName == nil && pvc.Spec.VolumeName == "" && pvc.Status.Phase == v1.ClaimPending {
+			if len(defaultClasses) == 0 {
+				failures = append(failures, common.Failure{
+					ID:            "PVC_NO_DEFAULT_STORAGECLASS",
+					Severity:      common.SeverityCritical,
+					Text:          "PVC does not set a StorageClass and the cluster has no default StorageClass",
+					KubernetesDoc: apiDoc.GetApiDocV2("spec.storageClassName"),
+					FieldPath:     "spec.storageClassName",
+					Remediation:   &common.Remediation{Text: "Mark a StorageClass as default with the storageclass.kubernetes.io/is-default-class annotation, or set the class on the claim"},
+				})
+			} else if len(defaultClasses) > 1 {
+				sort.Strings(defaultClasses)
+				failures = append(failures, common.Failure{
+					ID:          "PVC_MULTIPLE_DEFAULT_STORAGECLASSES",
+					Severity:    common.SeverityWarning,
+					Text:        fmt.Sprintf("PVC does not set a StorageClass and the cluster has multiple default StorageClasses: %s", strings.Join(defaultClasses, ", ")),
+					FieldPath:   "spec.storageClassName",
+					Remediation: &common.Remediation{Text: "Keep the default annotation on one StorageClass only"},
+				})
+			}
+		}
+
+		if pv, ok := pvs[pvc.Spec.VolumeName]; ok {
+			for _, mode := range pvc.Spec.AccessModes {
+				if !hasAccessMode(pv.Spec.AccessModes, mode) {
+					failures = append(failures, common.Failure{
+						ID:             "PVC_ACCESS_MODE_MISMATCH",
+						Severity:       common.SeverityWarning,
+						Text:           fmt.Sprintf("PVC requests the access mode %s which the PersistentVolume %s does not provide", mode, pv.Name),
+						KubernetesDoc:  apiDoc.GetApiDocV2("spec.accessModes"),
+						FieldPath:      "spec.accessModes",
+						RelatedObjects: []common.ObjectReference{{Kind: "PersistentVolume", Name: pv.Name}},
+					})
+				}
+			}
+		}
+
+		failures = append(failures, accessModeConflicts(pvc, claimPods[key])...)
+
+		if u, ok := usage[key]; ok && u.CapacityBytes > 0 && float64(u.UsedBytes) >= pvcUsageThreshold*float64(u.CapacityBytes) {
+			failures = append(failures, common.Failure{
+				ID:       "PVC_NEARLY_FULL",
+				Severity: common.SeverityWarning,
+				Text: fmt.Sprintf("PVC is %d%% full (%s of %s used)", u.UsedBytes*100/u.CapacityBytes,
+					resource.NewQuantity(int64(u.UsedBytes), resource.BinarySI).String(),
+					resource.NewQuantity(int64(u.CapacityBytes), resource.BinarySI).String()),
+				Remediation: &common.Remediation{Text: "Expand the claim if its StorageClass allows volume expansion, or free space on the volume"},
+			})
+		}
+
+		if len(failures) > 0 {
+			results = append(results, common.Result{
+				Kind:  "PersistentVolumeClaim",
+				Name:  key,
+				Error: failures,
+			})
+		}
+	}
+
+	// PersistentVolumes are cluster scoped, only report those claimed from the analyzed namespace. The unclaimed ones
+	// belong to no namespace, they are reported once per cluster scan by the analyzer that read them or when no
+	// namespace is given.
+	reportUnclaimed := r.Namespace == "" || (inClusterScan(ctx) && firstPVList)
+	reported := func(pv v1.PersistentVolume) bool {
+		if pv.Spec.ClaimRef == nil {
+			return reportUnclaimed
+		}
+		return r.Namespace == "" || pv.Spec.ClaimRef.Namespace == r.Namespace
+	}
+
+	for _, pv := range pvList.Items {
+		if !reported(pv) {
+			continue
+		}
+		var failures []common.Failure
+		switch pv.Status.Phase {
+		case v1.VolumeReleased:
+			text := fmt.Sprintf("PersistentVolume is Released with reclaim policy %s, it cannot be bound again until it is cleaned up", pv.Spec.PersistentVolumeReclaimPolicy)
+			if pv.Spec.ClaimRef != nil {
+				text = fmt.Sprintf("PersistentVolume is Released, its claim %s/%s was deleted and the reclaim policy %s keeps it from being bound again",
+					pv.Spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Name, pv.Spec.PersistentVolumeReclaimPolicy)
+			}
+			failures = append(failures, common.Failure{
+				ID:        "PV_RELEASED",
+				Severity:  common.SeverityInfo,
+				Text:      text,
+				FieldPath: "status.phase",
+				Remediation: &common.Remediation{
+					Text:  "Delete the volume once its data is no longer needed, or remove the claim reference so a new claim can bind it",
+					Patch: `{"spec":{"claimRef":null}}`,
+				},
+			})
+		case v1.VolumeFailed:
+			failures = append(failures, common.Failure{
+				ID:          "PV_RECLAIM_FAILED",
+				Severity:    common.SeverityWarning,
+				Text:        fmt.Sprintf("PersistentVolume failed reclamation: %s", pv.Status.Message),
+				FieldPath:   "status.phase",
+				Remediation: &common.Remediation{Text: "Clean up the volume in the storage backend and delete the PersistentVolume"},
+			})
+		}
+		if len(failures) > 0 {
+			results = append(results, common.Result{
+				Kind:  "PersistentVolume",
+				Name:  pv.Name,
+				Error: failures,
+			})
+		}
+	}
+
+	for _, va := range vaList.Items {
+		if va.Spec.Source.PersistentVolumeName == nil {
+			continue
+		}
+		pvName := *va.Spec.Source.PersistentVolumeName
+		// the attachment of a volume that no longer exists is reported like the one of an unclaimed volume
+		pv := pvs[pvName]
+		if !reported(pv) {
+			continue
+		}
+		var failures []common.Failure
+		if va.Status.AttachError != nil {
+			failures = append(failures, common.Failure{
+				ID:             "VOLUME_ATTACH_FAILED",
+				Severity:       common.SeverityCritical,
+				Text:           fmt.Sprintf("Attaching PersistentVolume %s to node %s failed: %s", pvName, va.Spec.NodeName, va.Status.AttachError.Message),
+				FieldPath:      "status.attachError",
+				RelatedObjects: []common.ObjectReference{{Kind: "PersistentVolume", Name: pvName}, {Kind: "Node", Name: va.Spec.NodeName}},
+				Remediation:    &common.Remediation{Text: "Check the CSI driver logs, a volume that is still attached to another node must be detached first"},
+			})
+		}
+		if va.Status.DetachError != nil {
+			failures = append(failures, common.Failure{
+				ID:             "VOLUME_DETACH_FAILED",
+				Severity:       common.SeverityWarning,
+				Text:           fmt.Sprintf("Detaching PersistentVolume %s from node %s failed: %s", pvName, va.Spec.NodeName, va.Status.DetachError.Message),
+				FieldPath:      "status.detachError",
+				RelatedObjects: []common.ObjectReference{{Kind: "PersistentVolume", Name: pvName}, {Kind: "Node", Name: va.Spec.NodeName}},
+				Remediation:    &common.Remediation{Text: "Check the CSI driver logs and whether the node is still reachable"},
+			})
+		}
+		if len(failures) > 0 {
+			result := common.Result{
+				Kind:  "VolumeAttachment",
+				Name:  va.Name,
+				Error: failures,
+			}
+			if pv.Spec.ClaimRef != nil {
+				result.ParentObject = fmt.Sprintf("PersistentVolumeClaim/%s/%s", pv.Spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Name)
+			}
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// accessModeConflicts flags claims shared by more pods than their access modes allow.
+func accessModeConflicts(pvc v1.PersistentVolumeClaim, pods []v1.Pod) []common.Failure {
+	var failures []common.Failure
+	if hasAccessMode(pvc.Spec.AccessModes, v1.ReadWriteOncePod) && len(pods) > 1 {
+		failures = append(failures, common.Failure{
+			ID:          "PVC_ACCESS_MODE_CONFLICT",
+			Severity:    common.SeverityCritical,
+			Text:        fmt.Sprintf("PVC has access mode ReadWriteOncePod but is used by %d pods: %s", len(pods), podNames(pods)),
+			FieldPath:   "spec.accessModes",
+			Remediation: &common.Remediation{Text: "Only one pod can use a ReadWriteOncePod claim, give every pod its own claim"},
+		})
+	}
+	if len(pvc.Spec.AccessModes) == 1 && pvc.Spec.AccessModes[0] == v1.ReadWriteOnce {
+		nodes := map[string]bool{}
+		for _, pod := range pods {
+			if pod.Spec.NodeName != "" {
+				nodes[pod.Spec.NodeName] = true
+			}
+		}
+		if len(nodes) > 1 {
+			failures = append(failures, common.Failure{
+				ID:          "PVC_ACCESS_MODE_CONFLICT",
+				Severity:    common.SeverityCritical,
+				Text:        fmt.Sprintf("PVC has access mode ReadWriteOnce but is used by pods on %d nodes, only pods on one node can mount it: %s", len(nodes), podNames(pods)),
+				FieldPath:   "spec.accessModes",
+				Remediation: &common.Remediation{Text: "Schedule the pods onto one node with pod affinity, or use a ReadWriteMany claim"},
+			})
+		}
+	}
+	return failures
+}
+
+// pvcUsage collects the volume usage of the given claims from the kubelets running their pods.
+// Nodes whose summary cannot be read are skipped, the proxy permission is often not granted.
+func (k *Kubernetes) pvcUsage(ctx context.Context, claimPods map[string][]v1.Pod) map[string]volumeUsage {
+	nodes := map[string]bool{}
+	for _, pods := range claimPods {
+		for _, pod := range pods {
+			if pod.Status.Phase == v1.PodRunning && pod.Spec.NodeName != "" {
+				nodes[pod.Spec.NodeName] = true
+			}
+		}
+	}
+
+	usage := map[string]volumeUsage{}
+	for node := range nodes {
+		data, err := k.kubeletStatsSummary(ctx, node)
+		if err != nil {
+			continue
+		}
+		nodeUsage, err := pvcUsageFromSummary(data)
+		if err != nil {
+			continue
+		}
+		for key, u := range nodeUsage {
+			usage[key] = u
+		}
+	}
+	return usage
+}
+
+func (k *Kubernetes) kubeletStatsSummary(ctx context.Context, node string) ([]byte, error) {
+	client := k.clientset.CoreV1().RESTClient()
+	// fake clientsets return a nil REST client
+	if c, ok := client.(*rest.RESTClient); !ok || c == nil {
+		return nil, fmt.Errorf("kubelet stats are not available")
+	}
+	return client.Get().Resource("nodes").Name(node).SubResource("proxy").Suffix("stats/summary").DoRaw(ctx)
+}
+
+func pvcUsageFromSummary(data []byte) (map[string]volumeUsage, error) {
+	var summary kubeletSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, err
+	}
+	usage := map[string]volumeUsage{}
+	for _, pod := range summary.Pods {
+		for _, volume := range pod.Volumes {
+			if volume.PVCRef == nil || volume.CapacityBytes == nil || volume.UsedBytes == nil {
+				continue
+			}
+			usage[fmt.Sprintf("%s/%s", volume.PVCRef.Namespace, volume.PVCRef.Name)] = volumeUsage{
+				CapacityBytes: *volume.CapacityBytes,
+				UsedBytes:     *volume.UsedBytes,
+			}
+		}
+	}
+	return usage, nil
+}
+
+func hasAccessMode(modes []v1.PersistentVolumeAccessMode, mode v1.PersistentVolumeAccessMode) bool {
+	for _, m := range modes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+func podNames(pods []v1.Pod) string {
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,186 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func newTestPVC(name string, phase v1.PersistentVolumeClaimPhase, modes ...v1.PersistentVolumeAccessMode) *v1.PersistentVolumeClaim {
+	return &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes:      modes,
+			StorageClassName: ptr.To("standard"),
+		},
+		Status: v1.PersistentVolumeClaimStatus{Phase: phase},
+	}
+}
+
+func newTestPodWithClaim(name, node, claim string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: v1.PodSpec{
+			NodeName: node,
+			Volumes: []v1.Volume{{
+				Name:         "data",
+				VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: claim}},
+			}},
+		},
+		Status: v1.PodStatus{Phase: v1.PodPending},
+	}
+}
+
+func TestAnalyzeStorage(t *testing.T) {
+	request := common.Request{Context: context.Background(), Namespace: "default"}
+	standard := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "standard"}}
+
+	t.Run("Bound claim", func(t *testing.T) {
+		pvc := newTestPVC("data", v1.ClaimBound, v1.ReadWriteOnce)
+		pvc.Spec.VolumeName = "pv-data"
+		pv := &v1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv-data"},
+			Spec: v1.PersistentVolumeSpec{
+				AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+				ClaimRef:    &v1.ObjectReference{Namespace: "default", Name: "data"},
+			},
+			Status: v1.PersistentVolumeStatus{Phase: v1.VolumeBound},
+		}
+		k := newTestKubernetes(fake.NewSimpleClientset(standard, pvc, pv, newTestPodWithClaim("db-0", "node-1", "data")), nil)
+		result, err := k.AnalyzeStorage(request)
+		assert.NoError(t, err)
+		assert.Equal(t, "[]", result)
+	})
+
+	t.Run("Pending claim with provisioning event and missing class", func(t *testing.T) {
+		pvc := newTestPVC("data", v1.ClaimPending, v1.ReadWriteOnce)
+		pvc.Spec.StorageClassName = ptr.To("fast")
+		event := &v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "data.1", Namespace: "default"},
+			InvolvedObject: v1.ObjectReference{Kind: "PersistentVolumeClaim", Name: "data", Namespace: "default"},
+			Reason:         "ProvisioningFailed",
+			Message:        `storageclass.storage.k8s.io "fast" not found`,
+		}
+		k := newTestKubernetes(fake.NewSimpleClientset(standard, pvc, event), nil)
+		result, err := k.AnalyzeStorage(request)
+		assert.NoError(t, err)
+		assert.Contains(t, result, "PVC is Pending, ProvisioningFailed")
+		assert.Contains(t, result, "PVC uses the StorageClass fast which does not exist")
+	})
+
+	t.Run("Claim without class and no default class", func(t *testing.T) {
+		pvc := newTestPVC("data", v1.ClaimPending, v1.ReadWriteOnce)
+		pvc.Spec.StorageClassName = nil
+		k := newTestKubernetes(fake.NewSimpleClientset(standard, pvc), nil)
+		result, err := k.AnalyzeStorage(request)
+		assert.NoError(t, err)
+		assert.Contains(t, result, "the cluster has no default StorageClass")
+	})
+
+	t.Run("Access mode mismatches", func(t *testing.T) {
+		pvc := newTestPVC("data", v1.ClaimBound, v1.ReadWriteOnce)
+		pvc.Spec.VolumeName = "pv-data"
+		pv := &v1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv-data"},
+			Spec: v1.PersistentVolumeSpec{
+				AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadOnlyMany},
+				ClaimRef:    &v1.ObjectReference{Namespace: "default", Name: "data"},
+			},
+		}
+		k := newTestKubernetes(fake.NewSimpleClientset(standard, pvc, pv,
+			newTestPodWithClaim("web-a", "node-1", "data"),
+			newTestPodWithClaim("web-b", "node-2", "data"),
+		), nil)
+		result, err := k.AnalyzeStorage(request)
+		assert.NoError(t, err)
+		assert.Contains(t, result, "PVC requests the access mode ReadWriteOnce which the PersistentVolume pv-data does not provide")
+		assert.Contains(t, result, "PVC has access mode ReadWriteOnce but is used by pods on 2 nodes, only pods on one node can mount it: web-a, web-b")
+	})
+
+	t.Run("Released volume and attach error", func(t *testing.T) {
+		pv := &v1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv-old"},
+			Spec: v1.PersistentVolumeSpec{
+				PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimRetain,
+				ClaimRef:                      &v1.ObjectReference{Namespace: "default", Name: "old"},
+			},
+			Status: v1.PersistentVolumeStatus{Phase: v1.VolumeReleased},
+		}
+		other := &v1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv-other"},
+			Spec:       v1.PersistentVolumeSpec{ClaimRef: &v1.ObjectReference{Namespace: "other", Name: "old"}},
+			Status:     v1.PersistentVolumeStatus{Phase: v1.VolumeReleased},
+		}
+		va := &storagev1.VolumeAttachment{
+			ObjectMeta: metav1.ObjectMeta{Name: "csi-123"},
+			Spec: storagev1.VolumeAttachmentSpec{
+				NodeName: "node-1",
+				Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: ptr.To("pv-old")},
+			},
+			Status: storagev1.VolumeAttachmentStatus{
+				AttachError: &storagev1.VolumeError{Message: "rpc error: volume is attached to another node"},
+			},
+		}
+		k := newTestKubernetes(fake.NewSimpleClientset(pv, other, va), nil)
+		result, err := k.AnalyzeStorage(request)
+		assert.NoError(t, err)
+		assert.Contains(t, result, "PersistentVolume is Released, its claim default/old was deleted")
+		assert.Contains(t, result, "Attaching PersistentVolume pv-old to node node-1 failed: rpc error: volume is attached to another node")
+		assert.Contains(t, result, `"parentObject":"PersistentVolumeClaim/default/old"`)
+		assert.NotContains(t, result, "pv-other")
+	})
+
+	t.Run("Unclaimed volumes", func(t *testing.T) {
+		pv := &v1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv-failed"},
+			Status:     v1.PersistentVolumeStatus{Phase: v1.VolumeFailed, Message: "host_path deleter only supports /tmp/.+"},
+		}
+		va := &storagev1.VolumeAttachment{
+			ObjectMeta: metav1.ObjectMeta{Name: "csi-456"},
+			Spec: storagev1.VolumeAttachmentSpec{
+				NodeName: "node-1",
+				Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: ptr.To("pv-failed")},
+			},
+			Status: storagev1.VolumeAttachmentStatus{
+				DetachError: &storagev1.VolumeError{Message: "node node-1 not found"},
+			},
+		}
+		k := newTestKubernetes(fake.NewSimpleClientset(pv, va), nil)
+
+		// a namespace does not own them
+		result, err := k.AnalyzeStorage(request)
+		assert.NoError(t, err)
+		assert.Equal(t, "[]", result)
+
+		result, err = k.AnalyzeStorage(common.Request{Context: context.Background()})
+		assert.NoError(t, err)
+		assert.Contains(t, result, "PersistentVolume failed reclamation: host_path deleter only supports /tmp/.+")
+		assert.Contains(t, result, "Detaching PersistentVolume pv-failed from node node-1 failed: node node-1 not found")
+
+		// a cluster scan reports them once, in the namespace that read the volumes first
+		ctx := withScanState(context.Background())
+		first, err := k.analyzeStorage(ctx, common.Request{Context: ctx, Namespace: "default"})
+		assert.NoError(t, err)
+		assert.Len(t, first, 2)
+		second, err := k.analyzeStorage(ctx, common.Request{Context: ctx, Namespace: "other"})
+		assert.NoError(t, err)
+		assert.Empty(t, second)
+	})
+}
+
+func TestPVCUsageFromSummary(t *testing.T) {
+	summary := []byte(`{"pods":[{"volume":[
+		{"name":"data","capacityBytes":1000,"usedBytes":950,"pvcRef":{"name":"data","namespace":"default"}},
+		{"name":"tmp","capacityBytes":1000,"usedBytes":10}
+	]}]}`)
+	usage, err := pvcUsageFromSummary(summary)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]volumeUsage{"default/data": {CapacityBytes: 1000, UsedBytes: 950}}, usage)
+}
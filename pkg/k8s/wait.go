@@ -0,0 +1,326 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const defaultWaitTimeout = 30 * time.Second
+
+// waitCondition reports whether an object reached the awaited state.
+type waitCondition func(obj *unstructured.Unstructured) (bool, error)
+
+// ResourceWait blocks until the object named by r, or every object matching r.LabelSelector, reaches the state
+// described by opts.For or the timeout expires. The objects are watched rather than polled, the watch is
+// restarted from a fresh list when it fails.
+func (k *Kubernetes) ResourceWait(r common.Request, opts common.WaitOptions) (string, error) {
+	switch {
+	case r.Name != "" && r.LabelSelector != "":
+		return "", fmt.Errorf("name and label selector are exclusive")
+	case r.Name == "" && r.LabelSelector == "":
+		return "", fmt.Errorf("a name or a label selector is required")
+	}
+	forDelete, condition, err := parseWaitFor(opts.For)
+	if err != nil {
+		return "", err
+	}
+	mapping, err := k.resolveKind(r.Kind, r.APIVersion)
+	if err != nil {
+		return "", err
+	}
+	namespace := ""
+	if isNamespaced(mapping) {
+		namespace = utils.NamespaceOrDefault(r.Namespace)
+	}
+	timeout := defaultWaitTimeout
+	if opts.Timeout > 0 {
+		timeout = time.Duration(opts.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(r.Context, timeout)
+	defer cancel()
+
+	client := k.dynamicClient.Resource(mapping.Resource).Namespace(namespace)
+	var fieldSelector string
+	if r.Name != "" {
+		fieldSelector = fields.OneTermEqualSelector("metadata.name", r.Name).String()
+	}
+	lw := &cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector, options.LabelSelector = fieldSelector, r.LabelSelector
+			return client.List(ctx, options)
+		},
+		WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector, options.LabelSelector = fieldSelector, r.LabelSelector
+			return client.Watch(ctx, options)
+		},
+	}
+
+	w := &waiter{
+		kind:      mapping.GroupVersionKind.Kind,
+		forDelete: forDelete,
+		condition: condition,
+		state:     map[string]bool{},
+		progress:  r.Progress,
+	}
+	// the field selector is not honoured by every client, e.g. the fake one of the tests
+	awaited := func(obj runtime.Object) (*unstructured.Unstructured, bool) {
+		u, ok := obj.(*unstructured.Unstructured)
+		return u, ok && (r.Name == "" || u.GetName() == r.Name)
+	}
+	precondition := func(store cache.Store) (bool, error) {
+		for _, item := range store.List() {
+			obj, ok := awaited(item.(runtime.Object))
+			if !ok {
+				continue
+			}
+			if err := w.observe(watch.Added, obj); err != nil {
+				return false, err
+			}
+		}
+		if len(w.state) == 0 && !forDelete {
+			if r.Name != "" {
+				return false, fmt.Errorf("%s %s not found", w.kind, objectKey(namespace, r.Name))
+			}
+			return false, fmt.Errorf("no %s matches the label selector %s", w.kind, r.LabelSelector)
+		}
+		w.report("")
+		return w.done(), nil
+	}
+	_, err = watchtools.UntilWithSync(ctx, lw, &unstructured.Unstructured{}, precondition, func(event watch.Event) (bool, error) {
+		obj, ok := awaited(event.Object)
+		if !ok {
+			return false, nil
+		}
+		if err := w.observe(event.Type, obj); err != nil {
+			return false, err
+		}
+		return w.done(), nil
+	})
+	if err != nil {
+		if r.Context.Err() == nil && (wait.Interrupted(err) || errors.Is(err, context.DeadlineExceeded)) {
+			return "", fmt.Errorf("timed out after %s waiting for %s: %s", timeout, opts.For, w.pending())
+		}
+		return "", err
+	}
+	if len(w.state) == 0 {
+		if r.Name != "" {
+			return fmt.Sprintf("%s %s deleted", w.kind, objectKey(namespace, r.Name)), nil
+		}
+		return fmt.Sprintf("no %s matches the label selector %s", w.kind, r.LabelSelector), nil
+	}
+	var lines []string
+	for _, key := range w.keys() {
+		lines = append(lines, fmt.Sprintf("%s %s %s", w.kind, key, w.verb()))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// waiter tracks the awaited objects, state maps their namespace/name to whether they reached the awaited state.
+type waiter struct {
+	kind      string
+	forDelete bool
+	condition waitCondition
+	state     map[string]bool
+	progress  common.Progress
+	// reported is the number of objects in the awaited state last reported, progress only ever increases
+	reported int
+}
+
+func (w *waiter) observe(eventType watch.EventType, obj *unstructured.Unstructured) error {
+	key := objectName(*obj)
+	met := false
+	switch {
+	case eventType == watch.Deleted:
+		if !w.forDelete {
+			delete(w.state, key)
+			return nil
+		}
+		met = true
+	case !w.forDelete:
+		var err error
+		if met, err = w.condition(obj); err != nil {
+			return err
+		}
+	}
+	w.state[key] = met
+	if met {
+		w.report(key)
+	}
+	return nil
+}
+
+// report notifies the progress when more objects reached the awaited state, key is the object that just did.
+func (w *waiter) report(key string) {
+	if w.progress == nil {
+		return
+	}
+	met := w.met()
+	if key == "" && w.reported == 0 && met == 0 {
+		w.progress(0, len(w.state), fmt.Sprintf("waiting for %d %s objects", len(w.state), w.kind))
+		return
+	}
+	if met <= w.reported {
+		return
+	}
+	w.reported = met
+	w.progress(met, len(w.state), fmt.Sprintf("%s %s %s", w.kind, key, w.verb()))
+}
+
+func (w *waiter) met() int {
+	var met int
+	for _, ok := range w.state {
+		if ok {
+			met++
+		}
+	}
+	return met
+}
+
+func (w *waiter) done() bool {
+	return (w.forDelete || len(w.state) > 0) && w.met() == len(w.state)
+}
+
+func (w *waiter) verb() string {
+	if w.forDelete {
+		return "deleted"
+	}
+	return "condition met"
+}
+
+func (w *waiter) keys() []string {
+	keys := make([]string, 0, len(w.state))
+	for key := range w.state {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// pending describes the objects that did not reach the awaited state.
+func (w *waiter) pending() string {
+	var pending []string
+	for _, key := range w.keys() {
+		if !w.state[key] {
+			pending = append(pending, key)
+		}
+	}
+	if len(pending) == 0 {
+		return fmt.Sprintf("no %s object was observed", w.kind)
+	}
+	return fmt.Sprintf("%d/%d %s objects are still pending: %s", len(pending), len(w.state), w.kind, strings.Join(pending, ", "))
+}
+
+// parseWaitFor parses the --for argument of kubectl wait, the condition is nil when waiting for the delete.
+func parseWaitFor(s string) (bool, waitCondition, error) {
+	switch {
+	case strings.EqualFold(s, "delete"):
+		return true, nil, nil
+	case strings.HasPrefix(s, "condition="):
+		name, value, found := strings.Cut(strings.TrimPrefix(s, "condition="), "=")
+		if name == "" {
+			return false, nil, fmt.Errorf("condition name is required, e.g. condition=Ready")
+		}
+		if !found {
+			value = "True"
+		}
+		return false, conditionMet(name, value), nil
+	case strings.HasPrefix(s, "jsonpath="):
+		condition, err := jsonPathMet(strings.TrimPrefix(s, "jsonpath="))
+		return false, condition, err
+	}
+	return false, nil, fmt.Errorf("unknown wait condition %q, expected delete, condition=<type>[=<status>] or jsonpath=<path>[=<value>]", s)
+}
+
+// conditionMet is met when the status condition typed name has the status value. A condition observed for an
+// older generation of the object is stale and never met.
+func conditionMet(name, value string) waitCondition {
+	return func(obj *unstructured.Unstructured) (bool, error) {
+		conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+		if err != nil {
+			return false, nil
+		}
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if conditionType, _ := condition["type"].(string); !strings.EqualFold(conditionType, name) {
+				continue
+			}
+			if observed, ok := condition["observedGeneration"].(int64); ok && observed < obj.GetGeneration() {
+				return false, nil
+			}
+			status, _ := condition["status"].(string)
+			return strings.EqualFold(status, value), nil
+		}
+		return false, nil
+	}
+}
+
+// jsonPathMet is met when the path exists, or when its value is value if one follows the path, e.g.
+// {.status.phase}=Running. The braces and the leading dot of the path may be omitted.
+func jsonPathMet(s string) (waitCondition, error) {
+	var expr, value string
+	var hasValue bool
+	if strings.HasPrefix(s, "{") {
+		end := strings.LastIndex(s, "}")
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated jsonpath %s", s)
+		}
+		expr = s[:end+1]
+		value, hasValue = strings.CutPrefix(s[end+1:], "=")
+		if !hasValue && s[end+1:] != "" {
+			return nil, fmt.Errorf("unexpected %q after the jsonpath %s", s[end+1:], expr)
+		}
+	} else {
+		expr, value, hasValue = strings.Cut(s, "=")
+		if !strings.HasPrefix(expr, ".") {
+			expr = "." + expr
+		}
+		expr = "{" + expr + "}"
+	}
+	path := jsonpath.New("wait")
+	if err := path.Parse(expr); err != nil {
+		return nil, fmt.Errorf("invalid jsonpath %s: %v", expr, err)
+	}
+
+	return func(obj *unstructured.Unstructured) (bool, error) {
+		// a missing field is not set yet
+		results, err := path.FindResults(obj.Object)
+		if err != nil || len(results) == 0 || len(results[0]) == 0 {
+			return false, nil
+		}
+		if !hasValue {
+			return true, nil
+		}
+		if len(results) > 1 || len(results[0]) > 1 {
+			return false, fmt.Errorf("jsonpath %s matches more than one value", expr)
+		}
+		v := results[0][0]
+		for v.Kind() == reflect.Interface {
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.Map, reflect.Slice, reflect.Struct:
+			return false, fmt.Errorf("jsonpath %s matches a %s, only a single value can be compared", expr, v.Kind())
+		}
+		return fmt.Sprint(v.Interface()) == value, nil
+	}, nil
+}
@@ -0,0 +1,141 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newWaitDeployment(name string, available string, readyReplicas int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"namespace":  "default",
+				"name":       name,
+				"generation": int64(2),
+				"labels":     map[string]interface{}{"app": "web"},
+			},
+			"status": map[string]interface{}{
+				"readyReplicas": readyReplicas,
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Available", "status": available},
+				},
+			},
+		},
+	}
+}
+
+func TestResourceWait(t *testing.T) {
+	r := common.Request{Context: context.Background(), Kind: "deploy", Namespace: "default", Name: "web-1"}
+
+	t.Run("Condition already met", func(t *testing.T) {
+		k := newTestKubernetes(fake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), newWaitDeployment("web-1", "True", 3)))
+		res, err := k.ResourceWait(r, common.WaitOptions{For: "condition=available"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Deployment default/web-1 condition met", res)
+	})
+
+	t.Run("JSONPath met after an update", func(t *testing.T) {
+		dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), newWaitDeployment("web-1", "False", 1))
+		k := newTestKubernetes(fake.NewSimpleClientset(), dynamicClient)
+		var progress []int
+		go func() {
+			time.Sleep(200 * time.Millisecond)
+			_, err := dynamicClient.Resource(deploymentsResource).Namespace("default").Update(context.Background(), newWaitDeployment("web-1", "True", 3), metav1.UpdateOptions{})
+			assert.NoError(t, err)
+		}()
+		r := r
+		r.Progress = func(p, total int, message string) { progress = append(progress, p) }
+		res, err := k.ResourceWait(r, common.WaitOptions{For: "jsonpath={.status.readyReplicas}=3", Timeout: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, "Deployment default/web-1 condition met", res)
+		assert.Equal(t, []int{0, 1}, progress)
+	})
+
+	t.Run("Delete of the selected objects", func(t *testing.T) {
+		dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), newWaitDeployment("web-1", "True", 3), newWaitDeployment("web-2", "True", 3))
+		k := newTestKubernetes(fake.NewSimpleClientset(), dynamicClient)
+		go func() {
+			time.Sleep(200 * time.Millisecond)
+			for _, name := range []string{"web-1", "web-2"} {
+				assert.NoError(t, dynamicClient.Resource(deploymentsResource).Namespace("default").Delete(context.Background(), name, metav1.DeleteOptions{}))
+			}
+		}()
+		res, err := k.ResourceWait(common.Request{Context: context.Background(), Kind: "deploy", Namespace: "default", LabelSelector: "app=web"}, common.WaitOptions{For: "delete", Timeout: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, "Deployment default/web-1 deleted\nDeployment default/web-2 deleted", res)
+	})
+
+	t.Run("Delete of a missing object", func(t *testing.T) {
+		k := newTestKubernetes(fake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), newWaitDeployment("web-2", "True", 3)))
+		res, err := k.ResourceWait(r, common.WaitOptions{For: "delete"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Deployment default/web-1 deleted", res)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		k := newTestKubernetes(fake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), newWaitDeployment("web-1", "False", 1)))
+		_, err := k.ResourceWait(r, common.WaitOptions{For: "condition=Available", Timeout: 1})
+		assert.EqualError(t, err, "timed out after 1s waiting for condition=Available: 1/1 Deployment objects are still pending: default/web-1")
+	})
+
+	t.Run("Missing object", func(t *testing.T) {
+		k := newTestKubernetes(fake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), newWaitDeployment("web-2", "True", 3)))
+		_, err := k.ResourceWait(r, common.WaitOptions{For: "condition=Available"})
+		assert.EqualError(t, err, "Deployment default/web-1 not found")
+	})
+}
+
+func TestParseWaitFor(t *testing.T) {
+	obj := newWaitDeployment("web-1", "True", 3)
+	tests := []struct {
+		name    string
+		forArg  string
+		met     bool
+		wantErr bool
+	}{
+		{name: "Condition", forArg: "condition=Available", met: true},
+		{name: "Condition with a status", forArg: "condition=Available=false", met: false},
+		{name: "Unknown condition", forArg: "condition=Progressing", met: false},
+		{name: "JSONPath", forArg: "jsonpath={.status.readyReplicas}=3", met: true},
+		{name: "JSONPath without braces", forArg: "jsonpath=status.readyReplicas=2", met: false},
+		{name: "JSONPath existence", forArg: "jsonpath={.status.conditions}", met: true},
+		{name: "JSONPath of a missing field", forArg: "jsonpath={.status.phase}=Running", met: false},
+		{name: "JSONPath of a list", forArg: "jsonpath={.status.conditions}=x", wantErr: true},
+		{name: "Missing condition name", forArg: "condition=", wantErr: true},
+		{name: "Unknown", forArg: "create", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forDelete, condition, err := parseWaitFor(tt.forArg)
+			assert.False(t, forDelete)
+			if err == nil {
+				var met bool
+				met, err = condition(obj)
+				assert.Equal(t, tt.met, met)
+			}
+			assert.Equal(t, tt.wantErr, err != nil, "error: %v", err)
+		})
+	}
+
+	t.Run("Stale condition", func(t *testing.T) {
+		stale := newWaitDeployment("web-1", "True", 3)
+		conditions, _, _ := unstructured.NestedSlice(stale.Object, "status", "conditions")
+		conditions[0].(map[string]interface{})["observedGeneration"] = int64(1)
+		assert.NoError(t, unstructured.SetNestedSlice(stale.Object, conditions, "status", "conditions"))
+		_, condition, _ := parseWaitFor("condition=Available")
+		met, err := condition(stale)
+		assert.NoError(t, err)
+		assert.False(t, met)
+	})
+}
@@ -1,11 +1,12 @@
 package k8s
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 
 	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
 	"github.com/wenhuwang/mcp-k8s-eye/pkg/utils"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -13,6 +14,10 @@ import (
 
 // AnalyzeValidatingWebhook analyzes ValidatingWebhookConfiguration resources and returns a list of failures
 func (k *Kubernetes) AnalyzeValidatingWebhook(r common.Request) (string, error) {
+	return marshalResults(k.analyzeValidatingWebhook(r.Context, r))
+}
+
+func (k *Kubernetes) analyzeValidatingWebhook(ctx context.Context, r common.Request) ([]common.Result, error) {
 	kind := "ValidatingWebhookConfiguration"
 	apiDoc := K8sApiReference{
 		Kind: kind,
@@ -23,11 +28,11 @@ func (k *Kubernetes) AnalyzeValidatingWebhook(r common.Request) (string, error)
 		OpenapiSchema: k.openapiSchema,
 	}
 
-	webhookList, err := k.clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(r.Context, metav1.ListOptions{
+	webhookList, err := k.clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{
 		LabelSelector: r.LabelSelector,
 	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	var preAnalysis = map[string]common.PreAnalysis{}
@@ -41,12 +46,16 @@ func (k *Kubernetes) AnalyzeValidatingWebhook(r common.Request) (string, error)
 			if wh.ClientConfig.Service != nil {
 				// Check if the service exists
 				svc, err := k.clientset.CoreV1().Services(wh.ClientConfig.Service.Namespace).Get(
-					r.Context, wh.ClientConfig.Service.Name, metav1.GetOptions{})
+					ctx, wh.ClientConfig.Service.Name, metav1.GetOptions{})
 				if err != nil {
 					doc := apiDoc.GetApiDocV2("webhooks.clientConfig.service")
 					failures = append(failures, common.Failure{
+						ID:            "WEBHOOK_SERVICE_NOT_FOUND",
+						Severity:      webhookSeverity(wh.FailurePolicy),
 						Text:          fmt.Sprintf("Webhook #%d (%s) references service which does not exist", i, wh.Name),
 						KubernetesDoc: doc,
+						FieldPath:     fmt.Sprintf("webhooks[%d].clientConfig.service", i),
+						Remediation:   &common.Remediation{Text: "Deploy the webhook service or delete the webhook configuration, a webhook with failurePolicy Fail rejects every matching request while it is unreachable"},
 					})
 				} else {
 					// Check if the service port exists
@@ -60,31 +69,42 @@ func (k *Kubernetes) AnalyzeValidatingWebhook(r common.Request) (string, error)
 					if wh.ClientConfig.Service.Port != nil && !portExists {
 						doc := apiDoc.GetApiDocV2("webhooks.clientConfig.service.port")
 						failures = append(failures, common.Failure{
+							ID:            "WEBHOOK_SERVICE_PORT_NOT_FOUND",
+							Severity:      webhookSeverity(wh.FailurePolicy),
 							Text:          fmt.Sprintf("Webhook #%d (%s) references service port %d which does not exist in service", i, wh.Name, *wh.ClientConfig.Service.Port),
 							KubernetesDoc: doc,
+							FieldPath:     fmt.Sprintf("webhooks[%d].clientConfig.service.port", i),
+							Remediation:   &common.Remediation{Text: "Point the webhook at a port the service exposes"},
 						})
 					}
 
 					if len(svc.Spec.Selector) == 0 {
 						doc := apiDoc.GetApiDocV2("webhooks.clientConfig.service")
 						failures = append(failures, common.Failure{
+							ID:            "WEBHOOK_SERVICE_NO_SELECTOR",
+							Severity:      common.SeverityInfo,
 							Text:          fmt.Sprintf("Webhook #%d (%s) references service which does not have a selector", i, wh.Name),
 							KubernetesDoc: doc,
+							FieldPath:     fmt.Sprintf("webhooks[%d].clientConfig.service", i),
 						})
 					}
 
 					// Check if the pods of the service are running
-					podList, err := k.clientset.CoreV1().Pods(wh.ClientConfig.Service.Namespace).List(r.Context, metav1.ListOptions{
+					podList, err := k.clientset.CoreV1().Pods(wh.ClientConfig.Service.Namespace).List(ctx, metav1.ListOptions{
 						LabelSelector: utils.MapToString(svc.Spec.Selector),
 					})
 					if err != nil {
-						return "", err
+						return nil, err
 					}
 					if len(podList.Items) == 0 {
 						doc := apiDoc.GetApiDocV2("webhooks.clientConfig.service")
 						failures = append(failures, common.Failure{
+							ID:            "WEBHOOK_NO_PODS",
+							Severity:      webhookSeverity(wh.FailurePolicy),
 							Text:          fmt.Sprintf("Webhook #%d (%s) references service which does not have pods", i, wh.Name),
 							KubernetesDoc: doc,
+							FieldPath:     fmt.Sprintf("webhooks[%d].clientConfig.service", i),
+							Remediation:   &common.Remediation{Text: "Start the pods of the webhook service, or set failurePolicy Ignore while the webhook is down"},
 						})
 					}
 
@@ -93,8 +113,12 @@ func (k *Kubernetes) AnalyzeValidatingWebhook(r common.Request) (string, error)
 						if pod.Status.Phase != corev1.PodRunning {
 							doc := apiDoc.GetApiDocV2("webhooks.clientConfig.service")
 							failures = append(failures, common.Failure{
+								ID:            "WEBHOOK_PODS_INACTIVE",
+								Severity:      webhookSeverity(wh.FailurePolicy),
 								Text:          fmt.Sprintf("Webhook #%d (%s) references service which have inactive pods", i, wh.Name),
 								KubernetesDoc: doc,
+								FieldPath:     fmt.Sprintf("webhooks[%d].clientConfig.service", i),
+								Remediation:   &common.Remediation{Text: "Analyze the pods of the webhook service"},
 							})
 						}
 					}
@@ -102,8 +126,12 @@ func (k *Kubernetes) AnalyzeValidatingWebhook(r common.Request) (string, error)
 			} else if wh.ClientConfig.URL == nil {
 				doc := apiDoc.GetApiDocV2("webhooks.clientConfig")
 				failures = append(failures, common.Failure{
+					ID:            "WEBHOOK_NO_CLIENT_CONFIG",
+					Severity:      common.SeverityCritical,
 					Text:          fmt.Sprintf("Webhook #%d (%s) has neither service reference nor URL", i, wh.Name),
 					KubernetesDoc: doc,
+					FieldPath:     fmt.Sprintf("webhooks[%d].clientConfig", i),
+					Remediation:   &common.Remediation{Text: "Set a service reference or a URL in the client config"},
 				})
 			}
 
@@ -111,8 +139,12 @@ func (k *Kubernetes) AnalyzeValidatingWebhook(r common.Request) (string, error)
 			if len(wh.ClientConfig.CABundle) == 0 {
 				doc := apiDoc.GetApiDocV2("webhooks.clientConfig.caBundle")
 				failures = append(failures, common.Failure{
+					ID:            "WEBHOOK_EMPTY_CA_BUNDLE",
+					Severity:      common.SeverityWarning,
 					Text:          fmt.Sprintf("Webhook #%d (%s) has empty CA bundle", i, wh.Name),
 					KubernetesDoc: doc,
+					FieldPath:     fmt.Sprintf("webhooks[%d].clientConfig.caBundle", i),
+					Remediation:   &common.Remediation{Text: "Set the CA bundle that signed the webhook serving certificate, e.g. with the cert-manager CA injector"},
 				})
 			}
 
@@ -120,8 +152,12 @@ func (k *Kubernetes) AnalyzeValidatingWebhook(r common.Request) (string, error)
 			if wh.Rules == nil || len(wh.Rules) == 0 {
 				doc := apiDoc.GetApiDocV2("webhooks.rules")
 				failures = append(failures, common.Failure{
+					ID:            "WEBHOOK_NO_RULES",
+					Severity:      common.SeverityInfo,
 					Text:          fmt.Sprintf("Webhook #%d (%s) has no rules", i, wh.Name),
 					KubernetesDoc: doc,
+					FieldPath:     fmt.Sprintf("webhooks[%d].rules", i),
+					Remediation:   &common.Remediation{Text: "Add rules for the requests the webhook should receive or delete the unused webhook"},
 				})
 			}
 		}
@@ -133,25 +169,15 @@ func (k *Kubernetes) AnalyzeValidatingWebhook(r common.Request) (string, error)
 		}
 	}
 
-	results := make([]common.Result, 0)
-	for key, value := range preAnalysis {
-		result := common.Result{
-			Kind:  kind,
-			Name:  key,
-			Error: value.FailureDetails,
-		}
-		results = append(results, result)
-	}
-
-	jsonData, err := json.Marshal(results)
-	if err != nil {
-		return "", err
-	}
-	return string(jsonData), nil
+	return k.preAnalysisResults(ctx, kind, preAnalysis, nil), nil
 }
 
 // AnalyzeMutatingWebhook analyzes MutatingWebhookConfiguration resources and returns a list of failures
 func (k *Kubernetes) AnalyzeMutatingWebhook(r common.Request) (string, error) {
+	return marshalResults(k.analyzeMutatingWebhook(r.Context, r))
+}
+
+func (k *Kubernetes) analyzeMutatingWebhook(ctx context.Context, r common.Request) ([]common.Result, error) {
 	kind := "MutatingWebhookConfiguration"
 	apiDoc := K8sApiReference{
 		Kind: kind,
@@ -162,11 +188,11 @@ func (k *Kubernetes) AnalyzeMutatingWebhook(r common.Request) (string, error) {
 		OpenapiSchema: k.openapiSchema,
 	}
 
-	webhookList, err := k.clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().List(r.Context, metav1.ListOptions{
+	webhookList, err := k.clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{
 		LabelSelector: r.LabelSelector,
 	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	var preAnalysis = map[string]common.PreAnalysis{}
@@ -180,12 +206,16 @@ func (k *Kubernetes) AnalyzeMutatingWebhook(r common.Request) (string, error) {
 			if wh.ClientConfig.Service != nil {
 				// Check if the service exists
 				svc, err := k.clientset.CoreV1().Services(wh.ClientConfig.Service.Namespace).Get(
-					r.Context, wh.ClientConfig.Service.Name, metav1.GetOptions{})
+					ctx, wh.ClientConfig.Service.Name, metav1.GetOptions{})
 				if err != nil {
 					doc := apiDoc.GetApiDocV2("webhooks.clientConfig.service")
 					failures = append(failures, common.Failure{
+						ID:            "WEBHOOK_SERVICE_NOT_FOUND",
+						Severity:      webhookSeverity(wh.FailurePolicy),
 						Text:          fmt.Sprintf("Webhook #%d (%s) references service %s/%s which does not exist", i, wh.Name, wh.ClientConfig.Service.Namespace, wh.ClientConfig.Service.Name),
 						KubernetesDoc: doc,
+						FieldPath:     fmt.Sprintf("webhooks[%d].clientConfig.service", i),
+						Remediation:   &common.Remediation{Text: "Deploy the webhook service or delete the webhook configuration, a webhook with failurePolicy Fail rejects every matching request while it is unreachable"},
 					})
 				} else {
 					// Check if the service port exists
@@ -199,30 +229,41 @@ func (k *Kubernetes) AnalyzeMutatingWebhook(r common.Request) (string, error) {
 					if wh.ClientConfig.Service.Port != nil && !portExists {
 						doc := apiDoc.GetApiDocV2("webhooks.clientConfig.service.port")
 						failures = append(failures, common.Failure{
+							ID:            "WEBHOOK_SERVICE_PORT_NOT_FOUND",
+							Severity:      webhookSeverity(wh.FailurePolicy),
 							Text:          fmt.Sprintf("Webhook #%d (%s) references service port %d which does not exist in service %s/%s", i, wh.Name, *wh.ClientConfig.Service.Port, wh.ClientConfig.Service.Namespace, wh.ClientConfig.Service.Name),
 							KubernetesDoc: doc,
+							FieldPath:     fmt.Sprintf("webhooks[%d].clientConfig.service.port", i),
+							Remediation:   &common.Remediation{Text: "Point the webhook at a port the service exposes"},
 						})
 					}
 
 					if len(svc.Spec.Selector) == 0 {
 						doc := apiDoc.GetApiDocV2("webhooks.clientConfig.service")
 						failures = append(failures, common.Failure{
+							ID:            "WEBHOOK_SERVICE_NO_SELECTOR",
+							Severity:      common.SeverityInfo,
 							Text:          fmt.Sprintf("Webhook #%d (%s) references service which does not have a selector", i, wh.Name),
 							KubernetesDoc: doc,
+							FieldPath:     fmt.Sprintf("webhooks[%d].clientConfig.service", i),
 						})
 					}
 					// Check if the pods of the service are running
-					podList, err := k.clientset.CoreV1().Pods(wh.ClientConfig.Service.Namespace).List(r.Context, metav1.ListOptions{
+					podList, err := k.clientset.CoreV1().Pods(wh.ClientConfig.Service.Namespace).List(ctx, metav1.ListOptions{
 						LabelSelector: utils.MapToString(svc.Spec.Selector),
 					})
 					if err != nil {
-						return "", err
+						return nil, err
 					}
 					if len(podList.Items) == 0 {
 						doc := apiDoc.GetApiDocV2("webhooks.clientConfig.service")
 						failures = append(failures, common.Failure{
+							ID:            "WEBHOOK_NO_PODS",
+							Severity:      webhookSeverity(wh.FailurePolicy),
 							Text:          fmt.Sprintf("Webhook #%d (%s) references service which does not have pods", i, wh.Name),
 							KubernetesDoc: doc,
+							FieldPath:     fmt.Sprintf("webhooks[%d].clientConfig.service", i),
+							Remediation:   &common.Remediation{Text: "Start the pods of the webhook service, or set failurePolicy Ignore while the webhook is down"},
 						})
 					}
 					// Check if the pods are running
@@ -230,8 +271,12 @@ func (k *Kubernetes) AnalyzeMutatingWebhook(r common.Request) (string, error) {
 						if pod.Status.Phase != corev1.PodRunning {
 							doc := apiDoc.GetApiDocV2("webhooks.clientConfig.service")
 							failures = append(failures, common.Failure{
+								ID:            "WEBHOOK_PODS_INACTIVE",
+								Severity:      webhookSeverity(wh.FailurePolicy),
 								Text:          fmt.Sprintf("Webhook #%d (%s) references service which have inactive pods", i, wh.Name),
 								KubernetesDoc: doc,
+								FieldPath:     fmt.Sprintf("webhooks[%d].clientConfig.service", i),
+								Remediation:   &common.Remediation{Text: "Analyze the pods of the webhook service"},
 							})
 						}
 					}
@@ -239,8 +284,12 @@ func (k *Kubernetes) AnalyzeMutatingWebhook(r common.Request) (string, error) {
 			} else if wh.ClientConfig.URL == nil {
 				doc := apiDoc.GetApiDocV2("webhooks.clientConfig")
 				failures = append(failures, common.Failure{
+					ID:            "WEBHOOK_NO_CLIENT_CONFIG",
+					Severity:      common.SeverityCritical,
 					Text:          fmt.Sprintf("Webhook #%d (%s) has neither service reference nor URL", i, wh.Name),
 					KubernetesDoc: doc,
+					FieldPath:     fmt.Sprintf("webhooks[%d].clientConfig", i),
+					Remediation:   &common.Remediation{Text: "Set a service reference or a URL in the client config"},
 				})
 			}
 
@@ -248,8 +297,12 @@ func (k *Kubernetes) AnalyzeMutatingWebhook(r common.Request) (string, error) {
 			if len(wh.ClientConfig.CABundle) == 0 {
 				doc := apiDoc.GetApiDocV2("webhooks.clientConfig.caBundle")
 				failures = append(failures, common.Failure{
+					ID:            "WEBHOOK_EMPTY_CA_BUNDLE",
+					Severity:      common.SeverityWarning,
 					Text:          fmt.Sprintf("Webhook #%d (%s) has empty CA bundle", i, wh.Name),
 					KubernetesDoc: doc,
+					FieldPath:     fmt.Sprintf("webhooks[%d].clientConfig.caBundle", i),
+					Remediation:   &common.Remediation{Text: "Set the CA bundle that signed the webhook serving certificate, e.g. with the cert-manager CA injector"},
 				})
 			}
 
@@ -257,8 +310,12 @@ func (k *Kubernetes) AnalyzeMutatingWebhook(r common.Request) (string, error) {
 			if wh.Rules == nil || len(wh.Rules) == 0 {
 				doc := apiDoc.GetApiDocV2("webhooks.rules")
 				failures = append(failures, common.Failure{
+					ID:            "WEBHOOK_NO_RULES",
+					Severity:      common.SeverityInfo,
 					Text:          fmt.Sprintf("Webhook #%d (%s) has no rules", i, wh.Name),
 					KubernetesDoc: doc,
+					FieldPath:     fmt.Sprintf("webhooks[%d].rules", i),
+					Remediation:   &common.Remediation{Text: "Add rules for the requests the webhook should receive or delete the unused webhook"},
 				})
 			}
 		}
@@ -269,19 +326,14 @@ func (k *Kubernetes) AnalyzeMutatingWebhook(r common.Request) (string, error) {
 		}
 	}
 
-	results := make([]common.Result, 0)
-	for key, value := range preAnalysis {
-		result := common.Result{
-			Kind:  kind,
-			Name:  key,
-			Error: value.FailureDetails,
-		}
-		results = append(results, result)
-	}
+	return k.preAnalysisResults(ctx, kind, preAnalysis, nil), nil
+}
 
-	jsonData, err := json.Marshal(results)
-	if err != nil {
-		return "", err
+// webhookSeverity ranks an unreachable webhook, with failurePolicy Fail (the default) the API server rejects the
+// requests it intercepts.
+func webhookSeverity(policy *admissionregistrationv1.FailurePolicyType) string {
+	if policy != nil && *policy == admissionregistrationv1.Ignore {
+		return common.SeverityWarning
 	}
-	return string(jsonData), nil
+	return common.SeverityCritical
 }
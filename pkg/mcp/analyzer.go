@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/k8s"
+)
+
+// initAnalyzers generates an "<name> analyze" tool for every registered analyzer.
+func (s *Server) initAnalyzers() []server.ServerTool {
+	var tools []server.ServerTool
+	for _, a := range s.k8s.Analyzers() {
+		options := []mcp.ToolOption{
+			mcp.WithDescription(a.Description()),
+			mcp.WithOutputSchema[common.AnalysisReport](),
+			minSeverityOption(),
+		}
+		if a.Namespaced() {
+			options = append(options, mcp.WithString("namespace",
+				mcp.Description("the namespace to analyze in (default all namespaces)"),
+			))
+		} else {
+			options = append(options,
+				mcp.WithString("name",
+					mcp.Description("the name of the object to analyze (default all)"),
+				),
+				mcp.WithString("label-selector",
+					mcp.Description("label selector to filter resources (optional)"),
+				),
+			)
+		}
+		tools = append(tools, server.ServerTool{
+			Tool:    mcp.NewTool(a.Name()+" analyze", options...),
+			Handler: s.analyzerHandler(a),
+		})
+	}
+	return tools
+}
+
+func (s *Server) analyzerHandler(a k8s.Analyzer) server.ToolHandlerFunc {
+	return func(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		r := common.Request{Context: ctx}
+		if v, ok := ctr.GetArguments()["namespace"].(string); ok {
+			r.Namespace = v
+		}
+		if v, ok := ctr.GetArguments()["name"].(string); ok {
+			r.Name = v
+		}
+		if v, ok := ctr.GetArguments()["label-selector"].(string); ok {
+			r.LabelSelector = v
+		}
+		if v, ok := ctr.GetArguments()["min_severity"].(string); ok {
+			r.MinSeverity = v
+		}
+		results, err := s.k8s.AnalyzeResults(a.Name(), r)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to run the %s analyzer: %v", a.Name(), err)), nil
+		}
+		return analysisResult(a.Name(), results)
+	}
+}
+
+// analysisResult returns the results of an analyzer as structured content with a summary listing every failure.
+func analysisResult(analyzer string, results []common.Result) (*mcp.CallToolResult, error) {
+	report := common.AnalysisReport{Analyzer: analyzer, Results: results}
+	if report.Results == nil {
+		report.Results = []common.Result{}
+	}
+	var lines []string
+	for _, result := range results {
+		report.Summary.Objects++
+		for _, failure := range result.Error {
+			switch failure.Severity {
+			case common.SeverityCritical:
+				report.Summary.Critical++
+			case common.SeverityWarning:
+				report.Summary.Warning++
+			default:
+				report.Summary.Info++
+			}
+			lines = append(lines, fmt.Sprintf("- [%s] %s %s: %s", failure.Severity, result.Kind, result.Name, failure.Text))
+		}
+	}
+
+	summary := fmt.Sprintf("The %s analyzer found no failures.", analyzer)
+	if report.Summary.Objects > 0 {
+		summary = fmt.Sprintf("The %s analyzer found %d objects with failures (%d critical, %d warning, %d info):\n%s",
+			analyzer, report.Summary.Objects, report.Summary.Critical, report.Summary.Warning, report.Summary.Info, strings.Join(lines, "\n"))
+	}
+	return structuredResult(report, summary)
+}
+
+// structuredResult returns data as the structured content of a tool result whose content holds the summary,
+// followed by the JSON of data for clients that do not read structured content.
+func structuredResult(data any, summary string) (*mcp.CallToolResult, error) {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	result := mcp.NewToolResultStructured(data, summary)
+	result.Content = append(result.Content, mcp.NewTextContent(string(jsonData)))
+	return result, nil
+}
+
+func minSeverityOption() mcp.ToolOption {
+	return mcp.WithString("min_severity",
+		mcp.Description("only report findings of this severity or higher (default all)"),
+		mcp.Enum(common.SeverityCritical, common.SeverityWarning, common.SeverityInfo),
+	)
+}
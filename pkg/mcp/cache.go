@@ -0,0 +1,33 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/utils"
+)
+
+// initCache returns the cache status tool, it is only available when the informer cache is enabled.
+func (s *Server) initCache() []server.ServerTool {
+	if !s.k8s.CacheEnabled() {
+		return nil
+	}
+	return []server.ServerTool{
+		{
+			Tool: mcp.NewTool("cache status",
+				mcp.WithDescription("show the resource types served from the informer cache, whether they are synced and how stale they may be"),
+			),
+			Handler: s.cacheStatus,
+		},
+	}
+}
+
+func (s *Server) cacheStatus(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	res, err := utils.Marshal(s.k8s.CacheStatus())
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to marshal the cache status: %v", err)), nil
+	}
+	return mcp.NewToolResultText(res), nil
+}
@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+)
+
+func (s *Server) initCluster() []server.ServerTool {
+	return []server.ServerTool{
+		{
+			Tool: mcp.NewTool("cluster analyze",
+				mcp.WithDescription("run every analyzer across namespaces and report the findings grouped by namespace and root object, most severe first"),
+				mcp.WithOutputSchema[common.ClusterReport](),
+				mcp.WithString("namespaces",
+					mcp.Description("comma separated namespaces to scan (default all namespaces)"),
+				),
+				mcp.WithNumber("concurrency",
+					mcp.Description("the maximum number of analyzers running at the same time (default 8)"),
+				),
+				minSeverityOption(),
+			),
+			Handler: s.clusterAnalyze,
+		},
+	}
+}
+
+func (s *Server) clusterAnalyze(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var opts common.ClusterScanOptions
+	if v, ok := ctr.GetArguments()["namespaces"].(string); ok {
+		for _, ns := range strings.Split(v, ",") {
+			if ns = strings.TrimSpace(ns); ns != "" {
+				opts.Namespaces = append(opts.Namespaces, ns)
+			}
+		}
+	}
+	if v, ok := ctr.GetArguments()["concurrency"].(float64); ok {
+		opts.Concurrency = int(v)
+	}
+	if v, ok := ctr.GetArguments()["min_severity"].(string); ok {
+		opts.MinSeverity = v
+	}
+	report, err := s.k8s.ScanCluster(common.Request{Context: ctx, Progress: s.progress(ctx, ctr)}, opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to analyze cluster: %v", err)), nil
+	}
+	return structuredResult(report, clusterSummary(report))
+}
+
+// clusterSummary lists the findings of a cluster report object by object, most severe first.
+func clusterSummary(report common.ClusterReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Found %d objects with failures in %d namespaces (%d critical, %d warning, %d info)",
+		report.Summary.Objects, report.Summary.Namespaces, report.Summary.Critical, report.Summary.Warning, report.Summary.Info)
+	for _, namespace := range report.Namespaces {
+		for _, object := range namespace.Objects {
+			name := object.Object
+			if namespace.Namespace != "" {
+				name = namespace.Namespace + "/" + object.Object
+			}
+			for _, finding := range object.Findings {
+				fmt.Fprintf(&b, "\n- [%s] %s: %s", finding.Severity, name, finding.Text)
+			}
+		}
+	}
+	for _, e := range report.Errors {
+		fmt.Fprintf(&b, "\n- analyzer error: %s", e)
+	}
+	return b.String()
+}
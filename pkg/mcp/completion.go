@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+)
+
+// maxCompletions is the most values a completion may return
+const maxCompletions = 100
+
+// completionProvider suggests the namespace, kind and name arguments of the prompts and resource templates from the
+// cluster. MCP completions only cover prompts and resource templates, tools cannot be completed.
+type completionProvider struct {
+	s *Server
+}
+
+func (p completionProvider) CompletePromptArgument(ctx context.Context, promptName string, argument mcp.CompleteArgument, completeContext mcp.CompleteContext) (*mcp.Completion, error) {
+	// the name argument of the get namespace prompt is a namespace
+	if promptName == "get namespace" && argument.Name == "name" {
+		argument.Name = "namespace"
+	}
+	return p.s.complete(ctx, argument, completeContext)
+}
+
+func (p completionProvider) CompleteResourceArgument(ctx context.Context, uri string, argument mcp.CompleteArgument, completeContext mcp.CompleteContext) (*mcp.Completion, error) {
+	return p.s.complete(ctx, argument, completeContext)
+}
+
+// complete suggests the values of a namespace, kind or name argument starting with its current value, names are
+// suggested once the kind is known and within the namespace already chosen.
+func (s *Server) complete(ctx context.Context, argument mcp.CompleteArgument, completeContext mcp.CompleteContext) (*mcp.Completion, error) {
+	args := completeContext.Arguments
+	var values []string
+	var err error
+	switch argument.Name {
+	case "namespace":
+		values, err = s.k8s.CompleteNamespaces(ctx, argument.Value)
+	case "kind":
+		values, err = s.k8s.CompleteKinds(argument.Value)
+	case "name":
+		if args["kind"] != "" {
+			values, err = s.k8s.CompleteNames(common.Request{
+				Context:    ctx,
+				Kind:       args["kind"],
+				APIVersion: args["api_version"],
+				Namespace:  args["namespace"],
+			}, argument.Value)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	completion := &mcp.Completion{Values: values, Total: len(values)}
+	if len(values) > maxCompletions {
+		completion.Values = values[:maxCompletions]
+		completion.HasMore = true
+	}
+	if completion.Values == nil {
+		completion.Values = []string{}
+	}
+	return completion, nil
+}
@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+)
+
+// defaultProtectedNamespaces are the namespaces where writes need a confirmation when no others are configured
+var defaultProtectedNamespaces = []string{"kube-system", "kube-public", "kube-node-lease"}
+
+// maxImpactObjects bounds the objects listed in a confirmation message, the others are only counted
+const maxImpactObjects = 20
+
+var confirmSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"confirm": map[string]interface{}{
+			"type":        "boolean",
+			"title":       "Proceed",
+			"description": "Run the operation described above",
+		},
+	},
+	"required": []string{"confirm"},
+}
+
+// confirmArgument is the tool argument carrying the confirmation token of a client that does not support elicitation
+func confirmArgument() mcp.ToolOption {
+	return mcp.WithString("confirm",
+		mcp.Description("the confirmation token returned by a previous call, only needed when the client does not support elicitation"),
+	)
+}
+
+// confirm asks the user to confirm the destructive operation described by message and reports whether it may run,
+// result is returned to the caller otherwise. Clients supporting elicitation always ask the user directly, the
+// confirm argument is ignored so the model cannot confirm on the user's behalf. The others get the message with
+// token and confirm by repeating the call with the token in the confirm argument, the token changes with what the
+// operation affects so a confirmation given for another impact is rejected.
+func (s *Server) confirm(ctx context.Context, ctr mcp.CallToolRequest, message, token string) (result *mcp.CallToolResult, confirmed bool) {
+	if supportsElicitation(ctx) {
+		res, err := s.server.RequestElicitation(ctx, mcp.ElicitationRequest{
+			Params: mcp.ElicitationParams{
+				Message:         message,
+				RequestedSchema: confirmSchema,
+			},
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to ask the user to confirm the operation, nothing was changed: %v", err)), false
+		}
+		content, _ := res.Content.(map[string]interface{})
+		if res.Action == mcp.ElicitationResponseActionAccept && content["confirm"] == true {
+			return nil, true
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("The operation was not confirmed, nothing was changed:\n%s", message)), false
+	}
+
+	if provided, _ := ctr.GetArguments()["confirm"].(string); provided != "" {
+		if provided == token {
+			return nil, true
+		}
+		// the expected token is not revealed, the caller previews the operation again to get it
+		return mcp.NewToolResultError(fmt.Sprintf("the confirmation token does not match, what the operation affects changed since it was issued, nothing was changed:\n%s\n\nRepeat the call without confirm to preview the operation again.", message)), false
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("%s\n\nThis operation needs a confirmation, ask the user and repeat the call with confirm set to %s to proceed.", message, token)), false
+}
+
+func supportsElicitation(ctx context.Context) bool {
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo)
+	if !ok {
+		return false
+	}
+	if _, ok := session.(server.SessionWithElicitation); !ok {
+		return false
+	}
+	return session.GetClientCapabilities().Elicitation != nil
+}
+
+// confirmToken hashes what an operation is about to do, parts must identify the operation and its impact.
+func confirmToken(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		fmt.Fprintf(h, "%s\x00", part)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+func (s *Server) protected(namespace string) bool {
+	return slices.Contains(s.protectedNamespaces, namespace)
+}
+
+func deleteMessage(impact common.DeleteImpact) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Delete %d objects:\n", len(impact.Objects))
+	writeObjects(&b, impact.Objects)
+	if len(impact.Dependents) > 0 {
+		fmt.Fprintf(&b, "The delete cascades to %d owned objects:\n", len(impact.Dependents))
+		writeObjects(&b, impact.Dependents)
+	}
+	fmt.Fprintf(&b, "Pods affected: %d", impact.Pods)
+	return b.String()
+}
+
+func writeObjects(b *strings.Builder, refs []common.ObjectReference) {
+	for i, ref := range refs {
+		if i == maxImpactObjects {
+			fmt.Fprintf(b, "- and %d more\n", len(refs)-maxImpactObjects)
+			break
+		}
+		name := ref.Name
+		if ref.Namespace != "" {
+			name = ref.Namespace + "/" + name
+		}
+		fmt.Fprintf(b, "- %s %s\n", ref.Kind, name)
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
 )
 
 func (s *Server) initDeployment() []server.ServerTool {
@@ -22,37 +23,26 @@ func (s *Server) initDeployment() []server.ServerTool {
 				mcp.WithNumber("replicas",
 					mcp.Description("the number of replicas to scale to"),
 				),
+				confirmArgument(),
 			),
 			Handler: s.deploymentScale,
 		},
-		{
-			Tool: mcp.NewTool("deployment analyze",
-				mcp.WithDescription("analyze deployment status"),
-				mcp.WithString("namespace",
-					mcp.Description("the namespace to analyze deployments in"),
-				),
-			),
-			Handler: s.deploymentAnalyze,
-		},
 	}
 }
 
 func (s *Server) deploymentScale(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	ns := ctr.Params.Arguments["namespace"].(string)
-	deploy := ctr.Params.Arguments["deployment"].(string)
-	replicas := int32(ctr.Params.Arguments["replicas"].(float64))
+	ns := ctr.GetArguments()["namespace"].(string)
+	deploy := ctr.GetArguments()["deployment"].(string)
+	replicas := int32(ctr.GetArguments()["replicas"].(float64))
+	if replicas == 0 {
+		r := common.Request{Context: ctx, Kind: "Deployment", Namespace: ns, Name: deploy}
+		if result, confirmed := s.confirmScaleToZero(ctx, ctr, r); !confirmed {
+			return result, nil
+		}
+	}
 	res, err := s.k8s.DeploymentScale(ctx, ns, deploy, replicas)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to scale deployment %s/%s: %v", ns, deploy, err)), nil
 	}
 	return mcp.NewToolResultText(res), nil
 }
-
-func (s *Server) deploymentAnalyze(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	ns := ctr.Params.Arguments["namespace"].(string)
-	res, err := s.k8s.AnalyzeDeployment(ctx, ns)
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to analyze deployments in namespace %s: %v", ns, err)), nil
-	}
-	return mcp.NewToolResultText(res), nil
-}
@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func (s *Server) initDiscovery() []server.ServerTool {
+	return []server.ServerTool{
+		{
+			Tool: mcp.NewTool("api resources",
+				mcp.WithDescription("list the resources the cluster serves, custom resources included, with their group, version, kind, short names, namespaced flag and verbs"),
+				mcp.WithString("group",
+					mcp.Description("only list the resources of this API group, core for the legacy group (default all groups)"),
+				),
+				mcp.WithString("verb",
+					mcp.Description("only list the resources supporting this verb, e.g. list or watch (default all)"),
+				),
+			),
+			Handler: s.apiResources,
+		},
+		{
+			Tool: mcp.NewTool("api versions",
+				mcp.WithDescription("list the API group versions the cluster serves"),
+			),
+			Handler: s.apiVersions,
+		},
+	}
+}
+
+func (s *Server) apiResources(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	group, _ := ctr.GetArguments()["group"].(string)
+	verb, _ := ctr.GetArguments()["verb"].(string)
+	res, err := s.k8s.APIResources(group, verb)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list api resources: %v", err)), nil
+	}
+	return mcp.NewToolResultText(res), nil
+}
+
+func (s *Server) apiVersions(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	res, err := s.k8s.APIVersions()
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list api versions: %v", err)), nil
+	}
+	return mcp.NewToolResultText(res), nil
+}
@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+)
+
+func (s *Server) initEvents() []server.ServerTool {
+	return []server.ServerTool{
+		{
+			Tool: mcp.NewTool("events",
+				mcp.WithDescription("list events as a timeline, oldest first, with repeated events collapsed into one with their count. With a kind and a name the timeline covers the object and the objects it owns, e.g. the ReplicaSets and Pods of a Deployment"),
+				mcp.WithString("namespace",
+					mcp.Description("the namespace of the events (default the default namespace)"),
+				),
+				mcp.WithBoolean("all_namespaces",
+					mcp.Description("list the events of all namespaces, cannot be used with a name of a namespaced kind"),
+				),
+				mcp.WithString("kind",
+					mcp.Description("only the events of objects of this kind, a kind, plural, short name or resource.group such as Pod, deploy or certificates.cert-manager.io"),
+				),
+				mcp.WithString("api_version",
+					mcp.Description("the group/version of the kind, e.g. apps/v1, only needed when the kind is ambiguous (default the preferred version)"),
+				),
+				mcp.WithString("name",
+					mcp.Description("only the events of the object with this name and, with a kind, of the objects it owns"),
+				),
+				mcp.WithString("type",
+					mcp.Description("only the events of this type"),
+					mcp.Enum("Normal", "Warning"),
+				),
+				mcp.WithString("reason",
+					mcp.Description("only the events with this reason, e.g. BackOff or FailedScheduling"),
+				),
+				mcp.WithString("since",
+					mcp.Description("only the events last seen within this duration, e.g. 30m or 2h (default all)"),
+				),
+			),
+			Handler: s.events,
+		},
+	}
+}
+
+func (s *Server) events(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r := common.Request{Context: ctx}
+	if v, ok := ctr.GetArguments()["namespace"].(string); ok {
+		r.Namespace = v
+	}
+	if v, ok := ctr.GetArguments()["kind"].(string); ok {
+		r.Kind = v
+	}
+	if v, ok := ctr.GetArguments()["api_version"].(string); ok {
+		r.APIVersion = v
+	}
+	if v, ok := ctr.GetArguments()["name"].(string); ok {
+		r.Name = v
+	}
+	var opts common.EventOptions
+	if v, ok := ctr.GetArguments()["all_namespaces"].(bool); ok {
+		opts.AllNamespaces = v
+	}
+	if v, ok := ctr.GetArguments()["type"].(string); ok {
+		opts.Type = v
+	}
+	if v, ok := ctr.GetArguments()["reason"].(string); ok {
+		opts.Reason = v
+	}
+	if v, ok := ctr.GetArguments()["since"].(string); ok && v != "" {
+		since, err := time.ParseDuration(v)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid since %s: %v", v, err)), nil
+		}
+		opts.Since = since
+	}
+	res, err := s.k8s.Events(r, opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list events: %v", err)), nil
+	}
+	return mcp.NewToolResultText(res), nil
+}
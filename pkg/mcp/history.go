@@ -0,0 +1,157 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/utils"
+)
+
+const (
+	defaultHistoryInterval = 5 * time.Minute
+	defaultHistoryWindow   = 24 * time.Hour
+	// defaultHistoryRetention is how long resolved findings are kept
+	defaultHistoryRetention = 7 * 24 * time.Hour
+)
+
+// initHistory returns the finding history tools, they are only available when a history file is configured.
+func (s *Server) initHistory() []server.ServerTool {
+	if s.history == nil {
+		return nil
+	}
+	return []server.ServerTool{
+		{
+			Tool: mcp.NewTool("findings since",
+				mcp.WithDescription("list the findings that appeared or came back since a time, e.g. what broke in the last hour"),
+				mcp.WithString("since",
+					mcp.Description("an RFC3339 time or a duration back from now such as 1h or 30m"),
+					mcp.Required(),
+				),
+			),
+			Handler: s.findingsSince,
+		},
+		{
+			Tool: mcp.NewTool("findings resolved",
+				mcp.WithDescription("list the findings that stopped being reported since a time"),
+				mcp.WithString("since",
+					mcp.Description("an RFC3339 time or a duration back from now such as 1h or 30m (default 24h)"),
+				),
+			),
+			Handler: s.findingsResolved,
+		},
+		{
+			Tool: mcp.NewTool("flapping objects",
+				mcp.WithDescription("list the objects whose findings were resolved and came back since a time, most flapping first"),
+				mcp.WithString("since",
+					mcp.Description("an RFC3339 time or a duration back from now such as 1h or 30m (default 24h)"),
+				),
+				mcp.WithNumber("min_flaps",
+					mcp.Description("the minimum number of times the findings of an object came back (default 2)"),
+				),
+			),
+			Handler: s.flappingObjects,
+		},
+	}
+}
+
+// recordHistory scans the cluster every interval and records the findings in the history store until ctx is done,
+// the findings resolved more than retention ago are deleted after every scan.
+func (s *Server) recordHistory(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		scanCtx, cancel := context.WithTimeout(ctx, interval)
+		report, err := s.k8s.ScanCluster(common.Request{Context: scanCtx}, common.ClusterScanOptions{})
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to scan cluster for the finding history: %v", err)
+		} else if err := s.history.Record(time.Now(), report); err != nil {
+			log.Printf("Failed to record the finding history: %v", err)
+		} else if len(report.Errors) > 0 {
+			log.Printf("Partial cluster scan for the finding history, the findings of the failed analyzers were not resolved: %s", strings.Join(report.Errors, "; "))
+		}
+		if _, err := s.history.Prune(time.Now().Add(-retention)); err != nil {
+			log.Printf("Failed to prune the finding history: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseSince parses an RFC3339 time or a duration back from now, an empty value means the default window.
+func parseSince(v string) (time.Time, error) {
+	if v == "" {
+		return time.Now().Add(-defaultHistoryWindow), nil
+	}
+	if d, err := time.ParseDuration(v); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since %q, expected an RFC3339 time or a duration such as 1h", v)
+	}
+	return t, nil
+}
+
+func (s *Server) findingsSince(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	since, err := parseSince(ctr.GetArguments()["since"].(string))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	records, err := s.history.Since(since)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to read the finding history: %v", err)), nil
+	}
+	return historyResult(records)
+}
+
+func (s *Server) findingsResolved(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	v, _ := ctr.GetArguments()["since"].(string)
+	since, err := parseSince(v)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	records, err := s.history.Resolved(since)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to read the finding history: %v", err)), nil
+	}
+	return historyResult(records)
+}
+
+func (s *Server) flappingObjects(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	v, _ := ctr.GetArguments()["since"].(string)
+	since, err := parseSince(v)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	minFlaps := 2
+	if v, ok := ctr.GetArguments()["min_flaps"].(float64); ok {
+		minFlaps = int(v)
+	}
+	objects, err := s.history.Flapping(since, minFlaps)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to read the finding history: %v", err)), nil
+	}
+	return historyResult(objects)
+}
+
+func historyResult(data any) (*mcp.CallToolResult, error) {
+	res, err := utils.Marshal(data)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to marshal the finding history: %v", err)), nil
+	}
+	return mcp.NewToolResultText(res), nil
+}
@@ -1,11 +1,13 @@
 package mcp
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
 )
 
 func (s *Server) initPod() []server.ServerTool {
@@ -24,7 +26,7 @@ func (s *Server) initPod() []server.ServerTool {
 		},
 		{
 			Tool: mcp.NewTool("pod exec",
-				mcp.WithDescription("execute a command in a pod"),
+				mcp.WithDescription("execute a command in a pod, the user confirms the command before it runs"),
 				mcp.WithString("namespace",
 					mcp.Description("the namespace to get pods in"),
 				),
@@ -34,24 +36,101 @@ func (s *Server) initPod() []server.ServerTool {
 				mcp.WithString("command",
 					mcp.Description("the command to execute"),
 				),
+				confirmArgument(),
 			),
 			Handler: s.podExec,
 		},
 		{
-			Tool: mcp.NewTool("pod analyze",
-				mcp.WithDescription("analyze pod"),
+			Tool: mcp.NewTool("pod probe",
+				mcp.WithDescription("probe a pod port over port-forward with an HTTP(S) or TCP check"),
 				mcp.WithString("namespace",
-					mcp.Description("the namespace to get pods in"),
+					mcp.Description("the namespace of the pod"),
+					mcp.Required(),
+				),
+				mcp.WithString("pod",
+					mcp.Description("the pod to probe"),
+					mcp.Required(),
+				),
+				mcp.WithString("port",
+					mcp.Description("the container port number or name to probe"),
+					mcp.Required(),
+				),
+				mcp.WithString("scheme",
+					mcp.Description("the probe scheme (default http)"),
+					mcp.Enum("http", "https", "tcp"),
+				),
+				mcp.WithString("path",
+					mcp.Description("the HTTP path to request (default /)"),
+				),
+				mcp.WithNumber("timeout",
+					mcp.Description("the probe timeout in seconds (default 10)"),
+				),
+			),
+			Handler: s.podProbe,
+		},
+		{
+			Tool: mcp.NewTool("pod file read",
+				mcp.WithDescription("read a file or a byte range of a file from a pod container"),
+				mcp.WithString("namespace",
+					mcp.Description("the namespace of the pod"),
+					mcp.Required(),
+				),
+				mcp.WithString("pod",
+					mcp.Description("the pod to read the file from"),
+					mcp.Required(),
+				),
+				mcp.WithString("container",
+					mcp.Description("the container to read the file from (default container of the pod if empty)"),
+				),
+				mcp.WithString("path",
+					mcp.Description("the file path inside the container"),
+					mcp.Required(),
+				),
+				mcp.WithNumber("offset",
+					mcp.Description("the byte offset to start reading at (default 0)"),
+				),
+				mcp.WithNumber("length",
+					mcp.Description("the number of bytes to read (default up to the 1MiB limit)"),
+				),
+			),
+			Handler: s.podFileRead,
+		},
+		{
+			Tool: mcp.NewTool("pod file write",
+				mcp.WithDescription("write a file into a pod container"),
+				mcp.WithString("namespace",
+					mcp.Description("the namespace of the pod"),
+					mcp.Required(),
+				),
+				mcp.WithString("pod",
+					mcp.Description("the pod to write the file to"),
+					mcp.Required(),
+				),
+				mcp.WithString("container",
+					mcp.Description("the container to write the file to (default container of the pod if empty)"),
+				),
+				mcp.WithString("path",
+					mcp.Description("the file path inside the container"),
+					mcp.Required(),
+				),
+				mcp.WithString("content",
+					mcp.Description("the file content"),
+					mcp.Required(),
 				),
+				mcp.WithString("encoding",
+					mcp.Description("the encoding of the content (default text)"),
+					mcp.Enum("text", "base64"),
+				),
+				confirmArgument(),
 			),
-			Handler: s.podAnalyze,
+			Handler: s.podFileWrite,
 		},
 	}
 }
 
 func (s *Server) podLogs(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	ns := ctr.Params.Arguments["namespace"].(string)
-	pod := ctr.Params.Arguments["pod"].(string)
+	ns := ctr.GetArguments()["namespace"].(string)
+	pod := ctr.GetArguments()["pod"].(string)
 	res, err := s.k8s.PodLogs(ctx, ns, pod)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to get logs for pod %s/%s: %v", ns, pod, err)), nil
@@ -60,9 +139,13 @@ func (s *Server) podLogs(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.Cal
 }
 
 func (s *Server) podExec(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	ns := ctr.Params.Arguments["namespace"].(string)
-	pod := ctr.Params.Arguments["pod"].(string)
-	cmd := ctr.Params.Arguments["command"].(string)
+	ns := ctr.GetArguments()["namespace"].(string)
+	pod := ctr.GetArguments()["pod"].(string)
+	cmd := ctr.GetArguments()["command"].(string)
+	message := fmt.Sprintf("Run %q in pod %s/%s", cmd, ns, pod)
+	if result, confirmed := s.confirm(ctx, ctr, message, confirmToken("pod exec", ns, pod, cmd)); !confirmed {
+		return result, nil
+	}
 	res, err := s.k8s.PodExec(ctx, ns, pod, cmd)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to execute command %s on pod %s/%s: %v", cmd, ns, pod, err)), nil
@@ -70,11 +153,95 @@ func (s *Server) podExec(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.Cal
 	return mcp.NewToolResultText(res), nil
 }
 
-func (s *Server) podAnalyze(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	ns := ctr.Params.Arguments["namespace"].(string)
-	res, err := s.k8s.AnalyzePod(ctx, ns)
+func (s *Server) podProbe(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ns := ctr.GetArguments()["namespace"].(string)
+	pod := ctr.GetArguments()["pod"].(string)
+	res, err := s.k8s.PodProbe(common.Request{
+		Context:   ctx,
+		Namespace: ns,
+		Name:      pod,
+	}, probeOptions(ctr))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to probe pod %s/%s: %v", ns, pod, err)), nil
+	}
+	return mcp.NewToolResultText(res), nil
+}
+
+// probeOptions reads the probe arguments shared by the pod and service probe tools
+func probeOptions(ctr mcp.CallToolRequest) common.ProbeOptions {
+	var opts common.ProbeOptions
+	if v, ok := ctr.GetArguments()["port"].(string); ok {
+		opts.Port = v
+	}
+	if v, ok := ctr.GetArguments()["scheme"].(string); ok {
+		opts.Scheme = v
+	}
+	if v, ok := ctr.GetArguments()["path"].(string); ok {
+		opts.Path = v
+	}
+	if v, ok := ctr.GetArguments()["timeout"].(float64); ok {
+		opts.Timeout = int(v)
+	}
+	return opts
+}
+
+func (s *Server) podFileRead(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ns := ctr.GetArguments()["namespace"].(string)
+	pod := ctr.GetArguments()["pod"].(string)
+	res, err := s.k8s.PodFileRead(common.Request{
+		Context:   ctx,
+		Namespace: ns,
+		Name:      pod,
+	}, podFileOptions(ctr))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to read file from pod %s/%s: %v", ns, pod, err)), nil
+	}
+	return mcp.NewToolResultText(res), nil
+}
+
+func (s *Server) podFileWrite(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ns := ctr.GetArguments()["namespace"].(string)
+	pod := ctr.GetArguments()["pod"].(string)
+	opts := podFileOptions(ctr)
+	message := fmt.Sprintf("Write %d characters of %s content to %s in pod %s/%s", len(opts.Content), cmp.Or(opts.Encoding, "text"), opts.Path, ns, pod)
+	if opts.Container != "" {
+		message += fmt.Sprintf(" container %s", opts.Container)
+	}
+	token := confirmToken("pod file write", ns, pod, opts.Container, opts.Path, opts.Encoding, opts.Content)
+	if result, confirmed := s.confirm(ctx, ctr, message, token); !confirmed {
+		return result, nil
+	}
+	res, err := s.k8s.PodFileWrite(common.Request{
+		Context:   ctx,
+		Namespace: ns,
+		Name:      pod,
+	}, opts)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to analyze pods in namespace %s: %v", ns, err)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("failed to write file to pod %s/%s: %v", ns, pod, err)), nil
 	}
 	return mcp.NewToolResultText(res), nil
 }
+
+// podFileOptions reads the arguments shared by the pod file tools
+func podFileOptions(ctr mcp.CallToolRequest) common.PodFileOptions {
+	var opts common.PodFileOptions
+	if v, ok := ctr.GetArguments()["container"].(string); ok {
+		opts.Container = v
+	}
+	if v, ok := ctr.GetArguments()["path"].(string); ok {
+		opts.Path = v
+	}
+	if v, ok := ctr.GetArguments()["offset"].(float64); ok {
+		opts.Offset = int64(v)
+	}
+	if v, ok := ctr.GetArguments()["length"].(float64); ok {
+		opts.Length = int64(v)
+	}
+	if v, ok := ctr.GetArguments()["content"].(string); ok {
+		opts.Content = v
+	}
+	if v, ok := ctr.GetArguments()["encoding"].(string); ok {
+		opts.Encoding = v
+	}
+	return opts
+}
@@ -0,0 +1,29 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+)
+
+// progress returns a Progress sending notifications/progress to the client for the call, it is nil when the client
+// did not ask for progress notifications with a progress token.
+func (s *Server) progress(ctx context.Context, ctr mcp.CallToolRequest) common.Progress {
+	if ctr.Params.Meta == nil || ctr.Params.Meta.ProgressToken == nil {
+		return nil
+	}
+	token := ctr.Params.Meta.ProgressToken
+	return func(progress, total int, message string) {
+		params := map[string]interface{}{
+			"progressToken": token,
+			"progress":      progress,
+			"message":       message,
+		}
+		if total > 0 {
+			params["total"] = total
+		}
+		// progress is best effort, a notification that cannot be sent does not fail the call
+		_ = s.server.SendNotificationToClient(ctx, "notifications/progress", params)
+	}
+}
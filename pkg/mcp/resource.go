@@ -3,6 +3,9 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -15,9 +18,12 @@ func (s *Server) initResource() []server.ServerTool {
 			Tool: mcp.NewTool("resource list",
 				mcp.WithDescription("list resources in a namespace or all namespaces"),
 				mcp.WithString("kind",
-					mcp.Description("the kind of resource to list"),
+					mcp.Description("the kind of resource to list, a kind, plural, short name or resource.group such as Deployment, deploy or certificates.cert-manager.io"),
 					mcp.Required(),
 				),
+				mcp.WithString("api_version",
+					mcp.Description("the group/version of the kind, e.g. apps/v1, only needed when the kind is ambiguous (default the preferred version)"),
+				),
 				mcp.WithString("namespace",
 					mcp.Description("the namespace to list resources in"),
 					mcp.Required(),
@@ -29,9 +35,12 @@ func (s *Server) initResource() []server.ServerTool {
 			Tool: mcp.NewTool("resource get",
 				mcp.WithDescription("get resource details"),
 				mcp.WithString("kind",
-					mcp.Description("the kind of resource to get"),
+					mcp.Description("the kind of resource to get, a kind, plural, short name or resource.group such as Deployment, deploy or certificates.cert-manager.io"),
 					mcp.Required(),
 				),
+				mcp.WithString("api_version",
+					mcp.Description("the group/version of the kind, e.g. apps/v1, only needed when the kind is ambiguous (default the preferred version)"),
+				),
 				mcp.WithString("namespace",
 					mcp.Description("the namespace to get resources in"),
 					mcp.Required(),
@@ -45,18 +54,40 @@ func (s *Server) initResource() []server.ServerTool {
 		},
 		{
 			Tool: mcp.NewTool("resource delete",
-				mcp.WithDescription("delete resource"),
+				mcp.WithDescription("delete a resource by name or the resources matching a label selector, the user confirms the objects and owned objects the delete removes before anything is deleted"),
 				mcp.WithString("kind",
-					mcp.Description("the kind of resource to delete"),
+					mcp.Description("the kind of resource to delete, a kind, plural, short name or resource.group such as Deployment, deploy or certificates.cert-manager.io"),
 					mcp.Required(),
 				),
+				mcp.WithString("api_version",
+					mcp.Description("the group/version of the kind, e.g. apps/v1, only needed when the kind is ambiguous (default the preferred version)"),
+				),
 				mcp.WithString("namespace",
 					mcp.Description("the namespace to get resources in"),
 					mcp.Required(),
 				),
 				mcp.WithString("name",
-					mcp.Description("the resource name to delete"),
-					mcp.Required(),
+					mcp.Description("the resource name to delete, exclusive with label_selector"),
+				),
+				mcp.WithString("label_selector",
+					mcp.Description("delete the resources matching the label selector, e.g. app=web (exclusive with name)"),
+				),
+				confirmArgument(),
+				mcp.WithString("propagation_policy",
+					mcp.Description("how the dependents are deleted (default the policy of the kind)"),
+					mcp.Enum("Foreground", "Background", "Orphan"),
+				),
+				mcp.WithNumber("grace_period_seconds",
+					mcp.Description("the termination grace period in seconds, 0 deletes immediately (default the grace period of the object)"),
+				),
+				mcp.WithString("uid",
+					mcp.Description("only delete the named resource if it still has this UID"),
+				),
+				mcp.WithString("resource_version",
+					mcp.Description("only delete the named resource if it still has this resourceVersion"),
+				),
+				mcp.WithBoolean("dry_run",
+					mcp.Description("validate the delete with a server-side dry-run without deleting anything"),
 				),
 			),
 			Handler: s.resourceDelete,
@@ -68,16 +99,142 @@ func (s *Server) initResource() []server.ServerTool {
 					mcp.Description("the resource to create or update"),
 					mcp.Required(),
 				),
+				confirmArgument(),
 			),
 			Handler: s.resourceCreateOrUpdate,
 		},
+		{
+			Tool: mcp.NewTool("resource patch",
+				mcp.WithDescription("patch any resource with a json, merge, strategic merge or server-side apply patch and show the diff of the change, optionally as a dry-run preview"),
+				mcp.WithString("kind",
+					mcp.Description("the kind of resource to patch, a kind, plural, short name or resource.group such as Deployment, deploy or certificates.cert-manager.io"),
+					mcp.Required(),
+				),
+				mcp.WithString("api_version",
+					mcp.Description("the group/version of the kind, e.g. apps/v1, only needed when the kind is ambiguous (default the preferred version)"),
+				),
+				mcp.WithString("namespace",
+					mcp.Description("the namespace of the resource"),
+					mcp.Required(),
+				),
+				mcp.WithString("name",
+					mcp.Description("the resource name to patch"),
+					mcp.Required(),
+				),
+				mcp.WithString("type",
+					mcp.Description("the patch type: json (RFC 6902), merge (RFC 7386), strategic (built-in kinds only) or apply (server-side apply)"),
+					mcp.Required(),
+					mcp.Enum(common.PatchTypeJSON, common.PatchTypeMerge, common.PatchTypeStrategic, common.PatchTypeApply),
+				),
+				mcp.WithString("patch",
+					mcp.Description("the patch document in JSON or YAML, an apply patch holds the apiVersion, kind, name and the fields to own"),
+					mcp.Required(),
+				),
+				mcp.WithBoolean("dry_run",
+					mcp.Description("only preview the change with a server-side dry-run"),
+				),
+				mcp.WithBoolean("force",
+					mcp.Description("take over the fields of an apply patch owned by other field managers"),
+				),
+				confirmArgument(),
+			),
+			Handler: s.resourcePatch,
+		},
+		{
+			Tool: mcp.NewTool("resource label",
+				mcp.WithDescription("add, change or remove labels of any resource and show the resulting diff"),
+				mcp.WithString("kind",
+					mcp.Description("the kind of resource to label, a kind, plural, short name or resource.group such as Deployment, deploy or certificates.cert-manager.io"),
+					mcp.Required(),
+				),
+				mcp.WithString("api_version",
+					mcp.Description("the group/version of the kind, e.g. apps/v1, only needed when the kind is ambiguous (default the preferred version)"),
+				),
+				mcp.WithString("namespace",
+					mcp.Description("the namespace of the resource"),
+					mcp.Required(),
+				),
+				mcp.WithString("name",
+					mcp.Description("the resource name to label"),
+					mcp.Required(),
+				),
+				mcp.WithObject("labels",
+					mcp.Description(`the labels to change, a string value sets the key and null removes it, e.g. {"app": "web", "tier": null}`),
+					mcp.AdditionalProperties(map[string]interface{}{"type": []string{"string", "null"}}),
+					mcp.Required(),
+				),
+				mcp.WithBoolean("overwrite",
+					mcp.Description("allow changing the value of an existing key, without it the call fails instead"),
+				),
+				mcp.WithBoolean("dry_run",
+					mcp.Description("only preview the change with a server-side dry-run"),
+				),
+				confirmArgument(),
+			),
+			Handler: s.resourceLabel,
+		},
+		{
+			Tool: mcp.NewTool("resource annotate",
+				mcp.WithDescription("add, change or remove annotations of any resource and show the resulting diff"),
+				mcp.WithString("kind",
+					mcp.Description("the kind of resource to annotate, a kind, plural, short name or resource.group such as Deployment, deploy or certificates.cert-manager.io"),
+					mcp.Required(),
+				),
+				mcp.WithString("api_version",
+					mcp.Description("the group/version of the kind, e.g. apps/v1, only needed when the kind is ambiguous (default the preferred version)"),
+				),
+				mcp.WithString("namespace",
+					mcp.Description("the namespace of the resource"),
+					mcp.Required(),
+				),
+				mcp.WithString("name",
+					mcp.Description("the resource name to annotate"),
+					mcp.Required(),
+				),
+				mcp.WithObject("annotations",
+					mcp.Description(`the annotations to change, a string value sets the key and null removes it, e.g. {"owner": "team-a", "note": null}`),
+					mcp.AdditionalProperties(map[string]interface{}{"type": []string{"string", "null"}}),
+					mcp.Required(),
+				),
+				mcp.WithBoolean("overwrite",
+					mcp.Description("allow changing the value of an existing key, without it the call fails instead"),
+				),
+				mcp.WithBoolean("dry_run",
+					mcp.Description("only preview the change with a server-side dry-run"),
+				),
+				confirmArgument(),
+			),
+			Handler: s.resourceAnnotate,
+		},
+		{
+			Tool: mcp.NewTool("resource explain",
+				mcp.WithDescription("explain the fields of a kind like kubectl explain, with their types, descriptions, required markers and enum values, custom resources included"),
+				mcp.WithString("kind",
+					mcp.Description("the kind to explain, e.g. Deployment"),
+					mcp.Required(),
+				),
+				mcp.WithString("api_version",
+					mcp.Description("the group/version of the kind, e.g. apps/v1 (default the preferred version)"),
+				),
+				mcp.WithString("field",
+					mcp.Description("a dotted field path below the kind, e.g. spec.template.spec.containers (default the kind itself)"),
+				),
+				mcp.WithBoolean("recursive",
+					mcp.Description("list all nested fields with their types instead of the descriptions of the direct fields"),
+				),
+			),
+			Handler: s.resourceExplain,
+		},
 		{
 			Tool: mcp.NewTool("resource describe",
 				mcp.WithDescription("describe resource"),
 				mcp.WithString("kind",
-					mcp.Description("the resource kind to describe"),
+					mcp.Description("the kind of resource to describe, a kind, plural, short name or resource.group such as Deployment, deploy or certificates.cert-manager.io"),
 					mcp.Required(),
 				),
+				mcp.WithString("api_version",
+					mcp.Description("the group/version of the kind, e.g. apps/v1, only needed when the kind is ambiguous (default the preferred version)"),
+				),
 				mcp.WithString("namespace",
 					mcp.Description("the resource namespace to describe"),
 					mcp.Required(),
@@ -89,6 +246,70 @@ func (s *Server) initResource() []server.ServerTool {
 			),
 			Handler: s.ResourceDescribe,
 		},
+		{
+			Tool: mcp.NewTool("resource scale",
+				mcp.WithDescription("scale any resource exposing the scale subresource, such as deployments, statefulsets, replicasets or custom resources"),
+				mcp.WithString("kind",
+					mcp.Description("the kind of resource to scale, a kind, plural, short name or resource.group such as Deployment, deploy or certificates.cert-manager.io"),
+					mcp.Required(),
+				),
+				mcp.WithString("api_version",
+					mcp.Description("the group/version of the kind, e.g. apps/v1, only needed when the kind is ambiguous (default the preferred version)"),
+				),
+				mcp.WithString("namespace",
+					mcp.Description("the namespace of the resource"),
+					mcp.Required(),
+				),
+				mcp.WithString("name",
+					mcp.Description("the resource name to scale"),
+					mcp.Required(),
+				),
+				mcp.WithNumber("replicas",
+					mcp.Description("the number of replicas to scale to"),
+					mcp.Required(),
+				),
+				mcp.WithNumber("current_replicas",
+					mcp.Description("only scale if the current replicas match this value"),
+				),
+				mcp.WithBoolean("wait",
+					mcp.Description("wait until the scaled replicas are ready"),
+				),
+				mcp.WithNumber("timeout",
+					mcp.Description("the wait timeout in seconds (default 300)"),
+				),
+				confirmArgument(),
+			),
+			Handler: s.resourceScale,
+		},
+		{
+			Tool: mcp.NewTool("resource wait",
+				mcp.WithDescription("wait until a resource, or all the resources matching a label selector, are deleted or reach a condition, like kubectl wait"),
+				mcp.WithString("kind",
+					mcp.Description("the kind of resource to wait for, a kind, plural, short name or resource.group such as Pod, deploy or certificates.cert-manager.io"),
+					mcp.Required(),
+				),
+				mcp.WithString("api_version",
+					mcp.Description("the group/version of the kind, e.g. apps/v1, only needed when the kind is ambiguous (default the preferred version)"),
+				),
+				mcp.WithString("namespace",
+					mcp.Description("the namespace of the resources, ignored for cluster-scoped kinds"),
+				),
+				mcp.WithString("name",
+					mcp.Description("the resource name to wait for, exclusive with label_selector"),
+				),
+				mcp.WithString("label_selector",
+					mcp.Description("wait for all the resources matching this label selector, e.g. app=web"),
+				),
+				mcp.WithString("for",
+					mcp.Description("what to wait for: delete, condition=<type>[=<status>] (e.g. condition=Ready, condition=Available=False) or jsonpath=<path>[=<value>] (e.g. jsonpath={.status.phase}=Running)"),
+					mcp.Required(),
+				),
+				mcp.WithNumber("timeout",
+					mcp.Description("the wait timeout in seconds (default 30)"),
+				),
+			),
+			Handler: s.resourceWait,
+		},
 		{
 			Tool: mcp.NewTool("workload resource usage",
 				mcp.WithDescription("workload resource usage"),
@@ -111,39 +332,97 @@ func (s *Server) initResource() []server.ServerTool {
 }
 
 func (s *Server) resourceList(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	ns := ctr.Params.Arguments["namespace"].(string)
-	kind := ctr.Params.Arguments["kind"].(string)
-	res, err := s.k8s.ResourceList(ctx, kind, ns)
+	r := common.Request{
+		Context:   ctx,
+		Kind:      ctr.GetArguments()["kind"].(string),
+		Namespace: ctr.GetArguments()["namespace"].(string),
+	}
+	if v, ok := ctr.GetArguments()["api_version"].(string); ok {
+		r.APIVersion = v
+	}
+	res, err := s.k8s.ResourceList(r)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to list resources in namespace %s: %v", ns, err)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list resources in namespace %s: %v", r.Namespace, err)), nil
 	}
 	return mcp.NewToolResultText(res), nil
 }
 
 func (s *Server) resourceGet(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	ns := ctr.Params.Arguments["namespace"].(string)
-	kind := ctr.Params.Arguments["kind"].(string)
-	name := ctr.Params.Arguments["name"].(string)
-	res, err := s.k8s.ResourceGet(ctx, kind, ns, name)
+	r := common.Request{
+		Context:   ctx,
+		Kind:      ctr.GetArguments()["kind"].(string),
+		Name:      ctr.GetArguments()["name"].(string),
+		Namespace: ctr.GetArguments()["namespace"].(string),
+	}
+	if v, ok := ctr.GetArguments()["api_version"].(string); ok {
+		r.APIVersion = v
+	}
+	res, err := s.k8s.ResourceGet(r)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to get resource %s/%s: %v", ns, name, err)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get resource %s/%s: %v", r.Namespace, r.Name, err)), nil
 	}
 	return mcp.NewToolResultText(res), nil
 }
 
 func (s *Server) resourceDelete(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	ns := ctr.Params.Arguments["namespace"].(string)
-	kind := ctr.Params.Arguments["kind"].(string)
-	name := ctr.Params.Arguments["name"].(string)
-	res, err := s.k8s.ResourceDelete(ctx, kind, ns, name)
+	r := common.Request{
+		Context:   ctx,
+		Kind:      ctr.GetArguments()["kind"].(string),
+		Namespace: ctr.GetArguments()["namespace"].(string),
+	}
+	r.APIVersion, _ = ctr.GetArguments()["api_version"].(string)
+	r.Name, _ = ctr.GetArguments()["name"].(string)
+	r.LabelSelector, _ = ctr.GetArguments()["label_selector"].(string)
+	var opts common.DeleteOptions
+	opts.PropagationPolicy, _ = ctr.GetArguments()["propagation_policy"].(string)
+	if v, ok := ctr.GetArguments()["grace_period_seconds"].(float64); ok {
+		gracePeriod := int64(v)
+		opts.GracePeriodSeconds = &gracePeriod
+	}
+	opts.UID, _ = ctr.GetArguments()["uid"].(string)
+	opts.ResourceVersion, _ = ctr.GetArguments()["resource_version"].(string)
+	opts.DryRun, _ = ctr.GetArguments()["dry_run"].(bool)
+	impact, err := s.k8s.DeleteImpact(r, opts)
+	// a dry-run changes nothing and is not confirmed
+	if err == nil && len(impact.Objects) > 0 && !opts.DryRun {
+		if result, confirmed := s.confirm(ctx, ctr, deleteMessage(impact), impact.Token); !confirmed {
+			return result, nil
+		}
+	}
+	var res string
+	if err == nil {
+		opts.Confirm = impact.Token
+		res, err = s.k8s.ResourceDelete(r, opts)
+	}
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to delete resource %s/%s: %v", ns, name, err)), nil
+		if r.Name == "" {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to delete resources matching %s in namespace %s: %v", r.LabelSelector, r.Namespace, err)), nil
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("failed to delete resource %s/%s: %v", r.Namespace, r.Name, err)), nil
 	}
 	return mcp.NewToolResultText(res), nil
 }
 
 func (s *Server) resourceCreateOrUpdate(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	resource := ctr.Params.Arguments["resource"].(string)
+	resource := ctr.GetArguments()["resource"].(string)
+	objects, err := s.k8s.ManifestObjects(resource)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to create/update resource: %v", err)), nil
+	}
+	var protected []common.ObjectReference
+	for _, obj := range objects {
+		if s.protected(obj.Namespace) || (obj.Kind == "Namespace" && s.protected(obj.Name)) {
+			protected = append(protected, obj)
+		}
+	}
+	if len(protected) > 0 {
+		var b strings.Builder
+		fmt.Fprintf(&b, "Apply %d objects to protected namespaces:\n", len(protected))
+		writeObjects(&b, protected)
+		if result, confirmed := s.confirm(ctx, ctr, strings.TrimSuffix(b.String(), "\n"), confirmToken("resource create or update", resource)); !confirmed {
+			return result, nil
+		}
+	}
 	res, err := s.k8s.ResourceCreateOrUpdate(ctx, resource)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to create/update resource: %v", err)), nil
@@ -151,12 +430,138 @@ func (s *Server) resourceCreateOrUpdate(ctx context.Context, ctr mcp.CallToolReq
 	return mcp.NewToolResultText(res), nil
 }
 
+func (s *Server) resourcePatch(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r := common.Request{
+		Context:   ctx,
+		Kind:      ctr.GetArguments()["kind"].(string),
+		Name:      ctr.GetArguments()["name"].(string),
+		Namespace: ctr.GetArguments()["namespace"].(string),
+	}
+	if v, ok := ctr.GetArguments()["api_version"].(string); ok {
+		r.APIVersion = v
+	}
+	opts := common.PatchOptions{
+		Type:  ctr.GetArguments()["type"].(string),
+		Patch: ctr.GetArguments()["patch"].(string),
+	}
+	opts.DryRun, _ = ctr.GetArguments()["dry_run"].(bool)
+	opts.Force, _ = ctr.GetArguments()["force"].(bool)
+	if s.protected(r.Namespace) && !opts.DryRun {
+		message := fmt.Sprintf("Patch %s %s/%s in the protected namespace %s with the %s patch:\n%s", r.Kind, r.Namespace, r.Name, r.Namespace, opts.Type, opts.Patch)
+		token := confirmToken("resource patch", r.Kind, r.APIVersion, r.Namespace, r.Name, opts.Type, opts.Patch)
+		if result, confirmed := s.confirm(ctx, ctr, message, token); !confirmed {
+			return result, nil
+		}
+	}
+	res, err := s.k8s.ResourcePatch(r, opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to patch %s %s/%s: %v", r.Kind, r.Namespace, r.Name, err)), nil
+	}
+	return mcp.NewToolResultText(res), nil
+}
+
+func (s *Server) resourceLabel(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r, opts, err := metadataRequest(ctx, ctr, "labels")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if result, confirmed := s.confirmMetadata(ctx, ctr, r, "labels", opts); !confirmed {
+		return result, nil
+	}
+	res, err := s.k8s.ResourceLabel(r, opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to label %s %s/%s: %v", r.Kind, r.Namespace, r.Name, err)), nil
+	}
+	return mcp.NewToolResultText(res), nil
+}
+
+func (s *Server) resourceAnnotate(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r, opts, err := metadataRequest(ctx, ctr, "annotations")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if result, confirmed := s.confirmMetadata(ctx, ctr, r, "annotations", opts); !confirmed {
+		return result, nil
+	}
+	res, err := s.k8s.ResourceAnnotate(r, opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to annotate %s %s/%s: %v", r.Kind, r.Namespace, r.Name, err)), nil
+	}
+	return mcp.NewToolResultText(res), nil
+}
+
+// confirmMetadata confirms a change of the labels or annotations of an object in a protected namespace
+func (s *Server) confirmMetadata(ctx context.Context, ctr mcp.CallToolRequest, r common.Request, field string, opts common.MetadataOptions) (*mcp.CallToolResult, bool) {
+	if !s.protected(r.Namespace) || opts.DryRun {
+		return nil, true
+	}
+	var changes []string
+	for key, value := range opts.Set {
+		changes = append(changes, fmt.Sprintf("- set %s=%s", key, value))
+	}
+	for _, key := range opts.Remove {
+		changes = append(changes, fmt.Sprintf("- remove %s", key))
+	}
+	slices.Sort(changes)
+	message := fmt.Sprintf("Change the %s of %s %s/%s in the protected namespace %s:\n%s", field, r.Kind, r.Namespace, r.Name, r.Namespace, strings.Join(changes, "\n"))
+	token := confirmToken("resource "+field, r.Kind, r.APIVersion, r.Namespace, r.Name, strconv.FormatBool(opts.Overwrite), strings.Join(changes, "\n"))
+	return s.confirm(ctx, ctr, message, token)
+}
+
+func metadataRequest(ctx context.Context, ctr mcp.CallToolRequest, field string) (common.Request, common.MetadataOptions, error) {
+	r := common.Request{
+		Context:   ctx,
+		Kind:      ctr.GetArguments()["kind"].(string),
+		Name:      ctr.GetArguments()["name"].(string),
+		Namespace: ctr.GetArguments()["namespace"].(string),
+	}
+	if v, ok := ctr.GetArguments()["api_version"].(string); ok {
+		r.APIVersion = v
+	}
+	changes, _ := ctr.GetArguments()[field].(map[string]interface{})
+	opts := common.MetadataOptions{Set: map[string]string{}}
+	for key, value := range changes {
+		switch v := value.(type) {
+		case nil:
+			opts.Remove = append(opts.Remove, key)
+		case string:
+			opts.Set[key] = v
+		default:
+			return r, opts, fmt.Errorf("invalid %s: the value of %s must be a string or null", field, key)
+		}
+	}
+	opts.Overwrite, _ = ctr.GetArguments()["overwrite"].(bool)
+	opts.DryRun, _ = ctr.GetArguments()["dry_run"].(bool)
+	return r, opts, nil
+}
+
+func (s *Server) resourceExplain(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r := common.Request{
+		Context:  ctx,
+		Kind:     ctr.GetArguments()["kind"].(string),
+		Progress: s.progress(ctx, ctr),
+	}
+	if v, ok := ctr.GetArguments()["api_version"].(string); ok {
+		r.APIVersion = v
+	}
+	field, _ := ctr.GetArguments()["field"].(string)
+	recursive, _ := ctr.GetArguments()["recursive"].(bool)
+	res, err := s.k8s.ResourceExplain(r, field, recursive)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to explain %s: %v", r.Kind, err)), nil
+	}
+	return mcp.NewToolResultText(res), nil
+}
+
 func (s *Server) ResourceDescribe(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	r := common.Request{
 		Context:   ctx,
-		Kind:      ctr.Params.Arguments["kind"].(string),
-		Name:      ctr.Params.Arguments["name"].(string),
-		Namespace: ctr.Params.Arguments["namespace"].(string),
+		Kind:      ctr.GetArguments()["kind"].(string),
+		Name:      ctr.GetArguments()["name"].(string),
+		Namespace: ctr.GetArguments()["namespace"].(string),
+	}
+	if v, ok := ctr.GetArguments()["api_version"].(string); ok {
+		r.APIVersion = v
 	}
 	res, err := s.k8s.ResourceDescribe(r)
 	if err != nil {
@@ -165,11 +570,92 @@ func (s *Server) ResourceDescribe(ctx context.Context, ctr mcp.CallToolRequest)
 	return mcp.NewToolResultText(res), nil
 }
 
+func (s *Server) resourceScale(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r := common.Request{
+		Context:   ctx,
+		Kind:      ctr.GetArguments()["kind"].(string),
+		Name:      ctr.GetArguments()["name"].(string),
+		Namespace: ctr.GetArguments()["namespace"].(string),
+	}
+	if v, ok := ctr.GetArguments()["api_version"].(string); ok {
+		r.APIVersion = v
+	}
+	opts := common.ScaleOptions{
+		Replicas: int32(ctr.GetArguments()["replicas"].(float64)),
+	}
+	if v, ok := ctr.GetArguments()["current_replicas"].(float64); ok {
+		current := int32(v)
+		opts.CurrentReplicas = &current
+	}
+	if v, ok := ctr.GetArguments()["wait"].(bool); ok {
+		opts.Wait = v
+		r.Progress = s.progress(ctx, ctr)
+	}
+	if v, ok := ctr.GetArguments()["timeout"].(float64); ok {
+		opts.Timeout = int(v)
+	}
+	if opts.Replicas == 0 {
+		if result, confirmed := s.confirmScaleToZero(ctx, ctr, r); !confirmed {
+			return result, nil
+		}
+	}
+	res, err := s.k8s.ResourceScale(r, opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to scale %s %s/%s: %v", r.Kind, r.Namespace, r.Name, err)), nil
+	}
+	return mcp.NewToolResultText(res), nil
+}
+
+func (s *Server) resourceWait(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r := common.Request{
+		Context:  ctx,
+		Kind:     ctr.GetArguments()["kind"].(string),
+		Progress: s.progress(ctx, ctr),
+	}
+	if v, ok := ctr.GetArguments()["api_version"].(string); ok {
+		r.APIVersion = v
+	}
+	if v, ok := ctr.GetArguments()["namespace"].(string); ok {
+		r.Namespace = v
+	}
+	if v, ok := ctr.GetArguments()["name"].(string); ok {
+		r.Name = v
+	}
+	if v, ok := ctr.GetArguments()["label_selector"].(string); ok {
+		r.LabelSelector = v
+	}
+	opts := common.WaitOptions{
+		For: ctr.GetArguments()["for"].(string),
+	}
+	if v, ok := ctr.GetArguments()["timeout"].(float64); ok {
+		opts.Timeout = int(v)
+	}
+	res, err := s.k8s.ResourceWait(r, opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to wait for %s: %v", r.Kind, err)), nil
+	}
+	return mcp.NewToolResultText(res), nil
+}
+
+// confirmScaleToZero asks for a confirmation before an object with running replicas is scaled to zero.
+func (s *Server) confirmScaleToZero(ctx context.Context, ctr mcp.CallToolRequest, r common.Request) (*mcp.CallToolResult, bool) {
+	impact, err := s.k8s.ScaleImpact(r)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to scale %s %s/%s: %v", r.Kind, r.Namespace, r.Name, err)), false
+	}
+	if impact.CurrentReplicas == 0 {
+		return nil, true
+	}
+	message := fmt.Sprintf("Scale %s %s/%s from %d to 0 replicas, terminating %d pods", impact.Kind, r.Namespace, r.Name, impact.CurrentReplicas, impact.Pods)
+	token := confirmToken("scale to zero", impact.Kind, r.Namespace, r.Name, fmt.Sprint(impact.CurrentReplicas))
+	return s.confirm(ctx, ctr, message, token)
+}
+
 func (s *Server) workloadResourceUsage(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	namespace := ctr.Params.Arguments["namespace"].(string)
-	kind := ctr.Params.Arguments["kind"].(string)
+	namespace := ctr.GetArguments()["namespace"].(string)
+	kind := ctr.GetArguments()["kind"].(string)
 	var name string
-	if v, ok := ctr.Params.Arguments["name"].(string); ok {
+	if v, ok := ctr.GetArguments()["name"].(string); ok {
 		name = v
 	}
 	res, err := s.k8s.WorkloadResourceUsage(common.Request{
@@ -184,6 +670,23 @@ func (s *Server) workloadResourceUsage(ctx context.Context, ctr mcp.CallToolRequ
 	return mcp.NewToolResultText(res), nil
 }
 
+// objectTemplate is the URI template of the objects read as MCP resources
+const objectTemplate = "k8s://{namespace}/{kind}/{name}"
+
+func (s *Server) readObject(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	r := common.Request{Context: ctx}
+	r.Namespace, _ = request.Params.Arguments["namespace"].(string)
+	r.Kind, _ = request.Params.Arguments["kind"].(string)
+	r.Name, _ = request.Params.Arguments["name"].(string)
+	res, err := s.k8s.ResourceGet(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s/%s: %v", r.Kind, r.Namespace, r.Name, err)
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{URI: request.Params.URI, MIMEType: "application/yaml", Text: res},
+	}, nil
+}
+
 // test prompt
 func (s *Server) getNamespacePrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
 	var name string
@@ -0,0 +1,198 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+)
+
+func (s *Server) initRollout() []server.ServerTool {
+	return []server.ServerTool{
+		{
+			Tool: mcp.NewTool("rollout status",
+				append(rolloutTargetOptions("Deployment", "StatefulSet", "DaemonSet"),
+					mcp.WithDescription("show the rollout status of a deployment, statefulset or daemonset"),
+					mcp.WithNumber("revision",
+						mcp.Description("the revision to check, only supported for Deployment (default latest)"),
+					),
+					mcp.WithBoolean("wait",
+						mcp.Description("wait until the rollout is finished"),
+					),
+					mcp.WithNumber("timeout",
+						mcp.Description("the wait timeout in seconds (default 300)"),
+					),
+				)...,
+			),
+			Handler: s.rolloutStatus,
+		},
+		{
+			Tool: mcp.NewTool("rollout history",
+				append(rolloutTargetOptions("Deployment", "StatefulSet", "DaemonSet"),
+					mcp.WithDescription("list rollout revisions with their change-cause and the pod template diff between revisions"),
+					mcp.WithNumber("revision",
+						mcp.Description("show the details and diff of a single revision"),
+					),
+				)...,
+			),
+			Handler: s.rolloutHistory,
+		},
+		{
+			Tool: mcp.NewTool("rollout undo",
+				append(rolloutTargetOptions("Deployment", "StatefulSet", "DaemonSet"),
+					mcp.WithDescription("roll back a deployment, statefulset or daemonset to a previous revision"),
+					mcp.WithNumber("to_revision",
+						mcp.Description("the revision to roll back to (default previous revision)"),
+					),
+					confirmArgument(),
+				)...,
+			),
+			Handler: s.rolloutUndo,
+		},
+		{
+			Tool: mcp.NewTool("rollout restart",
+				append(rolloutTargetOptions("Deployment", "StatefulSet", "DaemonSet"),
+					mcp.WithDescription("restart the pods of a deployment, statefulset or daemonset with a rolling update"),
+					confirmArgument(),
+				)...,
+			),
+			Handler: s.rolloutRestart,
+		},
+		{
+			Tool: mcp.NewTool("rollout pause",
+				append(rolloutTargetOptions("Deployment"),
+					mcp.WithDescription("pause a deployment rollout"),
+				)...,
+			),
+			Handler: s.rolloutPause,
+		},
+		{
+			Tool: mcp.NewTool("rollout resume",
+				append(rolloutTargetOptions("Deployment"),
+					mcp.WithDescription("resume a paused deployment rollout"),
+				)...,
+			),
+			Handler: s.rolloutResume,
+		},
+	}
+}
+
+// rolloutTargetOptions returns the arguments identifying the workload shared by the rollout tools
+func rolloutTargetOptions(kinds ...string) []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithString("kind",
+			mcp.Description("the workload kind"),
+			mcp.Enum(kinds...),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("the namespace of the workload"),
+			mcp.Required(),
+		),
+		mcp.WithString("name",
+			mcp.Description("the name of the workload"),
+			mcp.Required(),
+		),
+	}
+}
+
+func rolloutRequest(ctx context.Context, ctr mcp.CallToolRequest) common.Request {
+	return common.Request{
+		Context:   ctx,
+		Kind:      ctr.GetArguments()["kind"].(string),
+		Namespace: ctr.GetArguments()["namespace"].(string),
+		Name:      ctr.GetArguments()["name"].(string),
+	}
+}
+
+func (s *Server) rolloutStatus(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r := rolloutRequest(ctx, ctr)
+	r.Progress = s.progress(ctx, ctr)
+	var revision int64
+	if v, ok := ctr.GetArguments()["revision"].(float64); ok {
+		revision = int64(v)
+	}
+	wait, _ := ctr.GetArguments()["wait"].(bool)
+	var timeout time.Duration
+	if v, ok := ctr.GetArguments()["timeout"].(float64); ok {
+		timeout = time.Duration(v) * time.Second
+	}
+	res, err := s.k8s.RolloutStatus(r, revision, wait, timeout)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get rollout status of %s %s/%s: %v", r.Kind, r.Namespace, r.Name, err)), nil
+	}
+	return mcp.NewToolResultText(res), nil
+}
+
+func (s *Server) rolloutHistory(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r := rolloutRequest(ctx, ctr)
+	var revision int64
+	if v, ok := ctr.GetArguments()["revision"].(float64); ok {
+		revision = int64(v)
+	}
+	res, err := s.k8s.RolloutHistory(r, revision)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get rollout history of %s %s/%s: %v", r.Kind, r.Namespace, r.Name, err)), nil
+	}
+	return mcp.NewToolResultText(res), nil
+}
+
+func (s *Server) rolloutUndo(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r := rolloutRequest(ctx, ctr)
+	var toRevision int64
+	if v, ok := ctr.GetArguments()["to_revision"].(float64); ok {
+		toRevision = int64(v)
+	}
+	if s.protected(r.Namespace) {
+		message := fmt.Sprintf("Roll back %s %s/%s in the protected namespace %s to the previous revision", r.Kind, r.Namespace, r.Name, r.Namespace)
+		if toRevision > 0 {
+			message = fmt.Sprintf("Roll back %s %s/%s in the protected namespace %s to revision %d", r.Kind, r.Namespace, r.Name, r.Namespace, toRevision)
+		}
+		token := confirmToken("rollout undo", r.Kind, r.Namespace, r.Name, strconv.FormatInt(toRevision, 10))
+		if result, confirmed := s.confirm(ctx, ctr, message, token); !confirmed {
+			return result, nil
+		}
+	}
+	res, err := s.k8s.RolloutUndo(r, toRevision)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to undo rollout of %s %s/%s: %v", r.Kind, r.Namespace, r.Name, err)), nil
+	}
+	return mcp.NewToolResultText(res), nil
+}
+
+func (s *Server) rolloutRestart(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r := rolloutRequest(ctx, ctr)
+	if s.protected(r.Namespace) {
+		message := fmt.Sprintf("Restart the pods of %s %s/%s in the protected namespace %s", r.Kind, r.Namespace, r.Name, r.Namespace)
+		if result, confirmed := s.confirm(ctx, ctr, message, confirmToken("rollout restart", r.Kind, r.Namespace, r.Name)); !confirmed {
+			return result, nil
+		}
+	}
+	res, err := s.k8s.RolloutRestart(r)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to restart %s %s/%s: %v", r.Kind, r.Namespace, r.Name, err)), nil
+	}
+	return mcp.NewToolResultText(res), nil
+}
+
+func (s *Server) rolloutPause(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r := rolloutRequest(ctx, ctr)
+	res, err := s.k8s.RolloutPause(r)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to pause %s %s/%s: %v", r.Kind, r.Namespace, r.Name, err)), nil
+	}
+	return mcp.NewToolResultText(res), nil
+}
+
+func (s *Server) rolloutResume(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r := rolloutRequest(ctx, ctr)
+	res, err := s.k8s.RolloutResume(r)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to resume %s %s/%s: %v", r.Kind, r.Namespace, r.Name, err)), nil
+	}
+	return mcp.NewToolResultText(res), nil
+}
@@ -1,45 +1,153 @@
 package mcp
 
 import (
+	"context"
 	"slices"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/history"
 	"github.com/wenhuwang/mcp-k8s-eye/pkg/k8s"
 )
 
 type Server struct {
-	server *server.MCPServer
-	k8s    *k8s.Kubernetes
+	server              *server.MCPServer
+	k8s                 *k8s.Kubernetes
+	history             *history.Store
+	protectedNamespaces []string
+
+	// stopHistory stops recording the finding history, historyDone is closed once it stopped
+	stopHistory context.CancelFunc
+	historyDone chan struct{}
+}
+
+// Options configures the optional features of the server.
+type Options struct {
+	// RulesFile is a YAML file of policy rules evaluated by the policy analyzer
+	RulesFile string
+	// HistoryFile is the bbolt file the findings of periodic cluster scans are recorded in, the scans and the
+	// history tools are disabled when it is empty
+	HistoryFile string
+	// HistoryInterval is the time between two recorded cluster scans
+	HistoryInterval time.Duration
+	// HistoryRetention is how long resolved findings are kept in the history file
+	HistoryRetention time.Duration
+	// Cache serves the reads of the analyzers and the resource tools from informers
+	Cache bool
+	// ProtectedNamespaces are the namespaces applying or patching objects in needs a confirmation, nil means
+	// kube-system, kube-public and kube-node-lease
+	ProtectedNamespaces []string
+}
+
+// Option sets an optional feature of the server.
+type Option func(*Options)
+
+// WithRulesFile loads the policy rules of the YAML file at path.
+func WithRulesFile(path string) Option {
+	return func(o *Options) {
+		o.RulesFile = path
+	}
+}
+
+// WithHistory records the findings of a cluster scan run every interval in the bbolt file at path.
+func WithHistory(path string, interval time.Duration) Option {
+	return func(o *Options) {
+		o.HistoryFile = path
+		o.HistoryInterval = interval
+	}
+}
+
+// WithHistoryRetention deletes the findings resolved more than retention ago from the history file.
+func WithHistoryRetention(retention time.Duration) Option {
+	return func(o *Options) {
+		o.HistoryRetention = retention
+	}
+}
+
+// WithCache serves the reads of the analyzers and the resource tools from informers.
+func WithCache(enabled bool) Option {
+	return func(o *Options) {
+		o.Cache = enabled
+	}
+}
+
+// WithProtectedNamespaces asks for a confirmation before objects are applied or patched in namespaces.
+func WithProtectedNamespaces(namespaces []string) Option {
+	return func(o *Options) {
+		o.ProtectedNamespaces = namespaces
+	}
 }
 
-func NewServer(name, version string) (*Server, error) {
+func NewServer(name, version string, opts ...Option) (*Server, error) {
+	var options Options
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	s := &Server{
-		server: server.NewMCPServer(
-			name,
-			version,
-			server.WithResourceCapabilities(true, true),
-			server.WithPromptCapabilities(true),
-			server.WithLogging(),
-		),
+		protectedNamespaces: options.ProtectedNamespaces,
+	}
+	s.server = server.NewMCPServer(
+		name,
+		version,
+		server.WithResourceCapabilities(true, true),
+		server.WithPromptCapabilities(true),
+		server.WithLogging(),
+		server.WithElicitation(),
+		server.WithCompletions(),
+		server.WithPromptCompletionProvider(completionProvider{s}),
+		server.WithResourceCompletionProvider(completionProvider{s}),
+	)
+	if s.protectedNamespaces == nil {
+		s.protectedNamespaces = defaultProtectedNamespaces
 	}
 	k8s, err := k8s.NewKubernetes()
 	if err != nil {
 		return nil, err
 	}
 	s.k8s = k8s
+	if options.Cache {
+		s.k8s.EnableCache()
+	}
+	if options.RulesFile != "" {
+		if err := s.k8s.LoadPolicyRules(options.RulesFile); err != nil {
+			return nil, err
+		}
+	}
+	if options.HistoryFile != "" {
+		if s.history, err = history.Open(options.HistoryFile); err != nil {
+			return nil, err
+		}
+		interval := options.HistoryInterval
+		if interval <= 0 {
+			interval = defaultHistoryInterval
+		}
+		retention := options.HistoryRetention
+		if retention <= 0 {
+			retention = defaultHistoryRetention
+		}
+		var ctx context.Context
+		ctx, s.stopHistory = context.WithCancel(context.Background())
+		s.historyDone = make(chan struct{})
+		go func() {
+			defer close(s.historyDone)
+			s.recordHistory(ctx, interval, retention)
+		}()
+	}
 
 	s.server.AddTools(slices.Concat(
 		s.initResource(),
+		s.initDiscovery(),
 		s.initPod(),
 		s.initDeployment(),
+		s.initRollout(),
 		s.initService(),
-		s.initStatefulSet(),
-		s.initNode(),
-		s.initIngress(),
-		s.initCronJob(),
-		s.initNetworkPolicy(),
-		s.initWebhook(),
+		s.initEvents(),
+		s.initAnalyzers(),
+		s.initCluster(),
+		s.initHistory(),
+		s.initCache(),
 	)...)
 
 	// test prompt
@@ -50,9 +158,24 @@ func NewServer(name, version string) (*Server, error) {
 		),
 	), s.getNamespacePrompt)
 
+	s.server.AddResourceTemplate(mcp.NewResourceTemplate(objectTemplate, "kubernetes object",
+		mcp.WithTemplateDescription("the YAML of an object, cluster-scoped objects ignore the namespace"),
+		mcp.WithTemplateMIMEType("application/yaml"),
+	), s.readObject)
+
 	return s, nil
 }
 
+// Close stops recording the finding history and closes the history file.
+func (s *Server) Close() error {
+	if s.history == nil {
+		return nil
+	}
+	s.stopHistory()
+	<-s.historyDone
+	return s.history.Close()
+}
+
 func (s *Server) ServeStdio() error {
 	return server.ServeStdio(s.server)
 }
@@ -1,7 +1,16 @@
 package mcp
 
 import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/history"
 )
 
 func TestNewServer(t *testing.T) {
@@ -22,3 +31,168 @@ func TestNewServer(t *testing.T) {
 		}
 	})
 }
+
+func TestServerClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	server, err := NewServer("test-server", "1.0.0", WithHistory(path, time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	if err := server.Close(); err != nil {
+		t.Fatalf("Failed to close server: %v", err)
+	}
+	// the history file is unlocked once the store is closed
+	store, err := history.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open the history file after closing the server: %v", err)
+	}
+	_ = store.Close()
+}
+
+func TestInitAnalyzers(t *testing.T) {
+	server, err := NewServer("test-server", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	tools := map[string][]string{}
+	for _, tool := range server.initAnalyzers() {
+		for property := range tool.Tool.InputSchema.Properties {
+			tools[tool.Tool.Name] = append(tools[tool.Tool.Name], property)
+		}
+	}
+	if len(tools) != len(server.k8s.Analyzers()) {
+		t.Errorf("expected a tool per analyzer, got %d tools for %d analyzers", len(tools), len(server.k8s.Analyzers()))
+	}
+	if props := tools["pod analyze"]; !slices.Contains(props, "namespace") || !slices.Contains(props, "min_severity") {
+		t.Errorf("pod analyze should take a namespace and a minimum severity, got %v", props)
+	}
+	if props := tools["ingress analyze"]; slices.Contains(props, "label-selector") {
+		t.Errorf("ingress analyze should not take a label selector its analyzer is not given, got %v", props)
+	}
+	if _, ok := tools["node analyze"]; !ok {
+		t.Error("node analyze tool is missing")
+	}
+	for _, tool := range server.initAnalyzers() {
+		if _, ok := tool.Tool.OutputSchema.Properties["results"]; !ok {
+			t.Errorf("%s should declare an output schema with results", tool.Tool.Name)
+		}
+	}
+}
+
+func TestAnalysisResult(t *testing.T) {
+	result, err := analysisResult("pod", []common.Result{{
+		Kind:  "Pod",
+		Name:  "default/api",
+		Error: []common.Failure{{ID: "POD_CRASHLOOP", Severity: common.SeverityCritical, Text: "Container api is in CrashLoopBackOff"}},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to build result: %v", err)
+	}
+
+	report, ok := result.StructuredContent.(common.AnalysisReport)
+	if !ok || report.Summary.Objects != 1 || report.Summary.Critical != 1 {
+		t.Errorf("unexpected structured content %#v", result.StructuredContent)
+	}
+	if len(result.Content) != 2 {
+		t.Fatalf("expected a summary and a JSON content block, got %d blocks", len(result.Content))
+	}
+	summary := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(summary, "- [critical] Pod default/api: Container api is in CrashLoopBackOff") {
+		t.Errorf("summary should list the failure, got %q", summary)
+	}
+	if !strings.HasPrefix(result.Content[1].(mcp.TextContent).Text, `{"analyzer":"pod"`) {
+		t.Errorf("second content block should hold the JSON report, got %q", result.Content[1].(mcp.TextContent).Text)
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	s := &Server{}
+	request := func(confirm string) mcp.CallToolRequest {
+		var ctr mcp.CallToolRequest
+		ctr.Params.Arguments = map[string]interface{}{"confirm": confirm}
+		return ctr
+	}
+	token := confirmToken("resource delete", "default", "web")
+
+	t.Run("Asks for the token without elicitation", func(t *testing.T) {
+		result, confirmed := s.confirm(context.Background(), request(""), "Delete 1 objects", token)
+		if confirmed {
+			t.Fatal("the operation should not be confirmed")
+		}
+		if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "repeat the call with confirm set to "+token) {
+			t.Errorf("the result should carry the token, got %q", text)
+		}
+	})
+
+	t.Run("Matching token confirms", func(t *testing.T) {
+		if result, confirmed := s.confirm(context.Background(), request(token), "Delete 1 objects", token); !confirmed || result != nil {
+			t.Error("the operation should be confirmed")
+		}
+	})
+
+	t.Run("Stale token is rejected", func(t *testing.T) {
+		result, confirmed := s.confirm(context.Background(), request(confirmToken("resource delete", "default", "api")), "Delete 1 objects", token)
+		if confirmed || !result.IsError {
+			t.Error("a token of another operation should be rejected")
+		}
+		if text := result.Content[0].(mcp.TextContent).Text; strings.Contains(text, token) {
+			t.Errorf("the rejection should not reveal the expected token, got %q", text)
+		}
+	})
+}
+
+func TestConfirmWrites(t *testing.T) {
+	// the writes are not confirmed, they return before reaching the cluster
+	s := &Server{protectedNamespaces: defaultProtectedNamespaces}
+	request := func(arguments map[string]interface{}) mcp.CallToolRequest {
+		var ctr mcp.CallToolRequest
+		ctr.Params.Arguments = arguments
+		return ctr
+	}
+	target := map[string]interface{}{"kind": "Deployment", "namespace": "kube-system", "name": "coredns"}
+	tests := []struct {
+		name    string
+		handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
+		args    map[string]interface{}
+		message string
+	}{
+		{"pod file write", s.podFileWrite, map[string]interface{}{"namespace": "default", "pod": "web", "path": "/tmp/x", "content": "data"}, "Write 4 characters of text content to /tmp/x in pod default/web"},
+		{"resource label", s.resourceLabel, map[string]interface{}{"kind": "Deployment", "namespace": "kube-system", "name": "coredns", "labels": map[string]interface{}{"tier": "dns", "app": nil}}, "- remove app\n- set tier=dns"},
+		{"resource annotate", s.resourceAnnotate, map[string]interface{}{"kind": "Deployment", "namespace": "kube-system", "name": "coredns", "annotations": map[string]interface{}{"owner": "platform"}}, "- set owner=platform"},
+		{"rollout undo", s.rolloutUndo, target, "Roll back Deployment kube-system/coredns in the protected namespace kube-system to the previous revision"},
+		{"rollout restart", s.rolloutRestart, target, "Restart the pods of Deployment kube-system/coredns"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.handler(context.Background(), request(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			text := result.Content[0].(mcp.TextContent).Text
+			if !strings.Contains(text, tt.message) || !strings.Contains(text, "needs a confirmation") {
+				t.Errorf("the write should wait for a confirmation, got %q", text)
+			}
+		})
+	}
+}
+
+func TestProgress(t *testing.T) {
+	s, err := NewServer("test-server", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	var ctr mcp.CallToolRequest
+	if s.progress(context.Background(), ctr) != nil {
+		t.Error("progress should be nil without a progress token")
+	}
+
+	ctr.Params.Meta = &mcp.Meta{ProgressToken: "token"}
+	progress := s.progress(context.Background(), ctr)
+	if progress == nil {
+		t.Fatal("progress should be set with a progress token")
+	}
+	// without a client session the notification is dropped
+	progress(1, 2, "analyzed 1/2 namespaces")
+}
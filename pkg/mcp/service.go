@@ -6,27 +6,51 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/wenhuwang/mcp-k8s-eye/pkg/common"
 )
 
 func (s *Server) initService() []server.ServerTool {
 	return []server.ServerTool{
 		{
-			Tool: mcp.NewTool("service analyze",
-				mcp.WithDescription("analyze service status"),
+			Tool: mcp.NewTool("service probe",
+				mcp.WithDescription("probe a ready pod backing the service over port-forward with an HTTP(S) or TCP check"),
 				mcp.WithString("namespace",
-					mcp.Description("the namespace to analyze services in"),
+					mcp.Description("the namespace of the service"),
+					mcp.Required(),
+				),
+				mcp.WithString("service",
+					mcp.Description("the service to probe"),
+					mcp.Required(),
+				),
+				mcp.WithString("port",
+					mcp.Description("the service port number or name, optional when the service has a single port"),
+				),
+				mcp.WithString("scheme",
+					mcp.Description("the probe scheme (default http)"),
+					mcp.Enum("http", "https", "tcp"),
+				),
+				mcp.WithString("path",
+					mcp.Description("the HTTP path to request (default /)"),
+				),
+				mcp.WithNumber("timeout",
+					mcp.Description("the probe timeout in seconds (default 10)"),
 				),
 			),
-			Handler: s.serviceAnalyze,
+			Handler: s.serviceProbe,
 		},
 	}
 }
 
-func (s *Server) serviceAnalyze(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	ns := ctr.Params.Arguments["namespace"].(string)
-	res, err := s.k8s.AnalyzeService(ctx, ns)
+func (s *Server) serviceProbe(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ns := ctr.GetArguments()["namespace"].(string)
+	svc := ctr.GetArguments()["service"].(string)
+	res, err := s.k8s.ServiceProbe(common.Request{
+		Context:   ctx,
+		Namespace: ns,
+		Name:      svc,
+	}, probeOptions(ctr))
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to analyze services in namespace %s: %v", ns, err)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("failed to probe service %s/%s: %v", ns, svc, err)), nil
 	}
 	return mcp.NewToolResultText(res), nil
 }
@@ -11,10 +11,10 @@ import (
 	"sigs.k8s.io/yaml"
 )
 
-func FetchLatestEvent(kubernetesClient kubernetes.Interface, namespace string, name string) (*v1.Event, error) {
+func FetchLatestEvent(ctx context.Context, kubernetesClient kubernetes.Interface, namespace string, name string) (*v1.Event, error) {
 
 	// get the list of events
-	events, err := kubernetesClient.CoreV1().Events(namespace).List(context.TODO(),
+	events, err := kubernetesClient.CoreV1().Events(namespace).List(ctx,
 		metav1.ListOptions{
 			FieldSelector: "involvedObject.name=" + name,
 		})
@@ -39,55 +39,6 @@ func FetchLatestEvent(kubernetesClient kubernetes.Interface, namespace string, n
 	return latestEvent, nil
 }
 
-func GetParent(client kubernetes.Interface, meta metav1.ObjectMeta) (string, bool) {
-	if meta.OwnerReferences != nil {
-		for _, owner := range meta.OwnerReferences {
-			switch owner.Kind {
-			case "ReplicaSet":
-				rs, err := client.AppsV1().ReplicaSets(meta.Namespace).Get(context.Background(), owner.Name, metav1.GetOptions{})
-				if err != nil {
-					return "", false
-				}
-				if rs.OwnerReferences != nil {
-					return GetParent(client, rs.ObjectMeta)
-				}
-				return "ReplicaSet/" + rs.Name, true
-
-			case "Deployment":
-				dep, err := client.AppsV1().Deployments(meta.Namespace).Get(context.Background(), owner.Name, metav1.GetOptions{})
-				if err != nil {
-					return "", false
-				}
-				if dep.OwnerReferences != nil {
-					return GetParent(client, dep.ObjectMeta)
-				}
-				return "Deployment/" + dep.Name, true
-
-			case "StatefulSet":
-				sts, err := client.AppsV1().StatefulSets(meta.Namespace).Get(context.Background(), owner.Name, metav1.GetOptions{})
-				if err != nil {
-					return "", false
-				}
-				if sts.OwnerReferences != nil {
-					return GetParent(client, sts.ObjectMeta)
-				}
-				return "StatefulSet/" + sts.Name, true
-
-			case "DaemonSet":
-				ds, err := client.AppsV1().DaemonSets(meta.Namespace).Get(context.Background(), owner.Name, metav1.GetOptions{})
-				if err != nil {
-					return "", false
-				}
-				if ds.OwnerReferences != nil {
-					return GetParent(client, ds.ObjectMeta)
-				}
-				return "DaemonSet/" + ds.Name, true
-			}
-		}
-	}
-	return "", false
-}
-
 func Marshal(v any) (string, error) {
 	switch t := v.(type) {
 	case []unstructured.Unstructured:
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -29,7 +30,18 @@ var rootCmd = &cobra.Command{
 
   # Start SSE server
   mcp-k8s-eye --sse
-}
+
+  # load custom policy rules for the policy analyze tool
+  mcp-k8s-eye --rules rules.yaml
+
+  # serve the analyzers and the resource tools from an informer cache
+  mcp-k8s-eye --cache
+
+  # record the findings of a cluster scan every 5 minutes for the finding history tools, keeping them a week
+  mcp-k8s-eye --history-file findings.db --history-interval 5m --history-retention 168h
+
+  # only ask for a confirmation before applying to kube-system and production
+  mcp-k8s-eye --protected-namespaces kube-system,production
 
   # TODO: add more examples`,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -37,10 +49,21 @@ var rootCmd = &cobra.Command{
 			fmt.Println(common.Version)
 			return
 		}
-		mcpServer, err := mcp.NewServer(common.ProjectName, common.Version)
+		mcpServer, err := mcp.NewServer(common.ProjectName, common.Version,
+			mcp.WithRulesFile(viper.GetString("rules")),
+			mcp.WithHistory(viper.GetString("history-file"), viper.GetDuration("history-interval")),
+			mcp.WithHistoryRetention(viper.GetDuration("history-retention")),
+			mcp.WithCache(viper.GetBool("cache")),
+			mcp.WithProtectedNamespaces(viper.GetStringSlice("protected-namespaces")),
+		)
 		if err != nil {
 			log.Fatalf("Failed to create MCP server: %v", err)
 		}
+		defer func() {
+			if err := mcpServer.Close(); err != nil {
+				log.Printf("Failed to close MCP server: %v", err)
+			}
+		}()
 
 		if viper.GetBool("sse") {
 			sse := mcpServer.ServeSSE()
@@ -68,6 +91,12 @@ func init() {
 	rootCmd.Flags().BoolP("version", "v", false, "Print version information and quit")
 	rootCmd.Flags().BoolP("sse", "s", false, "Start SSE server(default port is 8080)")
 	rootCmd.Flags().IntP("port", "p", 0, "Start SSE server with specified port")
+	rootCmd.Flags().String("rules", "", "YAML file of custom policy rules evaluated by the policy analyze tool")
+	rootCmd.Flags().String("history-file", "", "Record the findings of periodic cluster scans in this file and enable the finding history tools")
+	rootCmd.Flags().Duration("history-interval", 5*time.Minute, "Time between two cluster scans recorded in the history file")
+	rootCmd.Flags().Duration("history-retention", 7*24*time.Hour, "How long the findings resolved are kept in the history file")
+	rootCmd.Flags().Bool("cache", false, "Serve the reads of the analyzers and the resource tools from an informer cache")
+	rootCmd.Flags().StringSlice("protected-namespaces", []string{"kube-system", "kube-public", "kube-node-lease"}, "Namespaces applying or patching objects in needs a confirmation")
 	_ = viper.BindPFlags(rootCmd.Flags())
 }
 